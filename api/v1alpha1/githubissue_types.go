@@ -28,10 +28,330 @@ type GithubIssueSpec struct {
 	// INSERT ADDITIONAL SPEC FIELDS - desired state of cluster
 	// Important: Run "make" to regenerate code after modifying this file
 
+	// Provider is the issue tracker the issue is filed in, the provider of the repository referenced by
+	// repositoryRef takes precedence. For GitLab, repo is the API URL of the project, e.g.
+	// https://gitlab.example.com/api/v4/projects/group%2Fproject, and the GITLAB_TOKEN environment variable
+	// of the operator is used as the token. For Jira, repo is the API URL of the project, e.g.
+	// https://example.atlassian.net/rest/api/2/project/OPS, and JIRA_TOKEN is used as the token. Gitea also
+	// covers Forgejo, repo is the API URL of the repository, e.g. https://gitea.example.com/api/v1/repos/owner/repo,
+	// and GITEA_TOKEN is used as the token.
+	// Comments, the comment mirror, target autoClose, deduplication, projects and parents are only supported for
+	// GitHub, the admission webhook rejects them for other providers.
+	// +kubebuilder:validation:Enum=GitHub;GitLab;Jira;Gitea
+	// +kubebuilder:default=GitHub
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
 	// Must fields of GithubIssue. Edit githubissue_types.go to remove/update to add more fileds.
-	Repo        string `json:"repo,omitempty"`
+	Repo string `json:"repo,omitempty"`
+
+	// RepositoryRef references the GithubRepository or ClusterGithubRepository the issue is filed in.
+	// When set it takes precedence over repo and the repository credentials replace the global token.
+	// +optional
+	RepositoryRef *RepositoryReference `json:"repositoryRef,omitempty"`
+
 	Title       string `json:"title,omitempty"`
 	Description string `json:"description,omitempty"`
+
+	// Labels are added to the issue next to the default labels of the repository.
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// State is the desired state of the issue. Closed closes the issue and keeps it closed,
+	// setting it back to Open reopens it.
+	// +kubebuilder:validation:Enum=Open;Closed
+	// +kubebuilder:default=Open
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// Comments are posted on the GitHub issue and kept in sync with this list.
+	// Entries removed from the list are deleted from the issue.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Comments []GithubIssueComment `json:"comments,omitempty"`
+
+	// CommentMirror copies the comments people leave on the GitHub issue back into the cluster.
+	// +optional
+	CommentMirror *CommentMirrorSpec `json:"commentMirror,omitempty"`
+
+	// Template renders the issue title and body with Go text/template on every reconcile,
+	// so the issue stays current as the referenced data changes.
+	// +optional
+	Template *IssueTemplateSpec `json:"template,omitempty"`
+
+	// TargetRef points at the Kubernetes object the issue describes.
+	// +optional
+	TargetRef *TargetReference `json:"targetRef,omitempty"`
+
+	// Deduplication files GithubIssues of the same namespace sharing a fingerprint in the same repository as a
+	// single GitHub issue, which is only closed once the last of them is gone.
+	// +optional
+	Deduplication *DeduplicationSpec `json:"deduplication,omitempty"`
+
+	// Project adds the GitHub issue to a GitHub Projects (v2) board and keeps the custom field values of its
+	// project item in sync.
+	// +optional
+	Project *ProjectSpec `json:"project,omitempty"`
+
+	// Parent makes the GitHub issue a sub-task of the issue of another GithubIssue in the same namespace.
+	// The parent reports the progress of its children in status.children. A GithubIssue cannot be its own
+	// ancestor, and chains of parents are at most eight GithubIssues long.
+	// +optional
+	Parent *ParentReference `json:"parent,omitempty"`
+
+	// Mode selects how the GitHub issue is reconciled. Enforce creates the issue and overwrites its title and
+	// body when they drift from the spec. CreateOnly creates the issue but only reports later drift.
+	// ObserveOnly never writes to GitHub, it reports the drift of the issue referenced by the
+	// github-issue.kubebuilder.io/issue-number annotation in the Drifted condition and status.drift.
+	// +kubebuilder:validation:Enum=Enforce;ObserveOnly;CreateOnly
+	// +kubebuilder:default=Enforce
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// Suspend stops all GitHub writes for the GithubIssue, including closing the issue on deletion,
+	// until it is set back to false. The github-issue.kubebuilder.io/paused: "true" annotation has the same effect.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// ResyncInterval is how often the GitHub issue is compared with the spec, overriding the --resync-period
+	// of the manager. Intervals below 10s are raised to 10s.
+	// +optional
+	ResyncInterval *metav1.Duration `json:"resyncInterval,omitempty"`
+}
+
+// DryRunRequest is a GitHub write skipped because the manager runs in dry-run mode
+type DryRunRequest struct {
+	// Method is the HTTP method of the request.
+	Method string `json:"method"`
+
+	// URL is the GitHub API URL of the request.
+	URL string `json:"url"`
+
+	// Payload is the JSON body of the request.
+	// +optional
+	Payload string `json:"payload,omitempty"`
+}
+
+// DriftedField is a field of the GitHub issue that differs from the spec
+type DriftedField struct {
+	// Field is the name of the issue field, title or body.
+	Field string `json:"field"`
+
+	// Desired is the value rendered from the spec.
+	// +optional
+	Desired string `json:"desired,omitempty"`
+
+	// Actual is the value found on GitHub.
+	// +optional
+	Actual string `json:"actual,omitempty"`
+}
+
+// DeduplicationSpec defines how GithubIssues describing the same problem share a GitHub issue
+type DeduplicationSpec struct {
+	// Fingerprint identifies the problem the GithubIssue describes. Defaults to the
+	// github-issue.kubebuilder.io/fingerprint label, and to the title when the label is not set.
+	// +optional
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// Occurrences selects how GithubIssues sharing the issue are recorded on it. Count keeps an
+	// occurrence count at the end of the issue body, Comment adds a comment for every further GithubIssue.
+	// +kubebuilder:validation:Enum=Count;Comment
+	// +kubebuilder:default=Count
+	// +optional
+	Occurrences string `json:"occurrences,omitempty"`
+}
+
+// TargetReference points at the Kubernetes object a GithubIssue describes and
+// controls whether the issue follows the object's lifecycle
+type TargetReference struct {
+	ObjectReference `json:",inline"`
+
+	// AutoClose closes the GitHub issue when the target is deleted, becomes healthy, or either.
+	// The issue is reopened when a healthy target becomes unhealthy again.
+	// +kubebuilder:validation:Enum=Never;Deleted;Healthy;HealthyOrDeleted
+	// +kubebuilder:default=Never
+	// +optional
+	AutoClose string `json:"autoClose,omitempty"`
+
+	// HealthyConditionType is the status condition of the target that is True when it is healthy.
+	// +kubebuilder:default=Ready
+	// +optional
+	HealthyConditionType string `json:"healthyConditionType,omitempty"`
+}
+
+// TargetStatus reports the observed state of the object a GithubIssue describes
+type TargetStatus struct {
+	ObjectReference `json:",inline"`
+
+	// State of the target, one of Healthy, Unhealthy or Deleted.
+	State string `json:"state"`
+}
+
+// IssueTemplateSpec defines Go text/template sources for the issue title and body.
+// Templates are executed with .Issue set to the GithubIssue, .Data set to the merged
+// data sources and .Object set to the content of the referenced Kubernetes object.
+type IssueTemplateSpec struct {
+	// Title is the template of the issue title, spec.title is used when empty.
+	// +optional
+	Title string `json:"title,omitempty"`
+
+	// Body is the template of the issue body, spec.description is used when empty.
+	// +optional
+	Body string `json:"body,omitempty"`
+
+	// Data holds static values available to the templates as .Data.
+	// +optional
+	Data map[string]string `json:"data,omitempty"`
+
+	// ConfigMapName is the name of a ConfigMap in the GithubIssue namespace whose data is merged into .Data.
+	// Keys in data take precedence over the ConfigMap.
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// ObjectRef references a Kubernetes object of the GithubIssue namespace available to the templates as .Object.
+	// Objects of other namespaces are not read. Only Pods, Deployments, Jobs, Nodes, Events and ConfigMaps can be
+	// referenced.
+	// +optional
+	ObjectRef *ObjectReference `json:"objectRef,omitempty"`
+}
+
+// ObjectReference points at any Kubernetes object
+type ObjectReference struct {
+	// APIVersion of the referenced object, e.g. "v1" or "apps/v1".
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the referenced object, e.g. "Pod".
+	Kind string `json:"kind"`
+
+	// Name of the referenced object.
+	Name string `json:"name"`
+
+	// Namespace of the referenced object, defaults to the GithubIssue namespace. Objects of other namespaces
+	// are not read. Ignored for cluster scoped kinds.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// RepositoryReference references a GithubRepository in the same namespace or a ClusterGithubRepository
+type RepositoryReference struct {
+	// Kind of the referenced repository.
+	// +kubebuilder:validation:Enum=GithubRepository;ClusterGithubRepository
+	// +kubebuilder:default=GithubRepository
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the referenced repository.
+	Name string `json:"name"`
+}
+
+// GithubIssueComment describes a comment the operator owns on the GitHub issue
+type GithubIssueComment struct {
+	// Name identifies the comment within the GithubIssue, it is not sent to GitHub.
+	Name string `json:"name"`
+
+	// Body is the markdown content of the comment.
+	Body string `json:"body"`
+}
+
+// CommentMirrorSpec configures how GitHub issue comments are recorded in the cluster
+type CommentMirrorSpec struct {
+	// Limit is the number of most recent comments to keep.
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Limit int `json:"limit,omitempty"`
+
+	// ConfigMapName is the name of a ConfigMap in the GithubIssue namespace to record the comments in.
+	// A ConfigMap created by the operator is deleted with the GithubIssue, an existing one is kept.
+	// When empty the comments are recorded in status.mirroredComments.
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+}
+
+// MirroredComment is a comment read from the GitHub issue
+type MirroredComment struct {
+	// ID is the GitHub ID of the comment.
+	ID int64 `json:"id"`
+
+	// Author is the login of the GitHub user who wrote the comment.
+	Author string `json:"author"`
+
+	// CreatedAt is the time the comment was posted.
+	CreatedAt metav1.Time `json:"createdAt"`
+
+	// UpdatedAt is the time the comment was last edited.
+	// +optional
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+
+	// Body is the markdown content of the comment.
+	Body string `json:"body"`
+}
+
+// ProjectSpec selects the GitHub Projects (v2) board of the issue and the values of its custom fields
+type ProjectSpec struct {
+	// Owner is the login of the organization or user owning the project.
+	// +kubebuilder:validation:MinLength=1
+	Owner string `json:"owner"`
+
+	// Number of the project, as in https://github.com/orgs/<owner>/projects/<number>.
+	// +kubebuilder:validation:Minimum=1
+	Number int `json:"number"`
+
+	// Fields are the custom field values of the project item, e.g. Status, Priority or Iteration.
+	// Values changed on the board are set back, fields not listed are left alone.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Fields []ProjectFieldValue `json:"fields,omitempty"`
+}
+
+// ProjectFieldValue is the value of a custom field of a project item
+type ProjectFieldValue struct {
+	// Name of the project field, e.g. Status.
+	Name string `json:"name"`
+
+	// Value is the option of a single select field, the title of an iteration, e.g. "Sprint 3",
+	// a number, a date as YYYY-MM-DD, or the text of a text field.
+	// +kubebuilder:validation:MinLength=1
+	Value string `json:"value"`
+}
+
+// ParentReference references the parent GithubIssue of a sub-task
+type ParentReference struct {
+	// Name of the parent GithubIssue in the same namespace.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Link selects how the issue is attached to the parent issue. SubIssue adds it as a GitHub sub-issue,
+	// TaskList adds it to the task list the operator keeps at the end of the parent issue body, checked
+	// once the issue is closed.
+	// +kubebuilder:validation:Enum=SubIssue;TaskList
+	// +kubebuilder:default=SubIssue
+	// +optional
+	Link string `json:"link,omitempty"`
+}
+
+// ChildIssuesStatus reports the progress of the GithubIssues referencing a GithubIssue as their parent
+type ChildIssuesStatus struct {
+	// Total is the number of child GithubIssues.
+	Total int32 `json:"total"`
+
+	// Closed is the number of child GithubIssues whose issue is closed.
+	Closed int32 `json:"closed"`
+
+	// Progress is the closed and total number of children, e.g. 2/3.
+	Progress string `json:"progress"`
+}
+
+// GithubIssueCommentStatus maps a spec comment to the comment created on GitHub
+type GithubIssueCommentStatus struct {
+	// Name of the matching entry in spec.comments.
+	Name string `json:"name"`
+
+	// ID is the GitHub ID of the comment.
+	ID int64 `json:"id"`
 }
 
 // GithubIssueStatus defines the observed state of GithubIssue
@@ -56,6 +376,24 @@ type GithubIssueStatus struct {
 	// +optional
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
 
+	// ObservedGeneration is the generation of the spec last synced to the GitHub issue.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// IssueNumber is the number of the GitHub issue filed for the GithubIssue.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	IssueNumber int64 `json:"issueNumber,omitempty"`
+
+	// State of the GitHub issue as of the last sync, open or closed.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	State string `json:"state,omitempty"`
+
 	// LastUpdateTime is the last time the status was updated.
 	//
 	//+optional
@@ -63,10 +401,78 @@ type GithubIssueStatus struct {
 	//+kubebuilder:validation:Format=date-time
 	//+operator-sdk:csv:customresourcedefinitions:type=status
 	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+
+	// Comments holds the GitHub ID of every comment created from spec.comments.
+	//
+	//+optional
+	//+listType=map
+	//+listMapKey=name
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Comments []GithubIssueCommentStatus `json:"comments,omitempty"`
+
+	// MirroredComments are the latest comments read from the GitHub issue, oldest first.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	MirroredComments []MirroredComment `json:"mirroredComments,omitempty"`
+
+	// CommentsSyncTime is the last time comments were read from the GitHub issue.
+	//
+	//+optional
+	//+kubebuilder:validation:Type=string
+	//+kubebuilder:validation:Format=date-time
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	CommentsSyncTime *metav1.Time `json:"commentsSyncTime,omitempty"`
+
+	// Target is the observed state of the object referenced by spec.targetRef.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Target *TargetStatus `json:"target,omitempty"`
+
+	// Occurrences is the number of GithubIssues sharing the GitHub issue when spec.deduplication is set.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Occurrences int32 `json:"occurrences,omitempty"`
+
+	// ProjectItemID is the node ID of the item of the issue on the spec.project board.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	ProjectItemID string `json:"projectItemID,omitempty"`
+
+	// Children is the progress of the GithubIssues referencing this GithubIssue in spec.parent.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Children *ChildIssuesStatus `json:"children,omitempty"`
+
+	// Drift lists the issue fields differing from the spec while spec.mode is ObserveOnly or CreateOnly.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Drift []DriftedField `json:"drift,omitempty"`
+
+	// DryRunRequests are the GitHub writes the last reconcile would have made while the manager runs with --dry-run.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	DryRunRequests []DryRunRequest `json:"dryRunRequests,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Issue",type=integer,JSONPath=`.status.issueNumber`
+// +kubebuilder:printcolumn:name="Synced",type=string,JSONPath=`.status.conditions[?(@.type=="Synced")].status`
+// +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
+// +kubebuilder:printcolumn:name="Children",type=string,JSONPath=`.status.children.progress`
+// +kubebuilder:printcolumn:name="Suspended",type=string,JSONPath=`.status.conditions[?(@.type=="Suspended")].status`
+// +kubebuilder:printcolumn:name="Target Kind",type=string,JSONPath=`.status.target.kind`
+// +kubebuilder:printcolumn:name="Target",type=string,JSONPath=`.status.target.name`
+// +kubebuilder:printcolumn:name="Target State",type=string,JSONPath=`.status.target.state`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
 // GithubIssue is the Schema for the githubissues API
 type GithubIssue struct {
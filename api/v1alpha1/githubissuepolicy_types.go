@@ -0,0 +1,68 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GithubIssuePolicySpec defines which repositories GithubIssues and GithubPullRequests in the selected
+// namespaces may file into. A namespace not selected by any policy is unrestricted. When several policies
+// select a namespace, a repository allowed by any of them is allowed.
+type GithubIssuePolicySpec struct {
+	// NamespaceSelector selects the namespaces the policy applies to, an empty selector selects all namespaces.
+	// +optional
+	NamespaceSelector metav1.LabelSelector `json:"namespaceSelector,omitempty"`
+
+	// AllowedRepositories are the repositories GithubIssues and GithubPullRequests in the selected namespaces
+	// may file into, written as "owner/name" for repositories of api.github.com, and prefixed with the host
+	// of the API otherwise, e.g. "github.example.com/owner/name". GitLab projects are written with their full path,
+	// e.g. "gitlab.example.com/group/project", and Jira projects with their key, e.g. "jira.example.com/OPS".
+	// Shell patterns are accepted, e.g. "owner/*" allows every repository of owner.
+	// +optional
+	AllowedRepositories []string `json:"allowedRepositories,omitempty"`
+
+	// AllowNodeRules lets GithubIssueRules in the selected namespaces target Nodes. Nodes are cluster scoped, so
+	// GithubIssueRules targeting them are refused in namespaces no policy allows them in, even when no policy
+	// restricts the repositories of the namespace.
+	// +optional
+	AllowNodeRules bool `json:"allowNodeRules,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+
+// GithubIssuePolicy is the Schema for the githubissuepolicies API
+type GithubIssuePolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GithubIssuePolicySpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GithubIssuePolicyList contains a list of GithubIssuePolicy
+type GithubIssuePolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GithubIssuePolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GithubIssuePolicy{}, &GithubIssuePolicyList{})
+}
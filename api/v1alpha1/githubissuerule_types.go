@@ -0,0 +1,151 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GithubIssueRuleSpec defines the desired state of GithubIssueRule
+type GithubIssueRuleSpec struct {
+	// Repo is the GitHub API URL of the repository the generated issues are filed in,
+	// e.g. https://api.github.com/repos/owner/name
+	// +optional
+	Repo string `json:"repo,omitempty"`
+
+	// RepositoryRef references the GithubRepository or ClusterGithubRepository the generated issues are filed in.
+	// +optional
+	RepositoryRef *RepositoryReference `json:"repositoryRef,omitempty"`
+
+	// Target selects the objects watched by the rule.
+	Target RuleTarget `json:"target"`
+
+	// Condition an object must be in for an issue to be filed.
+	Condition RuleCondition `json:"condition"`
+
+	// TitleTemplate is a Go text/template for the issue title, executed with .Object set to the matched object.
+	// +optional
+	TitleTemplate string `json:"titleTemplate,omitempty"`
+
+	// BodyTemplate is a Go text/template for the issue body, executed with .Object set to the matched object.
+	// +optional
+	BodyTemplate string `json:"bodyTemplate,omitempty"`
+}
+
+// RuleTarget selects the Kubernetes objects a GithubIssueRule watches
+type RuleTarget struct {
+	// Kind of the watched objects. Nodes may only be targeted from namespaces a GithubIssuePolicy allows
+	// Node rules in, see allowNodeRules.
+	// +kubebuilder:validation:Enum=Pod;Deployment;Job;Node;Event
+	Kind string `json:"kind"`
+
+	// Namespace of the watched objects, it must be the GithubIssueRule namespace, which is the default.
+	// Ignored for Nodes.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Selector filters the watched objects by label, all objects match when empty.
+	// +optional
+	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// RuleCondition describes when a watched object is considered an incident
+type RuleCondition struct {
+	// Type of the status condition to match, e.g. "Failed" for Jobs or "Ready" for Nodes.
+	// For Events it is matched against the event type, e.g. "Warning".
+	// +optional
+	Type string `json:"type,omitempty"`
+
+	// Status of the condition to match, defaults to "True".
+	// +kubebuilder:validation:Enum=True;False;Unknown
+	// +optional
+	Status metav1.ConditionStatus `json:"status,omitempty"`
+
+	// Reason to match, e.g. "CrashLoopBackOff". For Pods it is also matched against the container
+	// waiting and terminated reasons, for Events against the event reason.
+	// +optional
+	Reason string `json:"reason,omitempty"`
+}
+
+// RuleIncident is a matched object and the GithubIssue generated for it
+type RuleIncident struct {
+	// Kind of the matched object.
+	Kind string `json:"kind"`
+
+	// Namespace of the matched object.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the matched object.
+	Name string `json:"name"`
+
+	// IssueName is the name of the generated GithubIssue.
+	IssueName string `json:"issueName"`
+}
+
+// GithubIssueRuleStatus defines the observed state of GithubIssueRule
+type GithubIssueRuleStatus struct {
+	// Conditions store the status conditions of the GithubIssueRule instances
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// Incidents are the objects currently matching the rule.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Incidents []RuleIncident `json:"incidents,omitempty"`
+
+	// LastUpdateTime is the last time the status was updated.
+	//
+	//+optional
+	//+kubebuilder:validation:Type=string
+	//+kubebuilder:validation:Format=date-time
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Kind",type=string,JSONPath=`.spec.target.kind`
+// +kubebuilder:printcolumn:name="Reason",type=string,JSONPath=`.spec.condition.reason`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// GithubIssueRule is the Schema for the githubissuerules API
+type GithubIssueRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GithubIssueRuleSpec   `json:"spec,omitempty"`
+	Status GithubIssueRuleStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GithubIssueRuleList contains a list of GithubIssueRule
+type GithubIssueRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GithubIssueRule `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GithubIssueRule{}, &GithubIssueRuleList{})
+}
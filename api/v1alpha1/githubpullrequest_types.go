@@ -0,0 +1,193 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GithubPullRequestSpec defines the desired state of GithubPullRequest
+type GithubPullRequestSpec struct {
+	// Repo is the GitHub API URL of the repository the pull request is opened in,
+	// e.g. https://api.github.com/repos/owner/name
+	// +optional
+	Repo string `json:"repo,omitempty"`
+
+	// RepositoryRef references the GithubRepository or ClusterGithubRepository the pull request is opened in.
+	// When set it takes precedence over repo and the repository credentials replace the global token.
+	// +optional
+	RepositoryRef *RepositoryReference `json:"repositoryRef,omitempty"`
+
+	// Head is the branch holding the changes, "owner:branch" for a branch of a fork.
+	// +kubebuilder:validation:MinLength=1
+	Head string `json:"head"`
+
+	// Base is the branch the changes are pulled into.
+	// +kubebuilder:validation:MinLength=1
+	Base string `json:"base"`
+
+	// Title of the pull request.
+	// +kubebuilder:validation:MinLength=1
+	Title string `json:"title"`
+
+	// Body is the markdown description of the pull request. The operator appends a hidden marker to it, an open
+	// pull request of the same head and base is only adopted when it carries the marker of the GithubPullRequest.
+	// +optional
+	Body string `json:"body,omitempty"`
+
+	// Labels are added to the pull request next to the default labels of the repository.
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// Reviewers are the logins of the users whose review is requested.
+	// +optional
+	Reviewers []string `json:"reviewers,omitempty"`
+
+	// Draft opens the pull request as a draft. It is only used when the pull request is opened.
+	// +optional
+	Draft bool `json:"draft,omitempty"`
+}
+
+// PullRequestCheck is a check run reported on the head commit of a pull request
+type PullRequestCheck struct {
+	// Name of the check run.
+	Name string `json:"name"`
+
+	// Status of the check run, one of queued, in_progress or completed.
+	Status string `json:"status"`
+
+	// Conclusion of a completed check run, e.g. success or failure.
+	// +optional
+	Conclusion string `json:"conclusion,omitempty"`
+}
+
+// PullRequestReview is the latest review of a reviewer
+type PullRequestReview struct {
+	// Reviewer is the login of the user who reviewed the pull request.
+	Reviewer string `json:"reviewer"`
+
+	// State of the review, one of APPROVED, CHANGES_REQUESTED or COMMENTED.
+	State string `json:"state"`
+}
+
+// GithubPullRequestStatus defines the observed state of GithubPullRequest
+type GithubPullRequestStatus struct {
+	// Conditions store the status conditions of the GithubPullRequest instances
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// ObservedGeneration is the generation of the spec last synced to the pull request.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Number is the number of the pull request opened for the GithubPullRequest.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Number int64 `json:"number,omitempty"`
+
+	// URL is the web URL of the pull request.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	URL string `json:"url,omitempty"`
+
+	// State of the pull request, one of Open, Closed or Merged.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	State string `json:"state,omitempty"`
+
+	// Mergeable reports whether the pull request can be merged, unset while GitHub computes it.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Mergeable *bool `json:"mergeable,omitempty"`
+
+	// MergeableState is the mergeable state reported by GitHub, e.g. clean, blocked, dirty or unstable.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	MergeableState string `json:"mergeableState,omitempty"`
+
+	// ChecksState summarizes the check runs of the head commit, one of Success, Failure, Pending or None.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	ChecksState string `json:"checksState,omitempty"`
+
+	// Checks are the check runs of the head commit.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Checks []PullRequestCheck `json:"checks,omitempty"`
+
+	// ReviewDecision summarizes the reviews, one of Approved, ChangesRequested or ReviewRequired.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	ReviewDecision string `json:"reviewDecision,omitempty"`
+
+	// Reviews are the latest reviews of every reviewer.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	Reviews []PullRequestReview `json:"reviews,omitempty"`
+
+	// DryRunRequests are the GitHub writes the last reconcile would have made while the manager runs with --dry-run.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	DryRunRequests []DryRunRequest `json:"dryRunRequests,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Number",type=integer,JSONPath=`.status.number`
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.status.state`
+// +kubebuilder:printcolumn:name="Mergeable",type=string,JSONPath=`.status.mergeableState`
+// +kubebuilder:printcolumn:name="Checks",type=string,JSONPath=`.status.checksState`
+// +kubebuilder:printcolumn:name="Review",type=string,JSONPath=`.status.reviewDecision`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// GithubPullRequest is the Schema for the githubpullrequests API
+type GithubPullRequest struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GithubPullRequestSpec   `json:"spec,omitempty"`
+	Status GithubPullRequestStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GithubPullRequestList contains a list of GithubPullRequest
+type GithubPullRequestList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GithubPullRequest `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GithubPullRequest{}, &GithubPullRequestList{})
+}
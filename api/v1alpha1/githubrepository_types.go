@@ -0,0 +1,169 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GithubRepositorySpec defines the desired state of GithubRepository and ClusterGithubRepository
+type GithubRepositorySpec struct {
+	// Provider is the issue tracker hosting the repository. It takes precedence over spec.provider of the
+	// GithubIssues referencing the repository, which is used when it is not set.
+	// +kubebuilder:validation:Enum=GitHub;GitLab;Jira;Gitea
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// Owner is the user or organization owning the repository, the namespace of a GitLab project.
+	// Required for every provider but Jira.
+	// +optional
+	Owner string `json:"owner,omitempty"`
+
+	// Name of the repository, the key of a Jira project.
+	Name string `json:"name"`
+
+	// APIBaseURL is the base URL of the GitHub REST API. For GitLab it is the API URL of the instance,
+	// e.g. https://gitlab.example.com/api/v4, for Gitea and Forgejo https://gitea.example.com/api/v1, and for Jira
+	// the URL of the site, e.g. https://example.atlassian.net. It defaults to https://api.github.com for GitHub and
+	// to https://gitlab.com/api/v4 for GitLab, and is required for Gitea and Jira.
+	// +optional
+	APIBaseURL string `json:"apiBaseURL,omitempty"`
+
+	// Jira configures how issues are filed in a Jira project.
+	// +optional
+	Jira *JiraSpec `json:"jira,omitempty"`
+
+	// CredentialsRef references the Secret holding the token used to access the repository. It is required
+	// unless the repository is on https://api.github.com, where the operator SECRET_Token environment variable
+	// is used when it is not set.
+	// +optional
+	CredentialsRef *SecretKeyReference `json:"credentialsRef,omitempty"`
+
+	// DefaultLabels are added to every issue created in the repository.
+	// +optional
+	DefaultLabels []string `json:"defaultLabels,omitempty"`
+
+	// DefaultAssignees are assigned to every issue created in the repository.
+	// +optional
+	DefaultAssignees []string `json:"defaultAssignees,omitempty"`
+}
+
+// JiraSpec configures the Jira project issues are filed in
+type JiraSpec struct {
+	// IssueType is the name of the type of the created issues.
+	// +kubebuilder:default=Task
+	// +optional
+	IssueType string `json:"issueType,omitempty"`
+
+	// CloseTransition is the name of the workflow transition closing an issue.
+	// +kubebuilder:default=Done
+	// +optional
+	CloseTransition string `json:"closeTransition,omitempty"`
+
+	// ReopenTransition is the name of the workflow transition reopening a closed issue.
+	// +kubebuilder:default="To Do"
+	// +optional
+	ReopenTransition string `json:"reopenTransition,omitempty"`
+}
+
+// SecretKeyReference selects a key of a Secret
+type SecretKeyReference struct {
+	// Name of the Secret.
+	Name string `json:"name"`
+
+	// Namespace of the Secret. Required by ClusterGithubRepository,
+	// a GithubRepository always reads the Secret from its own namespace.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Key of the token in the Secret data.
+	// +kubebuilder:default=token
+	// +optional
+	Key string `json:"key,omitempty"`
+}
+
+// GithubRepositoryStatus defines the observed state of GithubRepository and ClusterGithubRepository
+type GithubRepositoryStatus struct {
+	// Conditions store the status conditions of the repository, the Reachable
+	// condition reports whether the repository can be accessed with the configured credentials.
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// URL is the API URL of the repository issues are filed against.
+	//
+	//+optional
+	//+operator-sdk:csv:customresourcedefinitions:type=status
+	URL string `json:"url,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Owner",type=string,JSONPath=`.spec.owner`
+// +kubebuilder:printcolumn:name="Repository",type=string,JSONPath=`.spec.name`
+// +kubebuilder:printcolumn:name="Reachable",type=string,JSONPath=`.status.conditions[?(@.type=="Reachable")].status`
+
+// GithubRepository is the Schema for the githubrepositories API
+type GithubRepository struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GithubRepositorySpec   `json:"spec,omitempty"`
+	Status GithubRepositoryStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GithubRepositoryList contains a list of GithubRepository
+type GithubRepositoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GithubRepository `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Owner",type=string,JSONPath=`.spec.owner`
+// +kubebuilder:printcolumn:name="Repository",type=string,JSONPath=`.spec.name`
+// +kubebuilder:printcolumn:name="Reachable",type=string,JSONPath=`.status.conditions[?(@.type=="Reachable")].status`
+
+// ClusterGithubRepository is the Schema for the clustergithubrepositories API
+type ClusterGithubRepository struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GithubRepositorySpec   `json:"spec,omitempty"`
+	Status GithubRepositoryStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ClusterGithubRepositoryList contains a list of ClusterGithubRepository
+type ClusterGithubRepositoryList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterGithubRepository `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GithubRepository{}, &GithubRepositoryList{})
+	SchemeBuilder.Register(&ClusterGithubRepository{}, &ClusterGithubRepositoryList{})
+}
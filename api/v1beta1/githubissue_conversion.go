@@ -0,0 +1,161 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+// ConvertTo converts this GithubIssue (v1beta1) to the Hub version (v1alpha1).
+func (src *GithubIssue) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*trainingv1alpha1.GithubIssue)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 GithubIssue but got %T", dstRaw)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = trainingv1alpha1.GithubIssueSpec{
+		Provider:       src.Spec.Tracker.Provider,
+		Repo:           src.Spec.Tracker.URL,
+		Title:          src.Spec.Title,
+		Description:    src.Spec.Body,
+		Labels:         src.Spec.Labels,
+		State:          src.Spec.State,
+		Mode:           src.Spec.Mode,
+		Suspend:        src.Spec.Suspend,
+		ResyncInterval: src.Spec.ResyncInterval,
+	}
+	if err := convertJSON(src.Spec.Tracker.RepositoryRef, &dst.Spec.RepositoryRef); err != nil {
+		return err
+	}
+	if err := convertJSON(src.Spec.Template, &dst.Spec.Template); err != nil {
+		return err
+	}
+	if err := convertJSON(src.Spec.TargetRef, &dst.Spec.TargetRef); err != nil {
+		return err
+	}
+	if github := src.Spec.GitHub; github != nil {
+		if err := convertJSON(github.Comments, &dst.Spec.Comments); err != nil {
+			return err
+		}
+		if err := convertJSON(github.CommentMirror, &dst.Spec.CommentMirror); err != nil {
+			return err
+		}
+		if err := convertJSON(github.Deduplication, &dst.Spec.Deduplication); err != nil {
+			return err
+		}
+		if err := convertJSON(github.Project, &dst.Spec.Project); err != nil {
+			return err
+		}
+		if err := convertJSON(github.Parent, &dst.Spec.Parent); err != nil {
+			return err
+		}
+	}
+
+	// The status only differs in how the issue is identified
+	dst.Status = trainingv1alpha1.GithubIssueStatus{}
+	if err := convertJSON(src.Status, &dst.Status); err != nil {
+		return err
+	}
+	if src.Status.IssueID != "" {
+		number, err := strconv.ParseInt(src.Status.IssueID, 10, 64)
+		if err != nil {
+			return fmt.Errorf("status.issueID %q of GithubIssue %s is not a number: %w", src.Status.IssueID, src.Name, err)
+		}
+		dst.Status.IssueNumber = number
+	}
+	return nil
+}
+
+// ConvertFrom converts the Hub version (v1alpha1) to this GithubIssue (v1beta1).
+func (dst *GithubIssue) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*trainingv1alpha1.GithubIssue)
+	if !ok {
+		return fmt.Errorf("expected a v1alpha1 GithubIssue but got %T", srcRaw)
+	}
+
+	dst.ObjectMeta = src.ObjectMeta
+	dst.Spec = GithubIssueSpec{
+		Tracker: TrackerReference{
+			Provider: src.Spec.Provider,
+			URL:      src.Spec.Repo,
+		},
+		Title:          src.Spec.Title,
+		Body:           src.Spec.Description,
+		Labels:         src.Spec.Labels,
+		State:          src.Spec.State,
+		Mode:           src.Spec.Mode,
+		Suspend:        src.Spec.Suspend,
+		ResyncInterval: src.Spec.ResyncInterval,
+	}
+	if err := convertJSON(src.Spec.RepositoryRef, &dst.Spec.Tracker.RepositoryRef); err != nil {
+		return err
+	}
+	if err := convertJSON(src.Spec.Template, &dst.Spec.Template); err != nil {
+		return err
+	}
+	if err := convertJSON(src.Spec.TargetRef, &dst.Spec.TargetRef); err != nil {
+		return err
+	}
+	if len(src.Spec.Comments) > 0 || src.Spec.CommentMirror != nil || src.Spec.Deduplication != nil ||
+		src.Spec.Project != nil || src.Spec.Parent != nil {
+		dst.Spec.GitHub = &GitHubIssueOptions{}
+		if err := convertJSON(src.Spec.Comments, &dst.Spec.GitHub.Comments); err != nil {
+			return err
+		}
+		if err := convertJSON(src.Spec.CommentMirror, &dst.Spec.GitHub.CommentMirror); err != nil {
+			return err
+		}
+		if err := convertJSON(src.Spec.Deduplication, &dst.Spec.GitHub.Deduplication); err != nil {
+			return err
+		}
+		if err := convertJSON(src.Spec.Project, &dst.Spec.GitHub.Project); err != nil {
+			return err
+		}
+		if err := convertJSON(src.Spec.Parent, &dst.Spec.GitHub.Parent); err != nil {
+			return err
+		}
+	}
+
+	dst.Status = GithubIssueStatus{}
+	if err := convertJSON(src.Status, &dst.Status); err != nil {
+		return err
+	}
+	if src.Status.IssueNumber != 0 {
+		dst.Status.IssueID = strconv.FormatInt(src.Status.IssueNumber, 10)
+	}
+	return nil
+}
+
+// convertJSON copies in into out through JSON. Both versions share the JSON layout of the nested types,
+// so no field is lost.
+func convertJSON(in interface{}, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return fmt.Errorf("error marshaling JSON: %w", err)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("error unmarshaling JSON: %w", err)
+	}
+	return nil
+}
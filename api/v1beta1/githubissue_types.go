@@ -0,0 +1,473 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GithubIssueSpec defines the desired state of GithubIssue. Unlike v1alpha1 it is provider neutral,
+// the tracker reference selects where the issue is filed and the GitHub only features are grouped under github.
+type GithubIssueSpec struct {
+	// Tracker selects the issue tracker and the repository or project the issue is filed in.
+	Tracker TrackerReference `json:"tracker"`
+
+	// Title of the issue.
+	// +optional
+	Title string `json:"title,omitempty"`
+
+	// Body of the issue.
+	// +optional
+	Body string `json:"body,omitempty"`
+
+	// Labels are added to the issue next to the default labels of the repository.
+	// +optional
+	Labels []string `json:"labels,omitempty"`
+
+	// State is the desired state of the issue. Closed closes the issue and keeps it closed,
+	// setting it back to Open reopens it.
+	// +kubebuilder:validation:Enum=Open;Closed
+	// +kubebuilder:default=Open
+	// +optional
+	State string `json:"state,omitempty"`
+
+	// Template renders the issue title and body with Go text/template on every reconcile,
+	// so the issue stays current as the referenced data changes.
+	// +optional
+	Template *IssueTemplateSpec `json:"template,omitempty"`
+
+	// TargetRef points at the Kubernetes object the issue describes.
+	// +optional
+	TargetRef *TargetReference `json:"targetRef,omitempty"`
+
+	// Mode selects how the issue is reconciled. Enforce creates the issue and overwrites its title and
+	// body when they drift from the spec. CreateOnly creates the issue but only reports later drift.
+	// ObserveOnly never writes to the tracker, it only reports drift.
+	// +kubebuilder:validation:Enum=Enforce;ObserveOnly;CreateOnly
+	// +kubebuilder:default=Enforce
+	// +optional
+	Mode string `json:"mode,omitempty"`
+
+	// Suspend stops all writes to the tracker, including closing the issue on deletion,
+	// until it is set back to false.
+	// +optional
+	Suspend bool `json:"suspend,omitempty"`
+
+	// ResyncInterval is how often the issue is compared with the spec, overriding the --resync-period
+	// of the manager. Intervals below 10s are raised to 10s.
+	// +optional
+	ResyncInterval *metav1.Duration `json:"resyncInterval,omitempty"`
+
+	// GitHub holds the features only supported by the GitHub provider.
+	// +optional
+	GitHub *GitHubIssueOptions `json:"github,omitempty"`
+}
+
+// TrackerReference selects the issue tracker and the repository or project an issue is filed in
+type TrackerReference struct {
+	// Provider is the issue tracker, the provider of the repository referenced by repositoryRef takes precedence.
+	// +kubebuilder:validation:Enum=GitHub;GitLab;Jira;Gitea
+	// +kubebuilder:default=GitHub
+	// +optional
+	Provider string `json:"provider,omitempty"`
+
+	// URL is the API URL of the repository or project, e.g. https://api.github.com/repos/owner/repo.
+	// The global token of the provider is used with it.
+	// +optional
+	URL string `json:"url,omitempty"`
+
+	// RepositoryRef references the GithubRepository or ClusterGithubRepository the issue is filed in.
+	// When set it takes precedence over url and the repository credentials replace the global token.
+	// +optional
+	RepositoryRef *RepositoryReference `json:"repositoryRef,omitempty"`
+}
+
+// GitHubIssueOptions holds the features only supported by the GitHub provider
+type GitHubIssueOptions struct {
+	// Comments are posted on the issue and kept in sync with this list.
+	// Entries removed from the list are deleted from the issue.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Comments []GithubIssueComment `json:"comments,omitempty"`
+
+	// CommentMirror copies the comments people leave on the issue back into the cluster.
+	// +optional
+	CommentMirror *CommentMirrorSpec `json:"commentMirror,omitempty"`
+
+	// Deduplication files GithubIssues of the same namespace sharing a fingerprint in the same repository as a
+	// single issue, which is only closed once the last of them is gone.
+	// +optional
+	Deduplication *DeduplicationSpec `json:"deduplication,omitempty"`
+
+	// Project adds the issue to a GitHub Projects (v2) board and keeps the custom field values of its
+	// project item in sync.
+	// +optional
+	Project *ProjectSpec `json:"project,omitempty"`
+
+	// Parent makes the issue a sub-task of the issue of another GithubIssue in the same namespace.
+	// The parent reports the progress of its children in status.children. A GithubIssue cannot be its own
+	// ancestor, and chains of parents are at most eight GithubIssues long.
+	// +optional
+	Parent *ParentReference `json:"parent,omitempty"`
+}
+
+// DryRunRequest is a write skipped because the manager runs in dry-run mode
+type DryRunRequest struct {
+	// Method is the HTTP method of the request.
+	Method string `json:"method"`
+
+	// URL is the API URL of the request.
+	URL string `json:"url"`
+
+	// Payload is the JSON body of the request.
+	// +optional
+	Payload string `json:"payload,omitempty"`
+}
+
+// DriftedField is a field of the issue that differs from the spec
+type DriftedField struct {
+	// Field is the name of the issue field, title or body.
+	Field string `json:"field"`
+
+	// Desired is the value rendered from the spec.
+	// +optional
+	Desired string `json:"desired,omitempty"`
+
+	// Actual is the value found in the tracker.
+	// +optional
+	Actual string `json:"actual,omitempty"`
+}
+
+// DeduplicationSpec defines how GithubIssues describing the same problem share an issue
+type DeduplicationSpec struct {
+	// Fingerprint identifies the problem the GithubIssue describes. Defaults to the
+	// github-issue.kubebuilder.io/fingerprint label, and to the title when the label is not set.
+	// +optional
+	Fingerprint string `json:"fingerprint,omitempty"`
+
+	// Occurrences selects how GithubIssues sharing the issue are recorded on it. Count keeps an
+	// occurrence count at the end of the issue body, Comment adds a comment for every further GithubIssue.
+	// +kubebuilder:validation:Enum=Count;Comment
+	// +kubebuilder:default=Count
+	// +optional
+	Occurrences string `json:"occurrences,omitempty"`
+}
+
+// TargetReference points at the Kubernetes object a GithubIssue describes and
+// controls whether the issue follows the object's lifecycle
+type TargetReference struct {
+	ObjectReference `json:",inline"`
+
+	// AutoClose closes the issue when the target is deleted, becomes healthy, or either.
+	// The issue is reopened when a healthy target becomes unhealthy again.
+	// +kubebuilder:validation:Enum=Never;Deleted;Healthy;HealthyOrDeleted
+	// +kubebuilder:default=Never
+	// +optional
+	AutoClose string `json:"autoClose,omitempty"`
+
+	// HealthyConditionType is the status condition of the target that is True when it is healthy.
+	// +kubebuilder:default=Ready
+	// +optional
+	HealthyConditionType string `json:"healthyConditionType,omitempty"`
+}
+
+// TargetStatus reports the observed state of the object a GithubIssue describes
+type TargetStatus struct {
+	ObjectReference `json:",inline"`
+
+	// State of the target, one of Healthy, Unhealthy or Deleted.
+	State string `json:"state"`
+}
+
+// IssueTemplateSpec defines Go text/template sources for the issue title and body.
+// Templates are executed with .Issue set to the GithubIssue, .Data set to the merged
+// data sources and .Object set to the content of the referenced Kubernetes object.
+type IssueTemplateSpec struct {
+	// Title is the template of the issue title, spec.title is used when empty.
+	// +optional
+	Title string `json:"title,omitempty"`
+
+	// Body is the template of the issue body, spec.body is used when empty.
+	// +optional
+	Body string `json:"body,omitempty"`
+
+	// Data holds static values available to the templates as .Data.
+	// +optional
+	Data map[string]string `json:"data,omitempty"`
+
+	// ConfigMapName is the name of a ConfigMap in the GithubIssue namespace whose data is merged into .Data.
+	// Keys in data take precedence over the ConfigMap.
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+
+	// ObjectRef references a Kubernetes object of the GithubIssue namespace available to the templates as .Object.
+	// Objects of other namespaces are not read. Only Pods, Deployments, Jobs, Nodes, Events and ConfigMaps can be
+	// referenced.
+	// +optional
+	ObjectRef *ObjectReference `json:"objectRef,omitempty"`
+}
+
+// ObjectReference points at any Kubernetes object
+type ObjectReference struct {
+	// APIVersion of the referenced object, e.g. "v1" or "apps/v1".
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the referenced object, e.g. "Pod".
+	Kind string `json:"kind"`
+
+	// Name of the referenced object.
+	Name string `json:"name"`
+
+	// Namespace of the referenced object, defaults to the GithubIssue namespace. Objects of other namespaces
+	// are not read. Ignored for cluster scoped kinds.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// RepositoryReference references a GithubRepository in the same namespace or a ClusterGithubRepository
+type RepositoryReference struct {
+	// Kind of the referenced repository.
+	// +kubebuilder:validation:Enum=GithubRepository;ClusterGithubRepository
+	// +kubebuilder:default=GithubRepository
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the referenced repository.
+	Name string `json:"name"`
+}
+
+// GithubIssueComment describes a comment the operator owns on the issue
+type GithubIssueComment struct {
+	// Name identifies the comment within the GithubIssue, it is not sent to the tracker.
+	Name string `json:"name"`
+
+	// Body is the markdown content of the comment.
+	Body string `json:"body"`
+}
+
+// CommentMirrorSpec configures how issue comments are recorded in the cluster
+type CommentMirrorSpec struct {
+	// Limit is the number of most recent comments to keep.
+	// +kubebuilder:default=10
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Limit int `json:"limit,omitempty"`
+
+	// ConfigMapName is the name of a ConfigMap in the GithubIssue namespace to record the comments in.
+	// When empty the comments are recorded in status.mirroredComments.
+	// +optional
+	ConfigMapName string `json:"configMapName,omitempty"`
+}
+
+// MirroredComment is a comment read from the issue
+type MirroredComment struct {
+	// ID is the ID of the comment in the tracker.
+	ID int64 `json:"id"`
+
+	// Author is the login of the user who wrote the comment.
+	Author string `json:"author"`
+
+	// CreatedAt is the time the comment was posted.
+	CreatedAt metav1.Time `json:"createdAt"`
+
+	// UpdatedAt is the time the comment was last edited.
+	// +optional
+	UpdatedAt *metav1.Time `json:"updatedAt,omitempty"`
+
+	// Body is the markdown content of the comment.
+	Body string `json:"body"`
+}
+
+// ParentReference references the parent GithubIssue of a sub-task
+type ParentReference struct {
+	// Name of the parent GithubIssue in the same namespace.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Link selects how the issue is attached to the parent issue. SubIssue adds it as a GitHub sub-issue,
+	// TaskList adds it to the task list the operator keeps at the end of the parent issue body, checked
+	// once the issue is closed.
+	// +kubebuilder:validation:Enum=SubIssue;TaskList
+	// +kubebuilder:default=SubIssue
+	// +optional
+	Link string `json:"link,omitempty"`
+}
+
+// ChildIssuesStatus reports the progress of the GithubIssues referencing a GithubIssue as their parent
+type ChildIssuesStatus struct {
+	// Total is the number of child GithubIssues.
+	Total int32 `json:"total"`
+
+	// Closed is the number of child GithubIssues whose issue is closed.
+	Closed int32 `json:"closed"`
+
+	// Progress is the closed and total number of children, e.g. 2/3.
+	Progress string `json:"progress"`
+}
+
+// ProjectSpec selects the GitHub Projects (v2) board of the issue and the values of its custom fields
+type ProjectSpec struct {
+	// Owner is the login of the organization or user owning the project.
+	// +kubebuilder:validation:MinLength=1
+	Owner string `json:"owner"`
+
+	// Number of the project, as in https://github.com/orgs/<owner>/projects/<number>.
+	// +kubebuilder:validation:Minimum=1
+	Number int `json:"number"`
+
+	// Fields are the custom field values of the project item, e.g. Status, Priority or Iteration.
+	// Values changed on the board are set back, fields not listed are left alone.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Fields []ProjectFieldValue `json:"fields,omitempty"`
+}
+
+// ProjectFieldValue is the value of a custom field of a project item
+type ProjectFieldValue struct {
+	// Name of the project field, e.g. Status.
+	Name string `json:"name"`
+
+	// Value is the option of a single select field, the title of an iteration, e.g. "Sprint 3",
+	// a number, a date as YYYY-MM-DD, or the text of a text field.
+	// +kubebuilder:validation:MinLength=1
+	Value string `json:"value"`
+}
+
+// GithubIssueCommentStatus maps a spec comment to the comment created in the tracker
+type GithubIssueCommentStatus struct {
+	// Name of the matching entry in spec.github.comments.
+	Name string `json:"name"`
+
+	// ID is the ID of the comment in the tracker.
+	ID int64 `json:"id"`
+}
+
+// GithubIssueStatus defines the observed state of GithubIssue
+type GithubIssueStatus struct {
+	// Conditions store the status conditions of the GithubIssue instances.
+	// +listType=map
+	// +listMapKey=type
+	// +patchStrategy=merge
+	// +patchMergeKey=type
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+
+	// ObservedGeneration is the generation of the spec last synced to the issue.
+	//
+	//+optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// IssueID identifies the issue filed for the GithubIssue in the tracker, the issue number for GitHub.
+	//
+	//+optional
+	IssueID string `json:"issueID,omitempty"`
+
+	// State of the issue as of the last sync, open or closed.
+	//
+	//+optional
+	State string `json:"state,omitempty"`
+
+	// LastUpdateTime is the last time the status was updated.
+	//
+	//+optional
+	//+kubebuilder:validation:Type=string
+	//+kubebuilder:validation:Format=date-time
+	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
+
+	// Comments holds the ID of every comment created from spec.github.comments.
+	//
+	//+optional
+	//+listType=map
+	//+listMapKey=name
+	Comments []GithubIssueCommentStatus `json:"comments,omitempty"`
+
+	// MirroredComments are the latest comments read from the issue, oldest first.
+	//
+	//+optional
+	MirroredComments []MirroredComment `json:"mirroredComments,omitempty"`
+
+	// CommentsSyncTime is the last time comments were read from the issue.
+	//
+	//+optional
+	//+kubebuilder:validation:Type=string
+	//+kubebuilder:validation:Format=date-time
+	CommentsSyncTime *metav1.Time `json:"commentsSyncTime,omitempty"`
+
+	// Target is the observed state of the object referenced by spec.targetRef.
+	//
+	//+optional
+	Target *TargetStatus `json:"target,omitempty"`
+
+	// Occurrences is the number of GithubIssues sharing the issue when spec.github.deduplication is set.
+	//
+	//+optional
+	Occurrences int32 `json:"occurrences,omitempty"`
+
+	// ProjectItemID is the node ID of the item of the issue on the spec.github.project board.
+	//
+	//+optional
+	ProjectItemID string `json:"projectItemID,omitempty"`
+
+	// Children is the progress of the GithubIssues referencing this GithubIssue in spec.github.parent.
+	//
+	//+optional
+	Children *ChildIssuesStatus `json:"children,omitempty"`
+
+	// Drift lists the issue fields differing from the spec while spec.mode is ObserveOnly or CreateOnly.
+	//
+	//+optional
+	Drift []DriftedField `json:"drift,omitempty"`
+
+	// DryRunRequests are the writes the last reconcile would have made while the manager runs with --dry-run.
+	//
+	//+optional
+	DryRunRequests []DryRunRequest `json:"dryRunRequests,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Provider",type=string,JSONPath=`.spec.tracker.provider`
+// +kubebuilder:printcolumn:name="Issue",type=string,JSONPath=`.status.issueID`
+// +kubebuilder:printcolumn:name="State",type=string,JSONPath=`.spec.state`
+// +kubebuilder:printcolumn:name="Synced",type=string,JSONPath=`.status.conditions[?(@.type=="Synced")].status`
+// +kubebuilder:printcolumn:name="Mode",type=string,JSONPath=`.spec.mode`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// GithubIssue is the Schema for the githubissues API
+type GithubIssue struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   GithubIssueSpec   `json:"spec,omitempty"`
+	Status GithubIssueStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GithubIssueList contains a list of GithubIssue
+type GithubIssueList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GithubIssue `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&GithubIssue{}, &GithubIssueList{})
+}
@@ -20,6 +20,7 @@ import (
 	"crypto/tls"
 	"flag"
 	"os"
+	"time"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -36,7 +37,10 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	trainingv1beta1 "Shai1-Levi/githubissues-operator.git/api/v1beta1"
 	"Shai1-Levi/githubissues-operator.git/internal/controller"
+	"Shai1-Levi/githubissues-operator.git/internal/githubfake"
+	webhooktrainingv1alpha1 "Shai1-Levi/githubissues-operator.git/internal/webhook/v1alpha1"
 	// +kubebuilder:scaffold:imports
 )
 
@@ -49,6 +53,7 @@ func init() {
 	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
 
 	utilruntime.Must(trainingv1alpha1.AddToScheme(scheme))
+	utilruntime.Must(trainingv1beta1.AddToScheme(scheme))
 	// +kubebuilder:scaffold:scheme
 }
 
@@ -58,6 +63,13 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var dryRun bool
+	var gitHubAPIURL string
+	var fakeGitHub bool
+	var resyncPeriod time.Duration
+	var issueCacheTTL time.Duration
+	var requestTimeout time.Duration
+	var controllerOptions controller.ControllerOptions
 	var tlsOpts []func(*tls.Config)
 	flag.StringVar(&metricsAddr, "metrics-bind-address", "0", "The address the metrics endpoint binds to. "+
 		"Use :8443 for HTTPS or :8080 for HTTP, or leave as 0 to disable the metrics service.")
@@ -69,6 +81,34 @@ func main() {
 		"If set, the metrics endpoint is served securely via HTTPS. Use --metrics-secure=false to use HTTP instead.")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.DurationVar(&resyncPeriod, "resync-period", time.Minute,
+		"How often GithubIssues and GithubIssueRules are resynced with GitHub. "+
+			"Up to 10% jitter is added, GithubIssues can override it with spec.resyncInterval.")
+	flag.DurationVar(&issueCacheTTL, "issue-cache-ttl", 30*time.Second,
+		"How long GitHub issues read in batches through the GraphQL API are reused by other GithubIssues of the "+
+			"same repository. 0 reads every issue with its own REST request.")
+	flag.IntVar(&controllerOptions.MaxConcurrentReconciles, "max-concurrent-reconciles", 1,
+		"The number of objects each controller reconciles in parallel. "+
+			"GithubIssues filed in the same repository are always reconciled one at a time.")
+	flag.DurationVar(&controllerOptions.RateLimiterBaseDelay, "rate-limiter-base-delay", 5*time.Millisecond,
+		"The initial delay before a failing object is retried, doubled on every failure.")
+	flag.DurationVar(&controllerOptions.RateLimiterMaxDelay, "rate-limiter-max-delay", 1000*time.Second,
+		"The maximum delay before a failing object is retried.")
+	flag.Float64Var(&controllerOptions.RateLimiterQPS, "rate-limiter-qps", 10,
+		"The overall rate at which each controller takes objects from its workqueue.")
+	flag.IntVar(&controllerOptions.RateLimiterBurst, "rate-limiter-burst", 100,
+		"The burst allowed above --rate-limiter-qps.")
+	flag.DurationVar(&requestTimeout, "github-request-timeout", controller.DefaultRequestTimeout,
+		"How long a request to GitHub or another issue tracker may take before it fails.")
+	flag.BoolVar(&dryRun, "dry-run", false,
+		"If set, only read requests are sent to GitHub. Creates, updates and closes are logged, "+
+			"emitted as events and recorded in the GithubIssue status instead.")
+	flag.StringVar(&gitHubAPIURL, "github-api-url", "",
+		"If set, the GitHub REST API is reached at this URL instead of https://api.github.com. "+
+			"Repositories on other GitHub servers are not affected.")
+	flag.BoolVar(&fakeGitHub, "fake-github", false,
+		"If set, an in-process fake GitHub server is started and used instead of https://api.github.com, "+
+			"for development without network access. Overrides --github-api-url.")
 	opts := zap.Options{
 		Development: true,
 	}
@@ -144,13 +184,80 @@ func main() {
 		os.Exit(1)
 	}
 
+	if dryRun {
+		setupLog.Info("running in dry-run mode, no writes are sent to GitHub")
+	}
+	controller.SetRequestTimeout(requestTimeout)
+	if fakeGitHub {
+		fake := githubfake.NewServer()
+		defer fake.Close()
+		gitHubAPIURL = fake.URL
+		setupLog.Info("using an in-process fake GitHub server", "url", gitHubAPIURL)
+	}
 	if err = (&controller.GithubIssueReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
+		Client:        mgr.GetClient(),
+		Scheme:        mgr.GetScheme(),
+		Recorder:      mgr.GetEventRecorderFor("githubissue-controller"),
+		APIReader:     mgr.GetAPIReader(),
+		ResyncPeriod:  resyncPeriod,
+		DryRun:        dryRun,
+		GitHubAPIURL:  gitHubAPIURL,
+		IssueCacheTTL: issueCacheTTL,
+		Options:       controllerOptions,
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "GithubIssue")
 		os.Exit(1)
 	}
+	if err = (&controller.GithubPullRequestReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		Recorder:     mgr.GetEventRecorderFor("githubpullrequest-controller"),
+		ResyncPeriod: resyncPeriod,
+		DryRun:       dryRun,
+		GitHubAPIURL: gitHubAPIURL,
+		Options:      controllerOptions,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GithubPullRequest")
+		os.Exit(1)
+	}
+	if err = (&controller.GithubIssueRuleReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		ResyncPeriod: resyncPeriod,
+		Options:      controllerOptions,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GithubIssueRule")
+		os.Exit(1)
+	}
+	if err = (&controller.GithubRepositoryReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		GitHubAPIURL: gitHubAPIURL,
+		Options:      controllerOptions,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "GithubRepository")
+		os.Exit(1)
+	}
+	if err = (&controller.ClusterGithubRepositoryReconciler{
+		Client:       mgr.GetClient(),
+		Scheme:       mgr.GetScheme(),
+		GitHubAPIURL: gitHubAPIURL,
+		Options:      controllerOptions,
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ClusterGithubRepository")
+		os.Exit(1)
+	}
+	// nolint:goconst
+	if os.Getenv("ENABLE_WEBHOOKS") != "false" {
+		if err = webhooktrainingv1alpha1.SetupGithubIssueWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "GithubIssue")
+			os.Exit(1)
+		}
+		if err = webhooktrainingv1alpha1.SetupGithubPullRequestWebhookWithManager(mgr); err != nil {
+			setupLog.Error(err, "unable to create webhook", "webhook", "GithubPullRequest")
+			os.Exit(1)
+		}
+	}
 	// +kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
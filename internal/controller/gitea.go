@@ -0,0 +1,124 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// giteaTokenEnvVar is the environment variable holding the token of the Gitea provider
+const giteaTokenEnvVar = "GITEA_TOKEN"
+
+// giteaTracker files issues with the API v1 of Gitea and Forgejo, issues are identified by their number
+type giteaTracker struct{}
+
+// giteaIssue holds the relevant parts of a Gitea issue
+type giteaIssue struct {
+	Number int64  `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	State  string `json:"state"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+}
+
+// giteaRepositoryURL returns the API URL of the repository "owner/name" on the Gitea API at baseURL,
+// e.g. https://gitea.example.com/api/v1
+func giteaRepositoryURL(baseURL string, owner string, name string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/repos/" + owner + "/" + name
+}
+
+func (giteaTracker) name() string {
+	return providerGitea
+}
+
+func (giteaTracker) authorize(req *http.Request, token string) {
+	req.Header.Set("Authorization", "token "+token)
+}
+
+// createIssue files the issue without labels, as Gitea only accepts label IDs on creation.
+// The labels are added by name when the new issue is synced.
+func (giteaTracker) createIssue(projectURL string, issue trackerIssue) trackerRequest {
+	payload := map[string]string{"title": issue.Title, "body": issue.Body}
+	return trackerRequest{Method: http.MethodPost, URL: projectURL + "/issues", Payload: payload, Expected: http.StatusCreated}
+}
+
+func (giteaTracker) getIssue(projectURL string, id string) trackerRequest {
+	return trackerRequest{Method: http.MethodGet, URL: projectURL + "/issues/" + id, Expected: http.StatusOK}
+}
+
+// updateIssue only adds missing labels, labels added on Gitea are kept
+func (giteaTracker) updateIssue(projectURL string, current trackerIssue, desired trackerIssue) ([]trackerRequest, error) {
+	issueURL := projectURL + "/issues/" + current.ID
+	var requests []trackerRequest
+
+	payload := map[string]string{}
+	if current.Title != desired.Title {
+		payload["title"] = desired.Title
+	}
+	if current.Body != desired.Body {
+		payload["body"] = desired.Body
+	}
+	if current.Closed != desired.Closed {
+		payload["state"] = "open"
+		if desired.Closed {
+			payload["state"] = "closed"
+		}
+	}
+	if len(payload) > 0 {
+		// Gitea answers edits of an issue with 201
+		requests = append(requests, trackerRequest{Method: http.MethodPatch, URL: issueURL, Payload: payload, Expected: http.StatusCreated})
+	}
+
+	var missing []string
+	for _, label := range desired.Labels {
+		if !containsLabel(current.Labels, label) {
+			missing = append(missing, label)
+		}
+	}
+	if len(missing) > 0 {
+		requests = append(requests, trackerRequest{Method: http.MethodPost, URL: issueURL + "/labels",
+			Payload: map[string][]string{"labels": missing}, Expected: http.StatusOK})
+	}
+	return requests, nil
+}
+
+func (giteaTracker) parseIssue(body []byte) (trackerIssue, error) {
+	var issue giteaIssue
+	if err := json.Unmarshal(body, &issue); err != nil {
+		return trackerIssue{}, fmt.Errorf("error unmarshaling JSON: %w", err)
+	}
+	if issue.Number == 0 {
+		return trackerIssue{}, fmt.Errorf("Gitea issue has no number")
+	}
+	labels := make([]string, 0, len(issue.Labels))
+	for _, label := range issue.Labels {
+		labels = append(labels, label.Name)
+	}
+	return trackerIssue{
+		ID:     strconv.FormatInt(issue.Number, 10),
+		Title:  issue.Title,
+		Body:   issue.Body,
+		Labels: labels,
+		Closed: issue.State == "closed",
+	}, nil
+}
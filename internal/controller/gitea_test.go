@@ -0,0 +1,151 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+var _ = Describe("Gitea provider", func() {
+	const (
+		resourceName = "test-gitea"
+		repoPath     = "/api/v1/repos/owner/repo"
+	)
+
+	ctx := context.Background()
+	typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	var (
+		server   *httptest.Server
+		mu       sync.Mutex
+		issue    map[string]interface{}
+		requests []string
+	)
+
+	BeforeEach(func() {
+		issue = nil
+		requests = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			body, _ := io.ReadAll(req.Body)
+			requests = append(requests, req.Method+" "+req.URL.Path+" "+string(body))
+			if req.Header.Get("Authorization") != "token gitea-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			var payload map[string]interface{}
+			_ = json.Unmarshal(body, &payload)
+			switch {
+			case req.Method == http.MethodPost && req.URL.Path == repoPath+"/issues":
+				issue = map[string]interface{}{"number": 7, "title": payload["title"], "body": payload["body"], "state": "open",
+					"labels": []interface{}{}}
+				w.WriteHeader(http.StatusCreated)
+			case issue == nil:
+				w.WriteHeader(http.StatusNotFound)
+				return
+			case req.Method == http.MethodPatch && req.URL.Path == repoPath+"/issues/7":
+				for key, value := range payload {
+					issue[key] = value
+				}
+				w.WriteHeader(http.StatusCreated)
+			case req.Method == http.MethodPost && req.URL.Path == repoPath+"/issues/7/labels":
+				var labels []interface{}
+				for _, name := range payload["labels"].([]interface{}) {
+					labels = append(labels, map[string]interface{}{"name": name})
+				}
+				issue["labels"] = labels
+				_ = json.NewEncoder(w).Encode(labels)
+				return
+			case req.URL.Path != repoPath+"/issues/7":
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(issue)
+		}))
+		Expect(os.Setenv(giteaTokenEnvVar, "gitea-token")).To(Succeed())
+
+		resource := &trainingv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec: trainingv1alpha1.GithubIssueSpec{
+				Provider:    providerGitea,
+				Repo:        server.URL + repoPath,
+				Title:       "title",
+				Description: "body",
+				Labels:      []string{"operator"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		Expect(os.Unsetenv(giteaTokenEnvVar)).To(Succeed())
+	})
+
+	It("should create, label, reopen and close the Gitea issue", func() {
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		reconcileGithubIssue := func() {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		By("creating the issue")
+		reconcileGithubIssue()
+		reconcileGithubIssue()
+		Expect(requests).To(ConsistOf(`POST ` + repoPath + `/issues {"body":"body","title":"title"}`))
+		ghi := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		Expect(ghi.Status.IssueNumber).To(Equal(int64(7)))
+
+		By("adding the labels by name")
+		reconcileGithubIssue()
+		Expect(requests[len(requests)-1]).To(Equal(`POST ` + repoPath + `/issues/7/labels {"labels":["operator"]}`))
+
+		By("reopening the issue")
+		mu.Lock()
+		issue["state"] = "closed"
+		mu.Unlock()
+		reconcileGithubIssue()
+		Expect(requests[len(requests)-1]).To(Equal(`PATCH ` + repoPath + `/issues/7 {"state":"open"}`))
+		Expect(issue["state"]).To(Equal("open"))
+
+		By("closing the issue on deletion")
+		Expect(k8sClient.Delete(ctx, ghi)).To(Succeed())
+		reconcileGithubIssue()
+		Expect(requests[len(requests)-1]).To(Equal(`PATCH ` + repoPath + `/issues/7 {"state":"closed"}`))
+		Expect(issue["state"]).To(Equal("closed"))
+		Expect(apiErrors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, ghi))).To(BeTrue())
+	})
+})
@@ -0,0 +1,361 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"Shai1-Levi/githubissues-operator.git/internal/policy"
+)
+
+const (
+	// issueBatchSize is the number of issues read by a single GraphQL query
+	issueBatchSize = 100
+
+	// issueCacheIdleTTLs is the number of TTLs after which an issue no reconcile looked up is evicted from the
+	// cache, e.g. the issue of a deleted GithubIssue, instead of being read again in every batch
+	issueCacheIdleTTLs = 3
+
+	// issueFields are the fields of the issues read in batches, the reconcile only compares these
+	issueFields = `id number title body state updatedAt labels(first: 100) { nodes { name } }`
+)
+
+var (
+	graphQLCostTotal = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "githubissues_graphql_cost_total",
+		Help: "GitHub GraphQL rate limit points spent reading issues in batches",
+	})
+	graphQLRateLimitRemaining = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "githubissues_graphql_rate_limit_remaining",
+		Help: "GitHub GraphQL rate limit points left in the current window, as reported by the last batch read",
+	})
+	issueCacheReads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "githubissues_issue_cache_reads_total",
+		Help: "Issue reads of GithubIssue reconciles, by whether they were answered from the batch cache",
+	}, []string{"result"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(graphQLCostTotal, graphQLRateLimitRemaining, issueCacheReads)
+}
+
+// gitHubRateLimit is the rateLimit object of a GraphQL response
+type gitHubRateLimit struct {
+	Cost      int       `json:"cost"`
+	Limit     int       `json:"limit"`
+	Remaining int       `json:"remaining"`
+	ResetAt   time.Time `json:"resetAt"`
+}
+
+// gitHubBatchIssue is an issue of a batch read
+type gitHubBatchIssue struct {
+	ID        string `json:"id"`
+	Number    int64  `json:"number"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	State     string `json:"state"`
+	UpdatedAt string `json:"updatedAt"`
+	Labels    struct {
+		Nodes []struct {
+			Name string `json:"name"`
+		} `json:"nodes"`
+	} `json:"labels"`
+}
+
+// gitHubIssueCache holds the issues read in batches through the GitHub GraphQL API. It is shared by the
+// reconciles of all GithubIssues: a miss reads the issue together with the other stale issues of the repository
+// read before, so the GithubIssues of a repository need one query per batch instead of a REST request each.
+type gitHubIssueCache struct {
+	mu sync.Mutex
+	// repos are keyed by the token as well, so a GithubIssue is never served an issue read with the token of
+	// another repository configuration, which may see private repositories its own token cannot
+	repos map[issueCacheKey]*cachedRepository
+	// rateLimits are the GraphQL rate limits reported by the last batch read of every token, as GitHub
+	// accounts the points per user
+	rateLimits map[string]gitHubRateLimit
+}
+
+// issueCacheKey identifies the issues of a repository read with a token
+type issueCacheKey struct {
+	repoURL     string
+	accessToken string
+}
+
+type cachedRepository struct {
+	issues map[int64]cachedIssue
+}
+
+type cachedIssue struct {
+	// issue has the fields of the REST API the reconcile compares, nil when the issue was not found
+	issue  map[string]interface{}
+	readAt time.Time
+	// lookedUpAt is when a reconcile last asked for the issue
+	lookedUpAt time.Time
+}
+
+// lookup returns the cached issue when it was read within ttl
+func (c *gitHubIssueCache) lookup(key issueCacheKey, number int64, ttl time.Duration, now time.Time) (map[string]interface{}, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	repo, ok := c.repos[key]
+	if !ok {
+		return nil, false
+	}
+	cached, ok := repo.issues[number]
+	if !ok {
+		return nil, false
+	}
+	cached.lookedUpAt = now
+	repo.issues[number] = cached
+	if now.Sub(cached.readAt) >= ttl {
+		return nil, false
+	}
+	return cached.issue, true
+}
+
+// batch returns number followed by the other issues of the repository that are older than ttl, up to
+// issueBatchSize, oldest first
+func (c *gitHubIssueCache) batch(key issueCacheKey, number int64, ttl time.Duration, now time.Time) []int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.evictIdle(ttl, now)
+	var stale []int64
+	if repo, ok := c.repos[key]; ok {
+		for other, cached := range repo.issues {
+			if other != number && now.Sub(cached.readAt) >= ttl {
+				stale = append(stale, other)
+			}
+		}
+		sort.Slice(stale, func(i, j int) bool {
+			return repo.issues[stale[i]].readAt.Before(repo.issues[stale[j]].readAt)
+		})
+	}
+	return append([]int64{number}, stale[:min(len(stale), issueBatchSize-1)]...)
+}
+
+// store records the issues of a batch read
+func (c *gitHubIssueCache) store(key issueCacheKey, issues map[int64]map[string]interface{}, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.repos == nil {
+		c.repos = map[issueCacheKey]*cachedRepository{}
+	}
+	repo, ok := c.repos[key]
+	if !ok {
+		repo = &cachedRepository{issues: map[int64]cachedIssue{}}
+		c.repos[key] = repo
+	}
+	for number, issue := range issues {
+		lookedUpAt := now
+		if cached, found := repo.issues[number]; found {
+			lookedUpAt = cached.lookedUpAt
+		}
+		repo.issues[number] = cachedIssue{issue: issue, readAt: now, lookedUpAt: lookedUpAt}
+	}
+}
+
+// evictIdle drops the issues no reconcile looked up for issueCacheIdleTTLs TTLs, the repositories left without
+// issues, and the rate limits of the tokens without repositories once their window was reset. c.mu must be held.
+func (c *gitHubIssueCache) evictIdle(ttl time.Duration, now time.Time) {
+	tokens := map[string]bool{}
+	for key, repo := range c.repos {
+		for number, cached := range repo.issues {
+			if now.Sub(cached.lookedUpAt) >= issueCacheIdleTTLs*ttl {
+				delete(repo.issues, number)
+			}
+		}
+		if len(repo.issues) == 0 {
+			delete(c.repos, key)
+			continue
+		}
+		tokens[key.accessToken] = true
+	}
+	for token, rateLimit := range c.rateLimits {
+		if !tokens[token] && !now.Before(rateLimit.ResetAt) {
+			delete(c.rateLimits, token)
+		}
+	}
+}
+
+// forget drops an issue of the repository after it was changed, so the next read with any token sees the
+// change. The issue keeps its place in the batches of the repository.
+func (c *gitHubIssueCache) forget(repoURL string, number int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, repo := range c.repos {
+		if key.repoURL != repoURL {
+			continue
+		}
+		if cached, found := repo.issues[number]; found {
+			repo.issues[number] = cachedIssue{lookedUpAt: cached.lookedUpAt}
+		}
+	}
+}
+
+// exhausted returns true when the last batch read of the token reported no GraphQL points left before the reset
+func (c *gitHubIssueCache) exhausted(accessToken string, now time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	rateLimit, ok := c.rateLimits[accessToken]
+	return ok && rateLimit.Remaining < rateLimit.Cost && now.Before(rateLimit.ResetAt)
+}
+
+// account records the rateLimit object of a batch read of the token
+func (c *gitHubIssueCache) account(accessToken string, rateLimit gitHubRateLimit) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rateLimits == nil {
+		c.rateLimits = map[string]gitHubRateLimit{}
+	}
+	c.rateLimits[accessToken] = rateLimit
+	graphQLCostTotal.Add(float64(rateLimit.Cost))
+	graphQLRateLimitRemaining.Set(float64(rateLimit.Remaining))
+}
+
+// forgetGitHubIssue drops the cached issue after it was changed, repo is the issues URL of the repository
+func (r *GithubIssueReconciler) forgetGitHubIssue(repo string, issueNumber string) {
+	if number, err := strconv.ParseInt(issueNumber, 10, 64); err == nil {
+		r.issueCache.forget(strings.TrimSuffix(repo, "/issues"), number)
+	}
+}
+
+// readGitHubIssue returns the issue of the repository in the form of the REST API. When IssueCacheTTL is set the
+// issue is served from the batch cache, and read in a batch through the GraphQL API on a miss. The REST API is
+// used when the cache is disabled or the GraphQL rate limit is used up.
+func (r *GithubIssueReconciler) readGitHubIssue(ctx context.Context, repoURL string, issueNumber string, accessToken string) (map[string]interface{}, error) {
+	number, err := strconv.ParseInt(issueNumber, 10, 64)
+	now := time.Now()
+	if r.IssueCacheTTL <= 0 || err != nil || r.issueCache.exhausted(accessToken, now) {
+		body, err := r.fetchGitHubIssuesbyIssueNumber(issueNumber, repoURL+"/issues", accessToken)
+		if err != nil {
+			return nil, err
+		}
+		var result map[string]interface{}
+		if err := json.Unmarshal(body, &result); err != nil {
+			return nil, fmt.Errorf("error unmarshaling JSON: %w", err)
+		}
+		return result, nil
+	}
+
+	key := issueCacheKey{repoURL: repoURL, accessToken: accessToken}
+	if issue, ok := r.issueCache.lookup(key, number, r.IssueCacheTTL, now); ok {
+		issueCacheReads.WithLabelValues("hit").Inc()
+		return foundGitHubIssue(issue)
+	}
+	issueCacheReads.WithLabelValues("miss").Inc()
+
+	numbers := r.issueCache.batch(key, number, r.IssueCacheTTL, now)
+	issues, rateLimit, err := fetchGitHubIssueBatch(repoURL, numbers, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	r.issueCache.account(accessToken, rateLimit)
+	r.issueCache.store(key, issues, now)
+	log.FromContext(ctx).V(1).Info("Read GitHub issues in a batch", "repo", repoURL, "issues", len(numbers),
+		"cost", rateLimit.Cost, "remaining", rateLimit.Remaining)
+	return foundGitHubIssue(issues[number])
+}
+
+// foundGitHubIssue reports an issue missing from a batch as the REST API does, so it is not synced as an empty issue
+func foundGitHubIssue(issue map[string]interface{}) (map[string]interface{}, error) {
+	if issue == nil {
+		return nil, &gitHubStatusError{StatusCode: http.StatusNotFound}
+	}
+	return issue, nil
+}
+
+// fetchGitHubIssueBatch reads the issues of the repository with a single GraphQL query. Issues that do not exist
+// are returned as nil.
+func fetchGitHubIssueBatch(repoURL string, numbers []int64, accessToken string) (map[int64]map[string]interface{}, gitHubRateLimit, error) {
+	var rateLimit gitHubRateLimit
+	fullName, err := policy.RepositoryFullName(repoURL)
+	if err != nil {
+		return nil, rateLimit, err
+	}
+	owner, name, _ := strings.Cut(fullName, "/")
+
+	var query strings.Builder
+	query.WriteString("query GithubIssues($owner: String!, $name: String!) {\n")
+	query.WriteString("  rateLimit { cost limit remaining resetAt }\n")
+	query.WriteString("  repository(owner: $owner, name: $name) {\n")
+	for _, number := range numbers {
+		fmt.Fprintf(&query, "    i%d: issue(number: %d) { ...issueFields }\n", number, number)
+	}
+	query.WriteString("  }\n}\nfragment issueFields on Issue { " + issueFields + " }")
+
+	request := graphQLRequest{
+		Query:         query.String(),
+		OperationName: "GithubIssues",
+		Variables:     map[string]interface{}{"owner": owner, "name": name},
+	}
+	response, err := sendGraphQLRequest(gitHubGraphQLURL(repoURL), request, accessToken)
+	if err != nil {
+		return nil, rateLimit, err
+	}
+	// Issues that were deleted or transferred are reported as NOT_FOUND errors next to the other issues
+	for _, e := range response.Errors {
+		if e.Type != "NOT_FOUND" || len(e.Path) != 2 {
+			return nil, rateLimit, graphQLErrors(request.OperationName, response.Errors)
+		}
+	}
+
+	var data struct {
+		RateLimit  gitHubRateLimit              `json:"rateLimit"`
+		Repository map[string]*gitHubBatchIssue `json:"repository"`
+	}
+	if err := json.Unmarshal(response.Data, &data); err != nil {
+		return nil, rateLimit, fmt.Errorf("error unmarshaling JSON: %w", err)
+	}
+
+	issues := make(map[int64]map[string]interface{}, len(numbers))
+	for _, number := range numbers {
+		issues[number] = restIssue(data.Repository["i"+strconv.FormatInt(number, 10)])
+	}
+	return issues, data.RateLimit, nil
+}
+
+// restIssue converts an issue of a batch read to the fields of the REST API the reconcile reads
+func restIssue(issue *gitHubBatchIssue) map[string]interface{} {
+	if issue == nil {
+		return nil
+	}
+	labels := make([]interface{}, 0, len(issue.Labels.Nodes))
+	for _, label := range issue.Labels.Nodes {
+		labels = append(labels, map[string]interface{}{"name": label.Name})
+	}
+	return map[string]interface{}{
+		"node_id":    issue.ID,
+		"number":     float64(issue.Number),
+		"title":      issue.Title,
+		"body":       issue.Body,
+		"state":      strings.ToLower(issue.State),
+		"labels":     labels,
+		"updated_at": issue.UpdatedAt,
+	}
+}
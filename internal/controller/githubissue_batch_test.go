@@ -0,0 +1,272 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	"Shai1-Levi/githubissues-operator.git/internal/githubfake"
+)
+
+var _ = Describe("GithubIssue batch reads", func() {
+	const fullName = "owner/batch"
+
+	ctx := context.Background()
+	names := []string{"test-batch-1", "test-batch-2", "test-batch-3"}
+
+	var reconciler *GithubIssueReconciler
+	reconcileAll := func() {
+		for _, name := range names {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}})
+			Expect(err).NotTo(HaveOccurred())
+		}
+	}
+	// expireIssueCache ages every cached issue past the TTL instead of waiting for it
+	expireIssueCache := func() {
+		for _, repo := range reconciler.issueCache.repos {
+			for number, cached := range repo.issues {
+				cached.readAt = cached.readAt.Add(-time.Hour)
+				repo.issues[number] = cached
+			}
+		}
+	}
+	// issueReads counts the GraphQL batch reads and the REST reads of single issues since the given request
+	issueReads := func(since int) (batches int, singles int) {
+		for _, request := range gitHubServer.Requests()[since:] {
+			switch {
+			case request.Path == "/graphql" && strings.Contains(request.Body, "GithubIssues"):
+				batches++
+			case request.Method == "GET" && strings.HasPrefix(request.Path, "/repos/"+fullName+"/issues/"):
+				singles++
+			}
+		}
+		return batches, singles
+	}
+
+	BeforeEach(func() {
+		gitHubServer.Reset()
+		gitHubServer.SetToken("token")
+		gitHubServer.SetRateLimit(githubfake.DefaultRateLimit)
+		Expect(os.Setenv(tokenEnvVar, "token")).To(Succeed())
+		reconciler = &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), GitHubAPIURL: gitHubServer.URL,
+			IssueCacheTTL: time.Hour}
+
+		for i, name := range names {
+			resource := &trainingv1alpha1.GithubIssue{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec: trainingv1alpha1.GithubIssueSpec{
+					Repo:        "https://api.github.com/repos/" + fullName,
+					Title:       fmt.Sprintf("title %d", i+1),
+					Description: "body",
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv(tokenEnvVar)).To(Succeed())
+
+		for _, name := range names {
+			resource := &trainingv1alpha1.GithubIssue{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, resource)).To(Succeed())
+			resource.Finalizers = nil
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).To(Succeed())
+		}
+	})
+
+	It("should read the issues of a repository with one GraphQL query", func() {
+		By("filing the issues")
+		reconcileAll()
+		reconcileAll()
+		reconcileAll()
+		Expect(gitHubServer.Issues(fullName)).To(HaveLen(3))
+
+		By("reading all issues in one batch once the cache expired")
+		expireIssueCache()
+		since := len(gitHubServer.Requests())
+		reconcileAll()
+		batches, singles := issueReads(since)
+		Expect(batches).To(Equal(1))
+		Expect(singles).To(BeZero())
+
+		By("accounting the cost of the query")
+		rateLimit := reconciler.issueCache.rateLimits["token"]
+		Expect(rateLimit.Cost).To(Equal(1))
+		Expect(rateLimit.Limit).To(Equal(githubfake.DefaultRateLimit))
+		Expect(rateLimit.Remaining).To(BeNumerically("<", githubfake.DefaultRateLimit))
+
+		By("correcting drift found in a batch")
+		Expect(gitHubServer.UpdateIssue(fullName, 2, func(issue *githubfake.Issue) {
+			issue.Title = "edited"
+		})).To(BeTrue())
+		expireIssueCache()
+		reconcileAll()
+		issue, found := gitHubServer.Issue(fullName, 2)
+		Expect(found).To(BeTrue())
+		Expect(issue.Title).To(Equal("title 2"))
+
+		By("reading the corrected issue again instead of the cached drift")
+		since = len(gitHubServer.Requests())
+		reconcileAll()
+		batches, _ = issueReads(since)
+		Expect(batches).To(Equal(1))
+		for _, request := range gitHubServer.Requests()[since:] {
+			Expect(request.Method).NotTo(Equal("PATCH"))
+		}
+	})
+
+	It("should report a failed read instead of syncing the issue", func() {
+		reconcileAll()
+		reconcileAll()
+		reconcileAll()
+
+		expireIssueCache()
+		gitHubServer.InjectFault(githubfake.Fault{Path: "/graphql", Status: http.StatusBadGateway})
+		since := len(gitHubServer.Requests())
+		name := types.NamespacedName{Name: names[0], Namespace: "default"}
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: name})
+		Expect(err).To(HaveOccurred())
+
+		ghi := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, name, ghi)).To(Succeed())
+		condition := meta.FindStatusCondition(ghi.Status.Conditions, conditionSynced)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("GitHubUnavailable"))
+		for _, request := range gitHubServer.Requests()[since:] {
+			Expect(request.Method).NotTo(Equal("PATCH"))
+		}
+	})
+
+	It("should fall back to REST reads when the cache is disabled or the GraphQL points are used up", func() {
+		reconciler.IssueCacheTTL = 0
+		reconcileAll()
+		reconcileAll()
+		since := len(gitHubServer.Requests())
+		reconcileAll()
+		batches, singles := issueReads(since)
+		Expect(batches).To(BeZero())
+		Expect(singles).To(Equal(3))
+
+		reconciler.IssueCacheTTL = time.Hour
+		reconciler.issueCache.account("token", gitHubRateLimit{Cost: 1, Limit: 5000, Remaining: 0, ResetAt: time.Now().Add(time.Hour)})
+		since = len(gitHubServer.Requests())
+		reconcileAll()
+		batches, singles = issueReads(since)
+		Expect(batches).To(BeZero())
+		Expect(singles).To(Equal(3))
+	})
+
+	It("should report a failed REST read instead of syncing the issue", func() {
+		reconciler.IssueCacheTTL = 0
+		reconcileAll()
+		reconcileAll()
+
+		for status, reason := range map[int]string{http.StatusNotFound: "NotFound", http.StatusForbidden: "Forbidden"} {
+			gitHubServer.InjectFault(githubfake.Fault{Method: "GET", Path: "/repos/" + fullName + "/issues/1", Status: status, Times: 1})
+			since := len(gitHubServer.Requests())
+			name := types.NamespacedName{Name: names[0], Namespace: "default"}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: name})
+			Expect(err).To(HaveOccurred())
+
+			ghi := &trainingv1alpha1.GithubIssue{}
+			Expect(k8sClient.Get(ctx, name, ghi)).To(Succeed())
+			condition := meta.FindStatusCondition(ghi.Status.Conditions, conditionSynced)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal(reason))
+			for _, request := range gitHubServer.Requests()[since:] {
+				Expect(request.Method).To(Equal("GET"))
+			}
+		}
+	})
+
+	It("should not serve issues read with another token", func() {
+		reconcileAll()
+		reconcileAll()
+		reconcileAll()
+		Expect(reconciler.issueCache.repos).To(HaveKey(issueCacheKey{repoURL: gitHubServer.RepositoryURL(fullName), accessToken: "token"}))
+
+		_, found := reconciler.issueCache.lookup(issueCacheKey{repoURL: gitHubServer.RepositoryURL(fullName), accessToken: "other"}, 1,
+			reconciler.IssueCacheTTL, time.Now())
+		Expect(found).To(BeFalse())
+	})
+
+	It("should evict the issues no reconcile looked up for a few TTLs", func() {
+		reconcileAll()
+		reconcileAll()
+		reconcileAll()
+		key := issueCacheKey{repoURL: gitHubServer.RepositoryURL(fullName), accessToken: "token"}
+		Expect(reconciler.issueCache.repos[key].issues).To(HaveLen(3))
+
+		By("leaving the issues of the other GithubIssues idle")
+		for number, cached := range reconciler.issueCache.repos[key].issues {
+			cached.readAt = cached.readAt.Add(-time.Hour)
+			if number != 1 {
+				cached.lookedUpAt = cached.lookedUpAt.Add(-issueCacheIdleTTLs * time.Hour)
+			}
+			reconciler.issueCache.repos[key].issues[number] = cached
+		}
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: names[0], Namespace: "default"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(reconciler.issueCache.repos[key].issues).To(HaveLen(1))
+		Expect(reconciler.issueCache.repos[key].issues).To(HaveKey(int64(1)))
+
+		By("dropping the repository once none of its issues is looked up")
+		for number, cached := range reconciler.issueCache.repos[key].issues {
+			cached.lookedUpAt = cached.lookedUpAt.Add(-issueCacheIdleTTLs * time.Hour)
+			reconciler.issueCache.repos[key].issues[number] = cached
+		}
+		reconciler.issueCache.batch(issueCacheKey{repoURL: "other"}, 1, reconciler.IssueCacheTTL, time.Now())
+		Expect(reconciler.issueCache.repos).NotTo(HaveKey(key))
+	})
+
+	It("should return issues missing from a batch as not found", func() {
+		gitHubServer.CreateIssue(fullName, githubfake.Issue{Title: "title"})
+
+		issues, _, err := fetchGitHubIssueBatch(gitHubServer.RepositoryURL(fullName), []int64{1, 7}, "token")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issues[1]).To(HaveKeyWithValue("title", "title"))
+		Expect(issues[1]).To(HaveKeyWithValue("state", "open"))
+		Expect(issues[1]).To(HaveKeyWithValue("node_id", githubfake.IssueNodeID(fullName, 1)))
+		Expect(issues).To(HaveKeyWithValue(int64(7), BeNil()))
+	})
+
+	It("should report a batch rejected by the rate limit", func() {
+		gitHubServer.SetRateLimit(0)
+
+		_, _, err := fetchGitHubIssueBatch(gitHubServer.RepositoryURL(fullName), []int64{1}, "token")
+		Expect(err).To(HaveOccurred())
+		Expect(syncFailureReason(err)).To(Equal("RateLimited"))
+	})
+})
@@ -0,0 +1,218 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+// GitHubComment holds the relevant parts of a GitHub issue comment.
+type GitHubComment struct {
+	ID   int64  `json:"id"`
+	Body string `json:"body"`
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// syncGithubIssueComments creates, edits and deletes the comments on the GitHub issue so they match spec.comments.
+// The GitHub ID of every comment is recorded in status.comments, which is updated even when a call fails
+// so that already created comments are not posted twice on the next reconcile.
+func (r *GithubIssueReconciler) syncGithubIssueComments(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, repoURL string, issueNumber string, accessToken string) error {
+	log := log.FromContext(ctx)
+
+	if len(ghi.Spec.Comments) == 0 && len(ghi.Status.Comments) == 0 {
+		return nil
+	}
+
+	desired := make(map[string]bool, len(ghi.Spec.Comments))
+	for _, c := range ghi.Spec.Comments {
+		desired[c.Name] = true
+	}
+	known := make(map[string]int64, len(ghi.Status.Comments))
+	for _, c := range ghi.Status.Comments {
+		known[c.Name] = c.ID
+	}
+
+	var syncErr error
+	changed := false
+	statusComments := []trainingv1alpha1.GithubIssueCommentStatus{}
+
+	// Delete the comments that were removed from the spec
+	for _, c := range ghi.Status.Comments {
+		if desired[c.Name] {
+			continue
+		}
+		if syncErr != nil {
+			statusComments = append(statusComments, c)
+			continue
+		}
+		log.Info("Deleting GitHub issue comment", "name", c.Name, "id", c.ID)
+		if err := r.deleteGithubIssueComment(ctx, repoURL, c.ID, accessToken); err != nil {
+			syncErr = err
+			statusComments = append(statusComments, c)
+			continue
+		}
+		changed = true
+	}
+
+	for _, c := range ghi.Spec.Comments {
+		if syncErr != nil {
+			if id, ok := known[c.Name]; ok {
+				statusComments = append(statusComments, trainingv1alpha1.GithubIssueCommentStatus{Name: c.Name, ID: id})
+			}
+			continue
+		}
+
+		if id, ok := known[c.Name]; ok {
+			current, found, err := r.fetchGithubIssueComment(repoURL, id, accessToken)
+			if err != nil {
+				syncErr = err
+				statusComments = append(statusComments, trainingv1alpha1.GithubIssueCommentStatus{Name: c.Name, ID: id})
+				continue
+			}
+			if found {
+				if current.Body != c.Body {
+					log.Info("Updating GitHub issue comment", "name", c.Name, "id", id)
+					if err := r.updateGithubIssueComment(ctx, repoURL, id, c.Body, accessToken); err != nil {
+						syncErr = err
+					}
+				}
+				statusComments = append(statusComments, trainingv1alpha1.GithubIssueCommentStatus{Name: c.Name, ID: id})
+				continue
+			}
+			// The comment was deleted on GitHub, post it again
+			log.Info("GitHub issue comment not found, recreating it", "name", c.Name, "id", id)
+		}
+
+		log.Info("Creating GitHub issue comment", "name", c.Name)
+		id, err := r.createGithubIssueComment(ctx, repoURL, issueNumber, c.Body, accessToken)
+		if err != nil {
+			syncErr = err
+			continue
+		}
+		statusComments = append(statusComments, trainingv1alpha1.GithubIssueCommentStatus{Name: c.Name, ID: id})
+		changed = true
+	}
+
+	// In dry-run mode nothing was posted, so there are no comment IDs to remember
+	if changed && !r.DryRun {
+		base := ghi.DeepCopy()
+		ghi.Status.Comments = statusComments
+		if err := r.patchGithubIssueStatus(ctx, ghi, base); err != nil {
+			log.Error(err, "Failed to update GithubIssue comments status")
+			return err
+		}
+	}
+
+	return syncErr
+}
+
+// githubIssueCommentURL returns the API URL of a single comment in the repo
+func githubIssueCommentURL(repo string, commentID int64) string {
+	return repo + "/issues/comments/" + strconv.FormatInt(commentID, 10)
+}
+
+func (r *GithubIssueReconciler) createGithubIssueComment(ctx context.Context, repo string, issueNumber string, body string, accessToken string) (int64, error) {
+	url := repo + "/issues/" + issueNumber + "/comments"
+	payload := map[string]string{"body": body}
+	if r.dryRunRequest(ctx, "POST", url, payload) {
+		return 0, nil
+	}
+
+	status, respBody, err := r.sendGitHubRequest("POST", url, payload, accessToken)
+	if err != nil {
+		return 0, err
+	}
+	if status != http.StatusCreated {
+		return 0, &gitHubStatusError{StatusCode: status}
+	}
+
+	var comment GitHubComment
+	if err := json.Unmarshal(respBody, &comment); err != nil {
+		return 0, fmt.Errorf("error unmarshaling JSON: %w", err)
+	}
+
+	return comment.ID, nil
+}
+
+// fetchGithubIssueComment returns the comment with the given ID, found is false when GitHub no longer has it
+func (r *GithubIssueReconciler) fetchGithubIssueComment(repo string, commentID int64, accessToken string) (GitHubComment, bool, error) {
+	var comment GitHubComment
+
+	status, respBody, err := r.sendGitHubRequest("GET", githubIssueCommentURL(repo, commentID), nil, accessToken)
+	if err != nil {
+		return comment, false, err
+	}
+	if status == http.StatusNotFound {
+		return comment, false, nil
+	}
+	if status != http.StatusOK {
+		return comment, false, &gitHubStatusError{StatusCode: status}
+	}
+
+	if err := json.Unmarshal(respBody, &comment); err != nil {
+		return comment, false, fmt.Errorf("error unmarshaling JSON: %w", err)
+	}
+
+	return comment, true, nil
+}
+
+func (r *GithubIssueReconciler) updateGithubIssueComment(ctx context.Context, repo string, commentID int64, body string, accessToken string) error {
+	payload := map[string]string{"body": body}
+	if r.dryRunRequest(ctx, "PATCH", githubIssueCommentURL(repo, commentID), payload) {
+		return nil
+	}
+
+	status, _, err := r.sendGitHubRequest("PATCH", githubIssueCommentURL(repo, commentID), payload, accessToken)
+	if err != nil {
+		return err
+	}
+	if status != http.StatusOK {
+		return &gitHubStatusError{StatusCode: status}
+	}
+
+	return nil
+}
+
+func (r *GithubIssueReconciler) deleteGithubIssueComment(ctx context.Context, repo string, commentID int64, accessToken string) error {
+	if r.dryRunRequest(ctx, "DELETE", githubIssueCommentURL(repo, commentID), nil) {
+		return nil
+	}
+
+	status, _, err := r.sendGitHubRequest("DELETE", githubIssueCommentURL(repo, commentID), nil, accessToken)
+	if err != nil {
+		return err
+	}
+	// A comment that is already gone needs no cleanup
+	if status != http.StatusNoContent && status != http.StatusNotFound {
+		return &gitHubStatusError{StatusCode: status}
+	}
+
+	return nil
+}
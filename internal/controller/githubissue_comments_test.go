@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+var _ = Describe("GithubIssue comments", func() {
+	const resourceName = "test-comments"
+
+	ctx := context.Background()
+	typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	var (
+		server   *httptest.Server
+		mu       sync.Mutex
+		comments map[string]string
+		nextID   int
+	)
+
+	BeforeEach(func() {
+		comments = map[string]string{}
+		nextID = 100
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			var payload map[string]string
+			_ = json.NewDecoder(req.Body).Decode(&payload)
+			id := req.URL.Path[strings.LastIndex(req.URL.Path, "/")+1:]
+
+			switch {
+			case req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/issues/1/comments"):
+				nextID++
+				comments[strconv.Itoa(nextID)] = payload["body"]
+				w.WriteHeader(http.StatusCreated)
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": nextID, "body": payload["body"]})
+			case req.Method == http.MethodGet:
+				body, ok := comments[id]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				commentID, _ := strconv.Atoi(id)
+				_ = json.NewEncoder(w).Encode(map[string]interface{}{"id": commentID, "body": body})
+			case req.Method == http.MethodPatch:
+				comments[id] = payload["body"]
+				w.WriteHeader(http.StatusOK)
+			case req.Method == http.MethodDelete:
+				delete(comments, id)
+				w.WriteHeader(http.StatusNoContent)
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}))
+
+		resource := &trainingv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec: trainingv1alpha1.GithubIssueSpec{
+				Repo:     server.URL + "/repos/owner/repo",
+				Title:    "title",
+				Comments: []trainingv1alpha1.GithubIssueComment{{Name: "status", Body: "first"}},
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		resource := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+	})
+
+	It("should create, edit and delete comments to match the spec", func() {
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+		ghi := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		Expect(reconciler.syncGithubIssueComments(ctx, ghi, ghi.Spec.Repo, "1", "token")).To(Succeed())
+
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		Expect(ghi.Status.Comments).To(HaveLen(1))
+		id := strconv.Itoa(int(ghi.Status.Comments[0].ID))
+		Expect(comments).To(HaveKeyWithValue(id, "first"))
+
+		By("editing the comment body")
+		ghi.Spec.Comments[0].Body = "second"
+		Expect(reconciler.syncGithubIssueComments(ctx, ghi, ghi.Spec.Repo, "1", "token")).To(Succeed())
+		Expect(comments).To(HaveKeyWithValue(id, "second"))
+
+		By("removing the comment from the spec")
+		ghi.Spec.Comments = nil
+		Expect(reconciler.syncGithubIssueComments(ctx, ghi, ghi.Spec.Repo, "1", "token")).To(Succeed())
+		Expect(comments).To(BeEmpty())
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		Expect(ghi.Status.Comments).To(BeEmpty())
+	})
+})
@@ -22,14 +22,16 @@ import (
 	"time"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
 
 	apiErrors "k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 
 	"bytes"
@@ -37,7 +39,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strings"
 )
 
@@ -49,7 +50,33 @@ const (
 // GithubIssueReconciler reconciles a GithubIssue object
 type GithubIssueReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+
+	// APIReader reads from the API server bypassing the cache, e.g. the GithubIssues sharing a fingerprint
+	APIReader client.Reader
+
+	// ResyncPeriod is how often GithubIssues are compared with GitHub when spec.resyncInterval is not set
+	ResyncPeriod time.Duration
+
+	// DryRun performs only read requests against GitHub, the writes are logged and reported on the GithubIssue instead
+	DryRun bool
+
+	// GitHubAPIURL replaces https://api.github.com in repository URLs when set, e.g. to use a fake GitHub server
+	GitHubAPIURL string
+
+	// Options configures the workers and rate limiter of the controller
+	Options ControllerOptions
+
+	// IssueCacheTTL is how long issues read in batches through the GitHub GraphQL API are reused by the reconciles
+	// of other GithubIssues, every issue is read with its own REST request when it is not set
+	IssueCacheTTL time.Duration
+
+	// issueCache holds the issues read in batches, shared by the reconciles of all GithubIssues
+	issueCache gitHubIssueCache
+
+	// repoLocks serializes the GitHub calls of GithubIssues filed in the same repository
+	repoLocks keyedMutex
 }
 
 // Define a struct to hold the relevant parts of the GitHub Search API response.
@@ -62,6 +89,7 @@ type GitHubSearchResponse struct {
 // +kubebuilder:rbac:groups=training.redhat.com,resources=githubissues,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=training.redhat.com,resources=githubissues/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=training.redhat.com,resources=githubissues/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=configmaps,verbs=get;list;watch;create;update;patch
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -95,6 +123,12 @@ func (r *GithubIssueReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return emptyResult, err
 	}
 
+	// In dry-run mode the skipped GitHub writes are collected and reported once the reconcile is done
+	if r.DryRun {
+		ctx = withDryRunLog(ctx)
+		defer r.reportDryRunRequests(ctx, ghi)
+	}
+
 	if !ghi.ObjectMeta.DeletionTimestamp.IsZero() && !controllerutil.ContainsFinalizer(ghi, myFinalizerName) {
 		// When CR doesn't include a finalizer and the CR deletionTimestamp exists
 		// then we can skip update, since it will be removed soon.
@@ -107,33 +141,79 @@ func (r *GithubIssueReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		// then let's add the finalizer and update the object. This is equivalent
 		// to registering our finalizer.
 
-		controllerutil.AddFinalizer(ghi, myFinalizerName)
 		log.Info("AddingFinalizer")
-		if err := r.Update(ctx, ghi); err != nil {
+		if err := r.patchGithubIssueFinalizer(ctx, ghi, true); err != nil {
 			return emptyResult, err
 		}
+		// Metadata changes are filtered by the predicates, so the reconcile is requeued explicitly
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// A suspended GithubIssue keeps its finalizer, so the issue is still closed on deletion once it is resumed
+	suspended, err := r.checkGithubIssueSuspended(ctx, ghi)
+	if err != nil {
+		return emptyResult, err
+	}
+	if suspended {
+		log.Info("GithubIssue is suspended, skipping reconcile")
 		return emptyResult, nil
 	}
 
-	accessToken := os.Getenv("SECRET_Token") // Read the environment variable
+	// The repository and token come from spec.repositoryRef, or from spec.repo and the SECRET_Token environment variable
+	repository, err := r.resolveGithubRepository(ctx, ghi)
+	if err != nil && !ghi.ObjectMeta.DeletionTimestamp.IsZero() {
+		// A repository or credentials Secret deleted first must not keep the GithubIssue, and its namespace, around
+		return r.abandonGithubIssue(ctx, ghi, err)
+	}
+	if err != nil {
+		log.Error(err, "Failed to resolve the GithubIssue repository")
+		return emptyResult, err
+	}
+	accessToken := repository.Token
+
+	// GithubIssuePolicies may restrict the repositories of the namespace, a forbidden repository is never contacted
+	forbidden, err := r.checkGithubIssuePolicy(ctx, ghi)
+	if err != nil {
+		log.Error(err, "Failed to check the GithubIssuePolicies")
+		return emptyResult, err
+	}
+	// An issue filed before the policy forbade the repository is still closed on deletion. Only the number
+	// recorded by the controller counts, the annotation could point the token at any issue of the repository.
+	if forbidden && (ghi.ObjectMeta.DeletionTimestamp.IsZero() || ghi.Status.IssueNumber == 0) {
+		if !ghi.ObjectMeta.DeletionTimestamp.IsZero() {
+			// Nothing was filed in the forbidden repository, so there is nothing to close
+			if err := r.patchGithubIssueFinalizer(ctx, ghi, false); err != nil {
+				return emptyResult, err
+			}
+			return emptyResult, nil
+		}
+		log.Info("Repository is forbidden by a GithubIssuePolicy", "repo", repository.URL)
+		return r.resyncResult(ghi), nil
+	}
+
+	// Concurrent workers never race on the issues of the same repository. Together with reading the GithubIssues
+	// sharing a fingerprint from the API server, this keeps them from filing the issue twice.
+	unlock := r.repoLocks.Lock(repository.URL)
+	defer unlock()
 
 	if accessToken == "" {
-		log.Info("SECRET_Token is not set")
-		return emptyResult, nil
+		log.Info("No token is set for the repository", "provider", repository.Provider)
+		return r.resyncResult(ghi), nil
 	}
 
-	// Extract `spec` field from cr
-	title := ghi.Spec.Title
-	description := ghi.Spec.Description
-	repo := string(ghi.Spec.Repo) + "/issues"
+	// Issues of other providers are reconciled through their issueTracker
+	if tracker := issueTrackerFor(repository); tracker != nil {
+		return r.reconcileTrackerIssue(ctx, ghi, tracker, repository, accessToken)
+	}
 
-	fmt.Printf("Title %s Description %s Repo %s \n", title, description, repo)
+	// Extract `spec` field from cr
+	repo := repository.URL + "/issues"
 
 	// Fetch issues from GitHub
-	body, err := r.fetchGitHubIssues(ghi.Spec.Repo, accessToken)
+	body, err := r.fetchGitHubIssues(repository.URL, accessToken)
 	if err != nil {
 		log.Info("Failed to fetch GitHub issues")
-		return emptyResult, nil
+		return r.resyncResult(ghi), nil
 	}
 
 	var gitHubIssues GitHubSearchResponse
@@ -141,8 +221,7 @@ func (r *GithubIssueReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 	// Parse the JSON
 	err = json.Unmarshal(body, &gitHubIssues)
 	if err != nil {
-		fmt.Print(err)
-		log.Info("Failed to parase response to JSon")
+		log.Error(err, "Failed to parse the GitHub search response")
 	}
 
 	// The object is being deleted
@@ -150,17 +229,16 @@ func (r *GithubIssueReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		// Delete CR only when a finalizer and DeletionTimestamp are set
 		// our finalizer is present, handle any external dependency
 
-		if err := r.closeGithubIssueFromCR(ghi, accessToken); err != nil {
+		if err := r.closeGithubIssueFromCR(ctx, ghi, repository.URL, accessToken); err != nil {
 			// if fail to delete the external dependency here, return with error
 			// so that it can be retried.
-			return emptyResult, err
+			return r.githubIssueSyncFailed(ctx, ghi, err)
 		}
 
 		log.Info("Trying RemoveFinalizer")
 
 		// remove our finalizer from the list and update it.
-		controllerutil.RemoveFinalizer(ghi, myFinalizerName)
-		if err := r.Update(ctx, ghi); err != nil {
+		if err := r.patchGithubIssueFinalizer(ctx, ghi, false); err != nil {
 			return emptyResult, err
 		}
 		log.Info("RemoveFinalizer")
@@ -168,171 +246,230 @@ func (r *GithubIssueReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return emptyResult, nil
 	}
 
-	if r.hasSpecificAnnotation(ghi) {
-		fmt.Printf("CR has the annotation key %s \n", annotationKey)
+	// GithubIssues sharing a fingerprint are labeled so they can find each other
+	if updated, err := r.ensureDedupLabel(ctx, ghi, repository.URL); err != nil || updated {
+		return ctrl.Result{Requeue: updated}, err
+	}
 
-		// 3. (Optional) Get the value of the annotation
-		value, _ := r.getSpecificAnnotationValue(ghi)
-		fmt.Printf("Annotation value key %s value %s \n", annotationKey, value)
+	// Title and description are rendered from spec.template when it is set
+	title, description, err := r.desiredTitleAndDescription(ctx, ghi)
+	if err != nil {
+		log.Error(err, "Failed to render GithubIssue template")
+		return emptyResult, err
+	}
 
-		// Now you can act based on the presence or value of the annotation
-		if value != "" {
-			// Do something specific
-			log.Info("Annotation value is true, performing action...")
-			ghiBySerial, err := r.fetchGitHubIssuesbyIssueNumber(value, repo, accessToken)
-			if err != nil {
-				fmt.Print(err)
-				log.Info("Failed to parase response to JSon")
-			}
+	log.V(1).Info("Desired GitHub issue", "title", title, "repo", repo)
+
+	if value := githubIssueNumber(ghi); value != "" {
+		log.Info("GitHub issue is filed, syncing it", "issue", value)
+		// Issues are read in batches shared with the other GithubIssues of the repository when --issue-cache-ttl is set
+		result, err := r.readGitHubIssue(ctx, repository.URL, value, accessToken)
+		if err != nil {
+			log.Error(err, "Failed to read the GitHub issue")
+			return r.githubIssueSyncFailed(ctx, ghi, err)
+		}
+		// Only the oldest of the GithubIssues sharing an issue keeps its title and body in sync
+		occurrences, primary, err := r.observeGithubIssueOccurrences(ctx, ghi, value)
+		if err != nil {
+			log.Error(err, "Failed to count GithubIssue occurrences")
+			return emptyResult, err
+		}
+		description = withOccurrences(ghi, description, occurrences)
 
-			// Declare a map to hold the unmarshaled JSON
-			var result map[string]interface{}
+		// A parent reports the progress of its children and lists the TaskList children at the end of its body
+		children, err := r.observeGithubIssueChildren(ctx, ghi)
+		if err != nil {
+			return emptyResult, err
+		}
+		description, err = r.withTaskList(ctx, ghi, repository, description, children)
+		if err != nil {
+			log.Error(err, "Failed to render the GithubIssue task list")
+			return emptyResult, err
+		}
 
-			// Unmarshal the JSON data into the map
-			err = json.Unmarshal(ghiBySerial, &result)
+		// Outside the Enforce mode differences are reported instead of overwritten
+		mode := githubIssueMode(ghi)
+		if err := r.recordGithubIssueDrift(ctx, ghi, githubIssueDrift(title, description, result), true); err != nil {
+			return emptyResult, err
+		}
+
+		// An issue closed because its target was resolved, or because spec.state was Closed, is reopened
+		// once the target is unhealthy again or the state is set back to Open
+		wasResolved := meta.IsStatusConditionTrue(ghi.Status.Conditions, conditionTargetResolved) ||
+			meta.IsStatusConditionTrue(ghi.Status.Conditions, conditionClosed)
+		targetResolved, err := r.observeGithubIssueTarget(ctx, ghi)
+		if err != nil {
+			log.Error(err, "Failed to observe GithubIssue target")
+			return emptyResult, err
+		}
+		stateClosed, err := r.observeGithubIssueState(ctx, ghi)
+		if err != nil {
+			return emptyResult, err
+		}
+
+		issueState, _ := result["state"].(string)
+		if targetResolved || stateClosed {
+			if result["state"] != "closed" {
+				log.Info("Closing GitHub issue", "targetResolved", targetResolved, "state", ghi.Spec.State)
+				if err := r.closeGithubIssueFromCR(ctx, ghi, repository.URL, accessToken); err != nil {
+					return r.githubIssueSyncFailed(ctx, ghi, err)
+				}
+				issueState = "closed"
+			}
+		} else if mode != modeObserveOnly && ((mode == modeEnforce && primary && (result["title"] != title || result["body"] != description)) ||
+			(wasResolved && result["state"] == "closed")) {
+			needUpdate, err := r.updateGitHubIssue(ctx, title, description, repo, value, accessToken)
 			if err != nil {
-				fmt.Printf("Error unmarshaling JSON: %v", err)
+				return r.githubIssueSyncFailed(ctx, ghi, err)
 			}
-			if result["title"] != title || result["body"] != description {
-				needUpdate, err := r.updateGitHubIssue(title, description, repo, value, accessToken)
-				if err != nil {
-					return emptyResult, err
-				}
-				if !needUpdate {
-					return emptyResult, nil
-				}
+			if !needUpdate {
+				return r.resyncResult(ghi), nil
 			}
+			issueState = "open"
+		}
 
-			return ctrl.Result{RequeueAfter: time.Minute}, nil
+		if mode != modeObserveOnly {
+			if err := r.syncGithubIssueComments(ctx, ghi, repository.URL, value, accessToken); err != nil {
+				log.Error(err, "Failed to sync GitHub issue comments")
+				return r.githubIssueSyncFailed(ctx, ghi, err)
+			}
+		}
+
+		if err := r.mirrorGithubIssueComments(ctx, ghi, repository.URL, value, accessToken); err != nil {
+			log.Error(err, "Failed to mirror GitHub issue comments")
+			return r.githubIssueSyncFailed(ctx, ghi, err)
+		}
 
+		if mode != modeObserveOnly {
+			nodeID, _ := result["node_id"].(string)
+			if err := r.syncGithubIssueProject(ctx, ghi, repository.URL, nodeID, accessToken); err != nil {
+				log.Error(err, "Failed to sync the GitHub project item")
+				return r.githubIssueSyncFailed(ctx, ghi, err)
+			}
+			if err := r.linkGithubIssueParent(ctx, ghi, repository.URL, nodeID, accessToken); err != nil {
+				log.Error(err, "Failed to add the GitHub issue to its parent")
+				return r.githubIssueSyncFailed(ctx, ghi, err)
+			}
 		}
 
-	} else { // No anttotaion filed, hence CR is on creation step
-		log.Info("CR does not have the annotation", "key", annotationKey)
+		// Children follow the state, so the parent can check them off its task list
+		if issueState != "" {
+			if err := r.recordGithubIssueState(ctx, ghi, issueState); err != nil {
+				return emptyResult, err
+			}
+		}
 
-		annotationValue, err := r.createGithubIssue(title, description, repo, accessToken)
-		if annotationValue == "" {
-			fmt.Printf("annotation value is empty string something went wrong")
+		if err := r.recordGithubIssueSynced(ctx, ghi, nil); err != nil {
 			return emptyResult, err
 		}
 
-		if err := r.UpdateGithubIssueAnnotation(ctx, req, annotationValue); err != nil {
+		return r.resyncResult(ghi), nil
+
+	}
+
+	// No issue filed, hence CR is on creation step
+	log.Info("CR does not have a GitHub issue number")
+
+	// ObserveOnly never files an issue, it waits for an existing one to be referenced
+	if githubIssueMode(ghi) == modeObserveOnly {
+		if err := r.recordGithubIssueDrift(ctx, ghi, nil, false); err != nil {
 			return emptyResult, err
 		}
-		log.Info("Reconciling createGithubIssue")
+		return r.resyncResult(ghi), nil
+	}
 
-		return emptyResult, nil
+	// A deduplicated GithubIssue shares the issue already filed for its fingerprint
+	annotationValue, err := r.adoptSharedGithubIssue(ctx, ghi, repository.URL, accessToken)
+	if err != nil {
+		log.Error(err, "Failed to look up GithubIssues sharing the fingerprint")
+		return emptyResult, err
+	}
+	if annotationValue == "" {
+		annotationValue, err = r.createGithubIssue(ctx, title, description, repo, issueLabels(ghi, repository), repository.Assignees, accessToken)
+	}
+	if annotationValue == "" && err == nil && r.DryRun {
+		return r.resyncResult(ghi), nil
+	}
+	if annotationValue == "" {
+		log.Info("No GitHub issue number was returned")
+		if err != nil {
+			return r.githubIssueSyncFailed(ctx, ghi, err)
+		}
+		return emptyResult, err
 	}
 
-	return emptyResult, nil
+	if err := r.recordGithubIssueNumber(ctx, ghi, annotationValue); err != nil {
+		return emptyResult, err
+	}
+	log.Info("Reconciling createGithubIssue")
 
+	// Status changes are filtered by the predicates, so the new issue is synced by an explicit requeue
+	return ctrl.Result{Requeue: true}, nil
 }
 
-func (r *GithubIssueReconciler) updateGitHubIssue(title string, description string, repo string, issueNumber string, accessToken string) (bool, error) {
-
-	fmt.Print(repo)
+func (r *GithubIssueReconciler) updateGitHubIssue(ctx context.Context, title string, description string, repo string, issueNumber string, accessToken string) (bool, error) {
+	log.FromContext(ctx).V(1).Info("Updating GitHub issue", "repo", repo, "issue", issueNumber)
 
 	// url := fmt.Sprintf("https://api.github.com/repos/Shai1-Levi/githubissues-operator/issues/%s", issueNumber)
 	url := repo + "/" + issueNumber
 
-	ans, e := r.updateGitHubIssuefileds(title, description, url, accessToken)
+	ans, e := r.updateGitHubIssuefileds(ctx, title, description, url, accessToken)
 	if e != nil {
 		return false, fmt.Errorf("failed to update issue fields: %w", e)
 	}
+	r.forgetGitHubIssue(repo, issueNumber)
 
 	return ans, nil
 
 }
 
-func (r *GithubIssueReconciler) hasSpecificAnnotation(obj metav1.Object) bool {
-	annotations := obj.GetAnnotations()
-	if annotations == nil {
-		return false // No annotations at all
-	}
-	_, exists := annotations[annotationKey]
-	return exists
-}
-
-func (r *GithubIssueReconciler) getSpecificAnnotationValue(obj metav1.Object) (string, bool) {
-	annotations := obj.GetAnnotations()
-	if annotations == nil {
-		return "", false // No annotations at all
-	}
-	value, exists := annotations[annotationKey]
-	return value, exists
-}
-
-// Function to add or update an annotation on a GithubIssue CR
-func (r *GithubIssueReconciler) UpdateGithubIssueAnnotation(
-	ctx context.Context,
-	req ctrl.Request,
-	annotationValue string,
-) error {
-	logger := log.FromContext(ctx).WithValues("githubissue", req.NamespacedName)
-
-	// 1. Get the GithubIssue CR
-	githubIssue := &trainingv1alpha1.GithubIssue{}
-	if err := r.Get(ctx, req.NamespacedName, githubIssue); err != nil {
-		if apiErrors.IsNotFound(err) {
-			logger.Info("GithubIssue not found, cannot update annotation.")
-			return nil // Or return error if this is unexpected
-		}
-		logger.Error(err, "Failed to get GithubIssue to update annotation")
-		return err
-	}
-
-	// 2. Modify Annotations
-	// Initialize the Annotations map if it's nil
-	if githubIssue.ObjectMeta.Annotations == nil {
-		githubIssue.ObjectMeta.Annotations = make(map[string]string)
-	}
-
-	// Check if the annotation value actually needs updating
-	currentValue, exists := githubIssue.ObjectMeta.Annotations[annotationKey]
-	if exists && currentValue == annotationValue {
-		logger.Info("Annotation already has the desired value, no update needed.", "key", annotationKey, "value", annotationValue)
+func (r *GithubIssueReconciler) closeGithubIssueFromCR(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, repoURL string, accessToken string) error {
+	// An observed issue is left as it is
+	if githubIssueMode(ghi) == modeObserveOnly {
 		return nil
 	}
 
-	// Add or update the annotation
-	githubIssue.ObjectMeta.Annotations[annotationKey] = annotationValue
-	logger.Info("Updating annotation", "key", annotationKey, "value", annotationValue)
-
-	// 3. Update the GithubIssue CR
-	if err := r.Client.Update(ctx, githubIssue); err != nil {
-		logger.Error(err, "Failed to update GithubIssue with new annotation")
+	// A shared issue stays open until the last GithubIssue referencing it goes away
+	sharing, err := r.sharingGithubIssues(ctx, ghi)
+	if err != nil {
 		return err
 	}
-
-	logger.Info("Successfully updated GithubIssue annotation", "key", annotationKey, "value", annotationValue)
-	return nil
-}
-
-func (r *GithubIssueReconciler) closeGithubIssueFromCR(ghi *trainingv1alpha1.GithubIssue, accessToken string) error {
+	for _, other := range sharing {
+		if githubIssueNumber(&other) == githubIssueNumber(ghi) {
+			log.FromContext(ctx).Info("Not closing the GitHub issue, other GithubIssues still reference it",
+				"issue", githubIssueNumber(ghi))
+			return nil
+		}
+	}
 
 	title := ghi.Spec.Title
 	description := ghi.Spec.Description
-	repo := ghi.Spec.Repo + "/issues"
+	if ghi.Spec.Template != nil {
+		// The referenced data may already be gone, closing the issue must not depend on it
+		if renderedTitle, renderedDescription, err := r.renderGithubIssueTemplate(ctx, ghi); err == nil {
+			title, description = renderedTitle, renderedDescription
+		}
+	}
+	repo := repoURL + "/issues"
 
 	// 3. (Optional) Get the value of the annotation
-	annotationValue, _ := r.getSpecificAnnotationValue(ghi)
-	fmt.Printf("Annotation value key %s value %s \n", annotationKey, annotationValue)
+	annotationValue := githubIssueNumber(ghi)
 
 	// Now you can act based on the presence or value of the annotation
 	if annotationValue != "" {
-		// Do something specific
-		fmt.Printf("Annotation value is true, performing action...")
-		if _, err := r.closeGithubIssue(title, description, repo, annotationValue, accessToken); err != nil {
+		log.FromContext(ctx).V(1).Info("Closing GitHub issue", "repo", repo, "issue", annotationValue)
+		if _, err := r.closeGithubIssue(ctx, title, description, repo, annotationValue, accessToken); err != nil {
 			// if fail to delete the external dependency here, return with error
 			// so that it can be retried.
 			return err
 		}
+		r.forgetGitHubIssue(repo, annotationValue)
 	}
 
 	return nil
 }
 
-func (r *GithubIssueReconciler) updateGitHubIssuefileds(title string, description string, repo string, accessToken string) (bool, error) {
+func (r *GithubIssueReconciler) updateGitHubIssuefileds(ctx context.Context, title string, description string, repo string, accessToken string) (bool, error) {
 	// Trim spaces and newlines from the token
 	tokenStr := strings.TrimSpace(accessToken)
 
@@ -348,6 +485,9 @@ func (r *GithubIssueReconciler) updateGitHubIssuefileds(title string, descriptio
 		Body:  description,
 		State: "open",
 	}
+	if r.dryRunRequest(ctx, "PATCH", repo, payload) {
+		return true, nil
+	}
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return false, fmt.Errorf("error marshaling JSON: %w", err)
@@ -370,8 +510,7 @@ func (r *GithubIssueReconciler) updateGitHubIssuefileds(title string, descriptio
 	req.Header.Add("X-GitHub-Api-Version", "2022-11-28")
 
 	// Create HTTP client and send request
-	client := &http.Client{Timeout: 1 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := gitHubHTTPClient.Do(req)
 	if err != nil {
 		fmt.Printf("error reading token: \n")
 		return false, fmt.Errorf("error sending request: %w", err)
@@ -380,21 +519,21 @@ func (r *GithubIssueReconciler) updateGitHubIssuefileds(title string, descriptio
 
 	// Check response status
 	if resp.StatusCode != http.StatusOK {
-		return false, fmt.Errorf("GitHub API returned status: %d", resp.StatusCode)
+		return false, newGitHubStatusError(resp)
 	}
 
 	return true, nil
 }
 
-func (r *GithubIssueReconciler) closeGithubIssue(title string, description string, url string, issueNumber string, accessToken string) (ctrl.Result, error) {
+func (r *GithubIssueReconciler) closeGithubIssue(ctx context.Context, title string, description string, url string, issueNumber string, accessToken string) (ctrl.Result, error) {
 
 	// Trim spaces and newlines from the token
 	tokenStr := strings.TrimSpace(accessToken)
 
 	// JSON payload for the issue
 	type IssuePayload struct {
-		Title string `json:"title"`
-		Body  string `json:"body"`
+		Title string `json:"title,omitempty"`
+		Body  string `json:"body,omitempty"`
 		State string `json:"state"`
 	}
 
@@ -410,6 +549,9 @@ func (r *GithubIssueReconciler) closeGithubIssue(title string, description strin
 	jsonStr := string(jsonData)
 
 	repo := url + "/" + issueNumber
+	if r.dryRunRequest(ctx, "PATCH", repo, payload) {
+		return ctrl.Result{}, nil
+	}
 
 	// Create a new HTTP request
 	req, err := http.NewRequest("PATCH", repo, bytes.NewBuffer([]byte(jsonStr)))
@@ -427,8 +569,7 @@ func (r *GithubIssueReconciler) closeGithubIssue(title string, description strin
 	req.Header.Add("X-GitHub-Api-Version", "2022-11-28")
 
 	// Create HTTP client and send request
-	client := &http.Client{Timeout: 1 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := gitHubHTTPClient.Do(req)
 	if err != nil {
 		fmt.Printf("error reading token: \n")
 		return ctrl.Result{}, fmt.Errorf("error sending request: %w", err)
@@ -440,28 +581,36 @@ func (r *GithubIssueReconciler) closeGithubIssue(title string, description strin
 	}()
 
 	if resp.StatusCode != http.StatusOK {
-		return ctrl.Result{}, fmt.Errorf("GitHub API returned status: %d", resp.StatusCode)
+		return ctrl.Result{}, newGitHubStatusError(resp)
 	}
 
 	return ctrl.Result{}, nil
 }
 
-func (r *GithubIssueReconciler) createGithubIssue(title string, description string, repo string, accessToken string) (string, error) {
+func (r *GithubIssueReconciler) createGithubIssue(ctx context.Context, title string, description string, repo string, labels []string, assignees []string, accessToken string) (string, error) {
 
 	// Trim spaces and newlines from the token
 	tokenStr := strings.TrimSpace(accessToken)
 
 	// JSON payload for the issue
 	type IssuePayload struct {
-		Title string `json:"title"`
-		Body  string `json:"body"`
-		State string `json:"state"`
+		Title     string   `json:"title"`
+		Body      string   `json:"body"`
+		State     string   `json:"state"`
+		Labels    []string `json:"labels,omitempty"`
+		Assignees []string `json:"assignees,omitempty"`
 	}
 
 	payload := IssuePayload{
-		Title: title,
-		Body:  description,
-		State: "open",
+		Title:     title,
+		Body:      description,
+		State:     "open",
+		Labels:    labels,
+		Assignees: assignees,
+	}
+	// No issue is filed in dry-run mode, so there is no issue number to return
+	if r.dryRunRequest(ctx, "POST", repo, payload) {
+		return "", nil
 	}
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
@@ -487,8 +636,7 @@ func (r *GithubIssueReconciler) createGithubIssue(title string, description stri
 	req.Header.Add("X-GitHub-Api-Version", "2022-11-28")
 
 	// Create HTTP client and send request
-	client := &http.Client{Timeout: 1 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := gitHubHTTPClient.Do(req)
 	if err != nil {
 		fmt.Printf("error reading token: \n")
 		return "", fmt.Errorf("error sending request: %w", err)
@@ -497,7 +645,7 @@ func (r *GithubIssueReconciler) createGithubIssue(title string, description stri
 
 	// Check response status
 	if resp.StatusCode != http.StatusCreated {
-		return "", fmt.Errorf("GitHub API returned status: %d", resp.StatusCode)
+		return "", newGitHubStatusError(resp)
 	}
 
 	// Read response body
@@ -587,7 +735,9 @@ func (r *GithubIssueReconciler) fetchGitHubIssues(repo, accessToken string) ([]b
 	if !boolean || owner_repo == "" {
 		return nil, fmt.Errorf("failed to get owner_repo")
 	}
-	url := "https://api.github.com/search/issues?q=repo:" + owner_repo + "+type:issue+state:open"
+	// The search API lives next to the repos API, which keeps GitHub Enterprise base URLs working
+	baseURL := strings.TrimSuffix(repo[:strings.Index(repo, "repos/")], "/")
+	url := baseURL + "/search/issues?q=repo:" + owner_repo + "+type:issue+state:open"
 
 	// Create a new HTTP request
 	req, err := http.NewRequest("GET", url, nil)
@@ -601,8 +751,7 @@ func (r *GithubIssueReconciler) fetchGitHubIssues(repo, accessToken string) ([]b
 	req.Header.Add("X-GitHub-Api-Version", "2022-11-28")
 
 	// Send request
-	client := &http.Client{Timeout: 1 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := gitHubHTTPClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("error sending request: %w", err)
 	}
@@ -617,17 +766,53 @@ func (r *GithubIssueReconciler) fetchGitHubIssues(repo, accessToken string) ([]b
 	return body, nil
 }
 
-// fetchGitHubIssues reads the token, sends the request, and returns the response body
+// fetchGitHubIssuesbyIssueNumber reads the issue with the REST API and returns the response body. A response
+// other than 200 is returned as a gitHubStatusError, its body is an error message rather than the issue.
 func (r *GithubIssueReconciler) fetchGitHubIssuesbyIssueNumber(issueNumber, repo, accessToken string) ([]byte, error) {
+	resp, body, err := doGitHubHTTPRequest(http.MethodGet, repo+"/"+issueNumber, nil, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, newGitHubStatusError(resp)
+	}
+	return body, nil
+}
+
+// sendGitHubRequest sends an authenticated request to the GitHub REST API and returns the response status code and body.
+// payload is marshaled to JSON when it is not nil.
+func (r *GithubIssueReconciler) sendGitHubRequest(method, url string, payload interface{}, accessToken string) (int, []byte, error) {
+	return doGitHubRequest(method, url, payload, accessToken)
+}
 
+// doGitHubRequest implements sendGitHubRequest for the controllers that do not reconcile GithubIssues
+func doGitHubRequest(method, url string, payload interface{}, accessToken string) (int, []byte, error) {
+	resp, body, err := doGitHubHTTPRequest(method, url, payload, accessToken)
+	if resp == nil {
+		return 0, nil, err
+	}
+	return resp.StatusCode, body, err
+}
+
+// doGitHubHTTPRequest is doGitHubRequest returning the whole response, whose headers tell a request rejected by
+// the rate limit from a forbidden one, see newGitHubStatusError. The body of the response is already read.
+func doGitHubHTTPRequest(method, url string, payload interface{}, accessToken string) (*http.Response, []byte, error) {
 	// Trim spaces and newlines from the token
 	tokenStr := strings.TrimSpace(accessToken)
-	url := repo + "/" + issueNumber
+
+	var reqBody io.Reader
+	if payload != nil {
+		jsonData, err := json.Marshal(payload)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error marshaling JSON: %w", err)
+		}
+		reqBody = bytes.NewBuffer(jsonData)
+	}
 
 	// Create a new HTTP request
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequest(method, url, reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("error creating request: %w", err)
+		return nil, nil, fmt.Errorf("error creating request: %w", err)
 	}
 
 	// Set headers
@@ -636,25 +821,27 @@ func (r *GithubIssueReconciler) fetchGitHubIssuesbyIssueNumber(issueNumber, repo
 	req.Header.Add("X-GitHub-Api-Version", "2022-11-28")
 
 	// Send request
-	client := &http.Client{Timeout: 1 * time.Second}
-	resp, err := client.Do(req)
+	resp, err := gitHubHTTPClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("error sending request: %w", err)
+		return nil, nil, fmt.Errorf("error sending request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	// Read response body
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("error reading response: %w", err)
+		return resp, nil, fmt.Errorf("error reading response: %w", err)
 	}
 
-	return body, nil
+	return resp, body, nil
 }
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *GithubIssueReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&trainingv1alpha1.GithubIssue{}).
+		For(&trainingv1alpha1.GithubIssue{}, builder.WithPredicates(githubIssuePredicate())).
+		// Parents follow the issue state of their children, children wait for the issue of their parent
+		Watches(&trainingv1alpha1.GithubIssue{}, r.githubIssueFamilyHandler(), builder.WithPredicates(githubIssueFamilyPredicate())).
+		WithOptions(r.Options.controllerOptions()).
 		Complete(r)
 }
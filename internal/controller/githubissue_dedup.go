@@ -0,0 +1,192 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+const (
+	// dedupLabelKey is set on deduplicated GithubIssues to a hash of their repository and fingerprint,
+	// GithubIssues with the same value share one GitHub issue
+	dedupLabelKey = "github-issue.kubebuilder.io/dedup"
+
+	occurrencesCount   = "Count"
+	occurrencesComment = "Comment"
+)
+
+// githubIssueFingerprint returns the fingerprint identifying the problem a deduplicated GithubIssue describes
+func githubIssueFingerprint(ghi *trainingv1alpha1.GithubIssue) string {
+	if ghi.Spec.Deduplication.Fingerprint != "" {
+		return ghi.Spec.Deduplication.Fingerprint
+	}
+	if fingerprint := ghi.Labels[fingerprintLabelKey]; fingerprint != "" {
+		return fingerprint
+	}
+	return ghi.Spec.Title
+}
+
+// dedupKey returns the dedupLabelKey value of a fingerprint in a repository
+func dedupKey(repoURL string, fingerprint string) string {
+	sum := sha256.Sum256([]byte(repoURL + "\n" + fingerprint))
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// ensureDedupLabel sets or removes the dedupLabelKey label according to spec.deduplication.
+// It returns true when the GithubIssue was updated.
+func (r *GithubIssueReconciler) ensureDedupLabel(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, repoURL string) (bool, error) {
+	base := ghi.DeepCopy()
+	current, labeled := ghi.Labels[dedupLabelKey]
+	switch {
+	case ghi.Spec.Deduplication == nil && !labeled:
+		return false, nil
+	case ghi.Spec.Deduplication == nil:
+		delete(ghi.Labels, dedupLabelKey)
+	default:
+		key := dedupKey(repoURL, githubIssueFingerprint(ghi))
+		if current == key {
+			return false, nil
+		}
+		if ghi.Labels == nil {
+			ghi.Labels = map[string]string{}
+		}
+		ghi.Labels[dedupLabelKey] = key
+	}
+
+	if err := r.Patch(ctx, ghi, client.MergeFrom(base)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update GithubIssue deduplication label")
+		return false, err
+	}
+	return true, nil
+}
+
+// sharingGithubIssues returns the other GithubIssues of the namespace that share the fingerprint and repository
+// of ghi, have an issue filed and are not being deleted, oldest first. GithubIssues of other namespaces are never
+// shared, a tenant must not adopt, edit or close the issues of another. They are read from the API server, the
+// cache may not show the issue another GithubIssue filed right before yet.
+func (r *GithubIssueReconciler) sharingGithubIssues(ctx context.Context, ghi *trainingv1alpha1.GithubIssue) ([]trainingv1alpha1.GithubIssue, error) {
+	key, ok := ghi.Labels[dedupLabelKey]
+	if !ok || ghi.Spec.Deduplication == nil {
+		return nil, nil
+	}
+
+	issues := &trainingv1alpha1.GithubIssueList{}
+	if err := r.apiReader().List(ctx, issues, client.InNamespace(ghi.Namespace), client.MatchingLabels{dedupLabelKey: key}); err != nil {
+		return nil, fmt.Errorf("failed to list GithubIssues sharing the fingerprint: %w", err)
+	}
+
+	var sharing []trainingv1alpha1.GithubIssue
+	for _, issue := range issues.Items {
+		if issue.Name == ghi.Name {
+			continue
+		}
+		if !issue.DeletionTimestamp.IsZero() || githubIssueNumber(&issue) == "" {
+			continue
+		}
+		sharing = append(sharing, issue)
+	}
+	sort.Slice(sharing, func(i, j int) bool { return olderGithubIssue(&sharing[i], &sharing[j]) })
+	return sharing, nil
+}
+
+// apiReader returns APIReader, or the client when it is not set
+func (r *GithubIssueReconciler) apiReader() client.Reader {
+	if r.APIReader == nil {
+		return r.Client
+	}
+	return r.APIReader
+}
+
+// olderGithubIssue orders GithubIssues by creation, the oldest GithubIssue sharing an issue owns its title and body
+func olderGithubIssue(a, b *trainingv1alpha1.GithubIssue) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Namespace+"/"+a.Name < b.Namespace+"/"+b.Name
+}
+
+// adoptSharedGithubIssue returns the number of the GitHub issue already filed for the fingerprint of ghi,
+// or an empty string when there is none. In the Comment occurrences mode the adoption is commented on the issue.
+func (r *GithubIssueReconciler) adoptSharedGithubIssue(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, repoURL string, accessToken string) (string, error) {
+	sharing, err := r.sharingGithubIssues(ctx, ghi)
+	if err != nil || len(sharing) == 0 {
+		return "", err
+	}
+	issueNumber := githubIssueNumber(&sharing[0])
+
+	if ghi.Spec.Deduplication.Occurrences == occurrencesComment {
+		body := fmt.Sprintf("Also reported by GithubIssue %s/%s", ghi.Namespace, ghi.Name)
+		if _, err := r.createGithubIssueComment(ctx, repoURL, issueNumber, body, accessToken); err != nil {
+			return "", fmt.Errorf("failed to comment the occurrence: %w", err)
+		}
+	}
+
+	log.FromContext(ctx).Info("Sharing the GitHub issue of an existing GithubIssue",
+		"issue", issueNumber, "githubissue", sharing[0].Namespace+"/"+sharing[0].Name)
+	return issueNumber, nil
+}
+
+// observeGithubIssueOccurrences counts the GithubIssues sharing the GitHub issue and records it in status.occurrences.
+// It returns the count and whether ghi is the oldest of them, which is the one keeping the title and body in sync.
+func (r *GithubIssueReconciler) observeGithubIssueOccurrences(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, issueNumber string) (int, bool, error) {
+	if ghi.Spec.Deduplication == nil {
+		return 1, true, nil
+	}
+
+	sharing, err := r.sharingGithubIssues(ctx, ghi)
+	if err != nil {
+		return 0, false, err
+	}
+
+	occurrences, primary := 1, true
+	for i := range sharing {
+		if githubIssueNumber(&sharing[i]) != issueNumber {
+			continue
+		}
+		occurrences++
+		if olderGithubIssue(&sharing[i], ghi) {
+			primary = false
+		}
+	}
+
+	if ghi.Status.Occurrences != int32(occurrences) {
+		base := ghi.DeepCopy()
+		ghi.Status.Occurrences = int32(occurrences)
+		if err := r.patchGithubIssueStatus(ctx, ghi, base); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to update GithubIssue status")
+			return 0, false, err
+		}
+	}
+	return occurrences, primary, nil
+}
+
+// withOccurrences appends the occurrence count to the issue body in the Count occurrences mode
+func withOccurrences(ghi *trainingv1alpha1.GithubIssue, description string, occurrences int) string {
+	if ghi.Spec.Deduplication == nil || ghi.Spec.Deduplication.Occurrences == occurrencesComment || occurrences < 2 {
+		return description
+	}
+	return fmt.Sprintf("%s\n\n---\nOccurrences: %d", description, occurrences)
+}
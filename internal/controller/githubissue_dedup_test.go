@@ -0,0 +1,163 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+var _ = Describe("GithubIssue deduplication", func() {
+	ctx := context.Background()
+	names := []string{"test-dedup-first", "test-dedup-second"}
+
+	var (
+		server   *httptest.Server
+		mu       sync.Mutex
+		requests []string
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			requests = append(requests, req.Method+" "+req.URL.Path)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": 1}`))
+		}))
+
+		for _, name := range names {
+			resource := &trainingv1alpha1.GithubIssue{
+				ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+				Spec: trainingv1alpha1.GithubIssueSpec{
+					Repo:          server.URL + "/repos/owner/repo",
+					Title:         name,
+					Deduplication: &trainingv1alpha1.DeduplicationSpec{Fingerprint: "disk-full", Occurrences: occurrencesComment},
+				},
+			}
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		}
+	})
+
+	AfterEach(func() {
+		server.Close()
+		for _, name := range names {
+			resource := &trainingv1alpha1.GithubIssue{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		}
+	})
+
+	It("should share one GitHub issue between GithubIssues with the same fingerprint", func() {
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		repoURL := server.URL + "/repos/owner/repo"
+
+		issues := make([]*trainingv1alpha1.GithubIssue, len(names))
+		for i, name := range names {
+			issues[i] = &trainingv1alpha1.GithubIssue{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, issues[i])).To(Succeed())
+			updated, err := reconciler.ensureDedupLabel(ctx, issues[i], repoURL)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(updated).To(BeTrue())
+		}
+		Expect(issues[0].Labels[dedupLabelKey]).To(Equal(issues[1].Labels[dedupLabelKey]))
+
+		By("adopting the issue filed for the first GithubIssue")
+		issueNumber, err := reconciler.adoptSharedGithubIssue(ctx, issues[1], repoURL, "token")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issueNumber).To(BeEmpty())
+
+		issues[0].Annotations = map[string]string{annotationKey: "7"}
+		Expect(k8sClient.Update(ctx, issues[0])).To(Succeed())
+
+		issueNumber, err = reconciler.adoptSharedGithubIssue(ctx, issues[1], repoURL, "token")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issueNumber).To(Equal("7"))
+		Expect(requests).To(ConsistOf("POST /repos/owner/repo/issues/7/comments"))
+
+		issues[1].Annotations = map[string]string{annotationKey: issueNumber}
+		Expect(k8sClient.Update(ctx, issues[1])).To(Succeed())
+
+		By("counting the occurrences")
+		occurrences, primary, err := reconciler.observeGithubIssueOccurrences(ctx, issues[0], "7")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(occurrences).To(Equal(2))
+		Expect(primary).To(BeTrue())
+		Expect(issues[0].Status.Occurrences).To(Equal(int32(2)))
+
+		_, primary, err = reconciler.observeGithubIssueOccurrences(ctx, issues[1], "7")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(primary).To(BeFalse())
+
+		By("keeping the issue open while another GithubIssue references it")
+		requests = nil
+		Expect(reconciler.closeGithubIssueFromCR(ctx, issues[0], repoURL, "token")).To(Succeed())
+		Expect(requests).To(BeEmpty())
+	})
+
+	It("should not share the GitHub issue of another namespace", func() {
+		ns := &corev1.Namespace{}
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: "dedup-other"}, ns); apiErrors.IsNotFound(err) {
+			Expect(k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "dedup-other"}})).To(Succeed())
+		}
+		repoURL := server.URL + "/repos/owner/repo"
+		other := &trainingv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: names[0], Namespace: "dedup-other", Annotations: map[string]string{annotationKey: "7"}},
+			Spec: trainingv1alpha1.GithubIssueSpec{
+				Repo:          repoURL,
+				Title:         names[0],
+				Deduplication: &trainingv1alpha1.DeduplicationSpec{Fingerprint: "disk-full"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, other)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, other)
+
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), APIReader: k8sClient}
+		_, err := reconciler.ensureDedupLabel(ctx, other, repoURL)
+		Expect(err).NotTo(HaveOccurred())
+		ghi := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, types.NamespacedName{Name: names[1], Namespace: "default"}, ghi)).To(Succeed())
+		_, err = reconciler.ensureDedupLabel(ctx, ghi, repoURL)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ghi.Labels[dedupLabelKey]).To(Equal(other.Labels[dedupLabelKey]))
+
+		issueNumber, err := reconciler.adoptSharedGithubIssue(ctx, ghi, repoURL, "token")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(issueNumber).To(BeEmpty())
+		Expect(requests).To(BeEmpty())
+	})
+
+	It("should append the occurrence count in the Count mode", func() {
+		ghi := &trainingv1alpha1.GithubIssue{Spec: trainingv1alpha1.GithubIssueSpec{
+			Deduplication: &trainingv1alpha1.DeduplicationSpec{Occurrences: occurrencesCount},
+		}}
+		Expect(withOccurrences(ghi, "body", 1)).To(Equal("body"))
+		Expect(withOccurrences(ghi, "body", 3)).To(Equal("body\n\n---\nOccurrences: 3"))
+	})
+})
@@ -0,0 +1,122 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+const (
+	modeEnforce     = "Enforce"
+	modeObserveOnly = "ObserveOnly"
+	modeCreateOnly  = "CreateOnly"
+
+	// conditionDrifted reports whether the GitHub issue differs from the spec while it is not enforced
+	conditionDrifted = "Drifted"
+
+	// maxDriftValueLength bounds the values recorded in status.drift, issue bodies can be large
+	maxDriftValueLength = 1024
+)
+
+// githubIssueMode returns spec.mode, defaulting to Enforce
+func githubIssueMode(ghi *trainingv1alpha1.GithubIssue) string {
+	if ghi.Spec.Mode == "" {
+		return modeEnforce
+	}
+	return ghi.Spec.Mode
+}
+
+// githubIssueDrift compares the desired title and body with the issue read from GitHub
+func githubIssueDrift(title string, description string, issue map[string]interface{}) []trainingv1alpha1.DriftedField {
+	var drift []trainingv1alpha1.DriftedField
+	for _, field := range []struct{ name, desired string }{{"title", title}, {"body", description}} {
+		actual, _ := issue[field.name].(string)
+		if actual == field.desired {
+			continue
+		}
+		drift = append(drift, trainingv1alpha1.DriftedField{
+			Field:   field.name,
+			Desired: truncateDriftValue(field.desired),
+			Actual:  truncateDriftValue(actual),
+		})
+	}
+	return drift
+}
+
+func truncateDriftValue(value string) string {
+	if len(value) <= maxDriftValueLength {
+		return value
+	}
+	return value[:maxDriftValueLength] + "..."
+}
+
+// recordGithubIssueDrift writes the Drifted condition and status.drift when they changed. In the Enforce mode
+// drift is corrected rather than reported, so a previously recorded report is removed.
+func (r *GithubIssueReconciler) recordGithubIssueDrift(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, drift []trainingv1alpha1.DriftedField, filed bool) error {
+	base := ghi.DeepCopy()
+	changed := false
+	if githubIssueMode(ghi) == modeEnforce {
+		changed = meta.RemoveStatusCondition(&ghi.Status.Conditions, conditionDrifted)
+		drift = nil
+	} else {
+		condition := metav1.Condition{
+			Type:               conditionDrifted,
+			Status:             metav1.ConditionFalse,
+			Reason:             "InSync",
+			Message:            "GitHub issue matches the spec",
+			ObservedGeneration: ghi.Generation,
+		}
+		switch {
+		case !filed:
+			condition.Status = metav1.ConditionUnknown
+			condition.Reason = "NotFiled"
+			condition.Message = fmt.Sprintf("No GitHub issue is referenced, set the %s annotation to observe an existing issue", annotationKey)
+		case len(drift) > 0:
+			fields := make([]string, 0, len(drift))
+			for _, field := range drift {
+				fields = append(fields, field.Field)
+			}
+			condition.Status = metav1.ConditionTrue
+			condition.Reason = "Drifted"
+			condition.Message = "GitHub issue differs from the spec in " + strings.Join(fields, ", ")
+		}
+		changed = meta.SetStatusCondition(&ghi.Status.Conditions, condition)
+	}
+
+	if !equality.Semantic.DeepEqual(ghi.Status.Drift, drift) {
+		ghi.Status.Drift = drift
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := r.patchGithubIssueStatus(ctx, ghi, base); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update GithubIssue status")
+		return err
+	}
+	return nil
+}
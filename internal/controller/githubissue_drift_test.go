@@ -0,0 +1,117 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+var _ = Describe("GithubIssue reconcile modes", func() {
+	const resourceName = "test-observe-only"
+
+	ctx := context.Background()
+	typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	var (
+		server *httptest.Server
+		mu     sync.Mutex
+		writes []string
+	)
+
+	BeforeEach(func() {
+		writes = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			switch {
+			case req.Method != http.MethodGet:
+				writes = append(writes, req.Method+" "+req.URL.Path)
+				w.WriteHeader(http.StatusOK)
+			case strings.HasPrefix(req.URL.Path, "/search/"):
+				_, _ = w.Write([]byte(`{"total_count": 0, "items": []}`))
+			case req.URL.Path == "/repos/owner/repo/issues/7":
+				_, _ = w.Write([]byte(`{"number": 7, "title": "edited on GitHub", "body": "body", "state": "open"}`))
+			default:
+				w.WriteHeader(http.StatusNotFound)
+			}
+		}))
+		Expect(os.Setenv(tokenEnvVar, "token")).To(Succeed())
+
+		resource := &trainingv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        resourceName,
+				Namespace:   "default",
+				Annotations: map[string]string{annotationKey: "7"},
+			},
+			Spec: trainingv1alpha1.GithubIssueSpec{
+				Repo:        server.URL + "/repos/owner/repo",
+				Title:       "title",
+				Description: "body",
+				Mode:        modeObserveOnly,
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		Expect(os.Unsetenv(tokenEnvVar)).To(Succeed())
+
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		resource := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(writes).To(BeEmpty())
+	})
+
+	It("should report drift in ObserveOnly without writing to GitHub", func() {
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+		By("adding the finalizer and then observing the issue")
+		for i := 0; i < 2; i++ {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(writes).To(BeEmpty())
+
+		ghi := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		condition := meta.FindStatusCondition(ghi.Status.Conditions, conditionDrifted)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(ghi.Status.Drift).To(ConsistOf(trainingv1alpha1.DriftedField{
+			Field: "title", Desired: "title", Actual: "edited on GitHub",
+		}))
+	})
+})
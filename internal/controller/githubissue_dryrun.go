@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=core,resources=events,verbs=create;patch
+
+// dryRunLogKey is the context key of the GitHub writes skipped during a reconcile
+type dryRunLogKey struct{}
+
+type dryRunLog struct {
+	mu       sync.Mutex
+	requests []trainingv1alpha1.DryRunRequest
+}
+
+// withDryRunLog returns a context collecting the GitHub writes skipped in dry-run mode
+func withDryRunLog(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunLogKey{}, &dryRunLog{})
+}
+
+// dryRunRequest returns true when the manager runs in dry-run mode, in which case the write is logged and
+// collected for the GithubIssue status instead of being sent to GitHub
+func (r *GithubIssueReconciler) dryRunRequest(ctx context.Context, method string, url string, payload interface{}) bool {
+	if !r.DryRun {
+		return false
+	}
+	skipDryRunRequest(ctx, method, url, payload)
+	return true
+}
+
+// skipDryRunRequest logs a GitHub write skipped in dry-run mode and collects it in the dry-run log of ctx
+func skipDryRunRequest(ctx context.Context, method string, url string, payload interface{}) {
+	request := trainingv1alpha1.DryRunRequest{Method: method, URL: url}
+	if payload != nil {
+		if data, err := json.Marshal(payload); err == nil {
+			request.Payload = string(data)
+		}
+	}
+	log.FromContext(ctx).Info("Dry run, skipping GitHub request", "method", method, "url", url, "payload", request.Payload)
+
+	if dryRun, ok := ctx.Value(dryRunLogKey{}).(*dryRunLog); ok {
+		dryRun.mu.Lock()
+		dryRun.requests = append(dryRun.requests, request)
+		dryRun.mu.Unlock()
+	}
+}
+
+// skippedDryRunRequests returns the GitHub writes skipped during the reconcile, and false when ctx has no dry-run
+// log. An event is emitted on obj for every write.
+func skippedDryRunRequests(ctx context.Context, recorder record.EventRecorder, obj runtime.Object) ([]trainingv1alpha1.DryRunRequest, bool) {
+	dryRun, ok := ctx.Value(dryRunLogKey{}).(*dryRunLog)
+	if !ok {
+		return nil, false
+	}
+	dryRun.mu.Lock()
+	requests := dryRun.requests
+	dryRun.mu.Unlock()
+
+	if recorder != nil {
+		for _, request := range requests {
+			recorder.Eventf(obj, corev1.EventTypeNormal, "DryRun", "Skipped %s %s %s", request.Method, request.URL, request.Payload)
+		}
+	}
+	return requests, true
+}
+
+// reportDryRunRequests emits an event for every GitHub write skipped during the reconcile and records them in
+// status.dryRunRequests when they changed
+func (r *GithubIssueReconciler) reportDryRunRequests(ctx context.Context, ghi *trainingv1alpha1.GithubIssue) {
+	requests, ok := skippedDryRunRequests(ctx, r.Recorder, ghi)
+	if !ok || equality.Semantic.DeepEqual(ghi.Status.DryRunRequests, requests) {
+		return
+	}
+	base := ghi.DeepCopy()
+	ghi.Status.DryRunRequests = requests
+	if err := r.patchGithubIssueStatus(ctx, ghi, base); err != nil && !apiErrors.IsNotFound(err) {
+		log.FromContext(ctx).Error(err, "Failed to record the dry run requests in the GithubIssue status")
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+var _ = Describe("GithubIssue dry run", func() {
+	const resourceName = "test-dry-run"
+
+	ctx := context.Background()
+	typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	var (
+		server *httptest.Server
+		mu     sync.Mutex
+		writes []string
+	)
+
+	BeforeEach(func() {
+		writes = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			if req.Method != http.MethodGet {
+				writes = append(writes, req.Method+" "+req.URL.Path)
+			}
+			_, _ = w.Write([]byte(`{"total_count": 0, "items": []}`))
+		}))
+		Expect(os.Setenv(tokenEnvVar, "token")).To(Succeed())
+
+		resource := &trainingv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec: trainingv1alpha1.GithubIssueSpec{
+				Repo:        server.URL + "/repos/owner/repo",
+				Title:       "title",
+				Description: "body",
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		Expect(os.Unsetenv(tokenEnvVar)).To(Succeed())
+
+		resource := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+		resource.Finalizers = nil
+		Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+	})
+
+	It("should record the issue creation instead of sending it", func() {
+		recorder := record.NewFakeRecorder(10)
+		reconciler := &GithubIssueReconciler{
+			Client:   k8sClient,
+			Scheme:   k8sClient.Scheme(),
+			Recorder: recorder,
+			DryRun:   true,
+		}
+
+		for i := 0; i < 2; i++ {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(writes).To(BeEmpty())
+
+		ghi := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		Expect(ghi.Annotations).NotTo(HaveKey(annotationKey))
+		Expect(ghi.Status.DryRunRequests).To(ConsistOf(trainingv1alpha1.DryRunRequest{
+			Method:  "POST",
+			URL:     server.URL + "/repos/owner/repo/issues",
+			Payload: `{"title":"title","body":"body","state":"open"}`,
+		}))
+		Expect(recorder.Events).To(Receive(ContainSubstring("DryRun Skipped POST")))
+	})
+})
@@ -0,0 +1,192 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	"Shai1-Levi/githubissues-operator.git/internal/githubfake"
+)
+
+var _ = Describe("GithubIssue lifecycle", func() {
+	const (
+		resourceName = "test-lifecycle"
+		fullName     = "owner/lifecycle"
+		issuesPath   = "/repos/owner/lifecycle/issues"
+	)
+
+	ctx := context.Background()
+	typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	var reconciler *GithubIssueReconciler
+
+	reconcileGithubIssue := func() error {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		return err
+	}
+
+	// writes returns the non-GET requests received by the fake GitHub API
+	writes := func() []string {
+		var result []string
+		for _, request := range gitHubServer.Requests() {
+			if request.Method != http.MethodGet {
+				result = append(result, request.Method+" "+request.Path)
+			}
+		}
+		return result
+	}
+
+	getGithubIssue := func() *trainingv1alpha1.GithubIssue {
+		ghi := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		return ghi
+	}
+
+	expectSynced := func(status metav1.ConditionStatus, reason string) {
+		condition := meta.FindStatusCondition(getGithubIssue().Status.Conditions, conditionSynced)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(status))
+		Expect(condition.Reason).To(Equal(reason))
+	}
+
+	BeforeEach(func() {
+		gitHubServer.Reset()
+		gitHubServer.SetToken("token")
+		gitHubServer.SetRateLimit(githubfake.DefaultRateLimit)
+		Expect(os.Setenv(tokenEnvVar, "token")).To(Succeed())
+
+		reconciler = &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), GitHubAPIURL: gitHubServer.URL}
+
+		resource := &trainingv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec: trainingv1alpha1.GithubIssueSpec{
+				Repo:        "https://api.github.com/repos/" + fullName,
+				Title:       "title",
+				Description: "body",
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+
+		By("adding the finalizer")
+		Expect(reconcileGithubIssue()).To(Succeed())
+		Expect(gitHubServer.Requests()).To(BeEmpty())
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv(tokenEnvVar)).To(Succeed())
+
+		resource := &trainingv1alpha1.GithubIssue{}
+		if err := k8sClient.Get(ctx, typeNamespacedName, resource); apiErrors.IsNotFound(err) {
+			return
+		}
+		Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+			resource.Finalizers = nil
+			Expect(client.IgnoreNotFound(k8sClient.Update(ctx, resource))).To(Succeed())
+		}
+	})
+
+	It("should file, correct, update and close the issue", func() {
+		By("filing the issue")
+		Expect(reconcileGithubIssue()).To(Succeed())
+		Expect(writes()).To(Equal([]string{"POST " + issuesPath}))
+		Expect(getGithubIssue().Status.IssueNumber).To(Equal(int64(1)))
+
+		By("syncing the filed issue")
+		Expect(reconcileGithubIssue()).To(Succeed())
+		Expect(writes()).To(HaveLen(1))
+		expectSynced(metav1.ConditionTrue, "Synced")
+		ghi := getGithubIssue()
+		Expect(ghi.Status.ObservedGeneration).To(Equal(ghi.Generation))
+
+		By("correcting the title edited on GitHub")
+		Expect(gitHubServer.UpdateIssue(fullName, 1, func(issue *githubfake.Issue) {
+			issue.Title = "edited on GitHub"
+		})).To(BeTrue())
+		Expect(reconcileGithubIssue()).To(Succeed())
+		Expect(writes()).To(Equal([]string{"POST " + issuesPath, "PATCH " + issuesPath + "/1"}))
+		issue, _ := gitHubServer.Issue(fullName, 1)
+		Expect(issue.Title).To(Equal("title"))
+
+		By("updating the spec")
+		ghi = getGithubIssue()
+		ghi.Spec.Title = "new title"
+		Expect(k8sClient.Update(ctx, ghi)).To(Succeed())
+		Expect(reconcileGithubIssue()).To(Succeed())
+		Expect(writes()).To(HaveLen(3))
+		issue, _ = gitHubServer.Issue(fullName, 1)
+		Expect(issue.Title).To(Equal("new title"))
+
+		By("closing the issue on deletion")
+		Expect(k8sClient.Delete(ctx, getGithubIssue())).To(Succeed())
+		Expect(reconcileGithubIssue()).To(Succeed())
+		Expect(writes()).To(HaveLen(4))
+		issue, _ = gitHubServer.Issue(fullName, 1)
+		Expect(issue.State).To(Equal("closed"))
+		Expect(apiErrors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, &trainingv1alpha1.GithubIssue{}))).To(BeTrue())
+	})
+
+	DescribeTable("reporting failed GitHub requests",
+		func(setup func(), reason string) {
+			setup()
+			Expect(reconcileGithubIssue()).NotTo(Succeed())
+			expectSynced(metav1.ConditionFalse, reason)
+			Expect(gitHubServer.Issues(fullName)).To(BeEmpty())
+			Expect(getGithubIssue().Status.IssueNumber).To(BeZero())
+		},
+		Entry("bad credentials", func() {
+			gitHubServer.SetToken("other")
+		}, "Unauthorized"),
+		Entry("missing repository", func() {
+			gitHubServer.InjectFault(githubfake.Fault{Path: "/repos/owner/lifecycle/*", Status: http.StatusNotFound})
+		}, "NotFound"),
+		Entry("rejected issue", func() {
+			gitHubServer.InjectFault(githubfake.Fault{Method: http.MethodPost, Path: issuesPath, Status: http.StatusUnprocessableEntity})
+		}, "ValidationFailed"),
+		Entry("exhausted rate limit", func() {
+			// The search for existing issues uses up the limit before the issue is filed
+			gitHubServer.SetRateLimit(1)
+		}, "RateLimited"),
+	)
+
+	It("should file the issue once GitHub recovers from a server error", func() {
+		gitHubServer.InjectFault(githubfake.Fault{Method: http.MethodPost, Path: issuesPath, Status: http.StatusBadGateway, Times: 1})
+
+		By("failing to file the issue")
+		Expect(reconcileGithubIssue()).NotTo(Succeed())
+		expectSynced(metav1.ConditionFalse, "GitHubUnavailable")
+
+		By("retrying")
+		Expect(reconcileGithubIssue()).To(Succeed())
+		Expect(reconcileGithubIssue()).To(Succeed())
+		expectSynced(metav1.ConditionTrue, "Synced")
+		Expect(writes()).To(Equal([]string{"POST " + issuesPath, "POST " + issuesPath}))
+		Expect(gitHubServer.Issues(fullName)).To(HaveLen(1))
+	})
+})
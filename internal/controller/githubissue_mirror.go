@@ -0,0 +1,206 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+const (
+	// mirroredCommentsKey is the ConfigMap data key holding the mirrored comments as a JSON list
+	mirroredCommentsKey = "comments.json"
+	// commentsSyncTimeKey is the ConfigMap data key holding the last comments sync time
+	commentsSyncTimeKey = "syncTime"
+	// defaultMirroredCommentsLimit is used when spec.commentMirror.limit is not set
+	defaultMirroredCommentsLimit = 10
+	// commentsPerPage is the page size used when listing issue comments
+	commentsPerPage = 100
+)
+
+// mirrorGithubIssueComments reads the comments left on the GitHub issue since the last sync and records the
+// latest ones in status.mirroredComments or in the ConfigMap referenced by spec.commentMirror.
+// Comments created by the operator from spec.comments are not mirrored.
+func (r *GithubIssueReconciler) mirrorGithubIssueComments(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, repoURL string, issueNumber string, accessToken string) error {
+	log := log.FromContext(ctx)
+
+	mirror := ghi.Spec.CommentMirror
+	if mirror == nil {
+		return nil
+	}
+	limit := mirror.Limit
+	if limit <= 0 {
+		limit = defaultMirroredCommentsLimit
+	}
+
+	// Remember the time before the request so comments posted while fetching are read again next time
+	syncTime := metav1.Now()
+
+	fetched, err := r.fetchGithubIssueComments(repoURL, issueNumber, ghi.Status.CommentsSyncTime, accessToken)
+	if err != nil {
+		return err
+	}
+
+	// Nothing new since the last sync, keep the recorded comments and sync time as they are
+	if len(fetched) == 0 && ghi.Status.CommentsSyncTime != nil {
+		return nil
+	}
+
+	owned := make(map[int64]bool, len(ghi.Status.Comments))
+	for _, c := range ghi.Status.Comments {
+		owned[c.ID] = true
+	}
+
+	var current []trainingv1alpha1.MirroredComment
+	var cm *corev1.ConfigMap
+	if mirror.ConfigMapName != "" {
+		cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: mirror.ConfigMapName, Namespace: ghi.Namespace}}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(cm), cm); err != nil && !apiErrors.IsNotFound(err) {
+			return err
+		}
+		if data, ok := cm.Data[mirroredCommentsKey]; ok {
+			if err := json.Unmarshal([]byte(data), &current); err != nil {
+				log.Info("Ignoring unreadable mirrored comments in ConfigMap", "configmap", mirror.ConfigMapName)
+				current = nil
+			}
+		}
+	} else {
+		current = ghi.Status.MirroredComments
+	}
+
+	mirrored := mergeMirroredComments(current, fetched, owned, limit)
+
+	if cm != nil {
+		data, err := json.Marshal(mirrored)
+		if err != nil {
+			return fmt.Errorf("error marshaling JSON: %w", err)
+		}
+		cm = &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: mirror.ConfigMapName, Namespace: ghi.Namespace}}
+		if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, cm, func() error {
+			if cm.Data == nil {
+				cm.Data = make(map[string]string)
+			}
+			cm.Data[mirroredCommentsKey] = string(data)
+			cm.Data[commentsSyncTimeKey] = syncTime.UTC().Format(time.RFC3339)
+			// Only a ConfigMap created here is owned by the GithubIssue, an existing ConfigMap of the user must
+			// not be garbage collected with it
+			if cm.ResourceVersion == "" {
+				return controllerutil.SetControllerReference(ghi, cm, r.Scheme)
+			}
+			return nil
+		}); err != nil {
+			log.Error(err, "Failed to write mirrored comments to ConfigMap", "configmap", mirror.ConfigMapName)
+			return err
+		}
+		mirrored = nil
+	}
+
+	base := ghi.DeepCopy()
+	ghi.Status.MirroredComments = mirrored
+	ghi.Status.CommentsSyncTime = &syncTime
+	if err := r.patchGithubIssueStatus(ctx, ghi, base); err != nil {
+		log.Error(err, "Failed to update GithubIssue mirrored comments status")
+		return err
+	}
+
+	return nil
+}
+
+// mergeMirroredComments adds or replaces the fetched comments in current, drops the comments owned by the
+// operator and keeps the latest limit comments ordered from oldest to newest
+func mergeMirroredComments(current []trainingv1alpha1.MirroredComment, fetched []GitHubComment, owned map[int64]bool, limit int) []trainingv1alpha1.MirroredComment {
+	byID := make(map[int64]trainingv1alpha1.MirroredComment, len(current)+len(fetched))
+	for _, c := range current {
+		byID[c.ID] = c
+	}
+	for _, c := range fetched {
+		mc := trainingv1alpha1.MirroredComment{
+			ID:        c.ID,
+			Author:    c.User.Login,
+			CreatedAt: metav1.NewTime(c.CreatedAt),
+			Body:      c.Body,
+		}
+		if !c.UpdatedAt.IsZero() && !c.UpdatedAt.Equal(c.CreatedAt) {
+			updatedAt := metav1.NewTime(c.UpdatedAt)
+			mc.UpdatedAt = &updatedAt
+		}
+		byID[c.ID] = mc
+	}
+
+	merged := make([]trainingv1alpha1.MirroredComment, 0, len(byID))
+	for id, c := range byID {
+		if owned[id] {
+			continue
+		}
+		merged = append(merged, c)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		if merged[i].CreatedAt.Equal(&merged[j].CreatedAt) {
+			return merged[i].ID < merged[j].ID
+		}
+		return merged[i].CreatedAt.Before(&merged[j].CreatedAt)
+	})
+
+	if len(merged) > limit {
+		merged = merged[len(merged)-limit:]
+	}
+	return merged
+}
+
+// fetchGithubIssueComments lists the comments of the issue page by page, only comments updated after since are
+// returned when since is set
+func (r *GithubIssueReconciler) fetchGithubIssueComments(repo string, issueNumber string, since *metav1.Time, accessToken string) ([]GitHubComment, error) {
+	baseURL := repo + "/issues/" + issueNumber + "/comments?per_page=" + strconv.Itoa(commentsPerPage)
+	if since != nil {
+		baseURL += "&since=" + since.UTC().Format(time.RFC3339)
+	}
+
+	var comments []GitHubComment
+	for page := 1; ; page++ {
+		status, respBody, err := r.sendGitHubRequest("GET", baseURL+"&page="+strconv.Itoa(page), nil, accessToken)
+		if err != nil {
+			return nil, err
+		}
+		if status != http.StatusOK {
+			return nil, &gitHubStatusError{StatusCode: status}
+		}
+
+		var pageComments []GitHubComment
+		if err := json.Unmarshal(respBody, &pageComments); err != nil {
+			return nil, fmt.Errorf("error unmarshaling JSON: %w", err)
+		}
+		comments = append(comments, pageComments...)
+
+		if len(pageComments) < commentsPerPage {
+			return comments, nil
+		}
+	}
+}
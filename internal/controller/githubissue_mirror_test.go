@@ -0,0 +1,152 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	"Shai1-Levi/githubissues-operator.git/internal/githubfake"
+)
+
+var _ = Describe("Mirrored comments", func() {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newComment := func(id int64, minutes int, body string) GitHubComment {
+		c := GitHubComment{ID: id, Body: body, CreatedAt: base.Add(time.Duration(minutes) * time.Minute)}
+		c.UpdatedAt = c.CreatedAt
+		c.User.Login = "octocat"
+		return c
+	}
+
+	It("should merge fetched comments and keep only the latest ones", func() {
+		current := []trainingv1alpha1.MirroredComment{
+			{ID: 1, Author: "octocat", CreatedAt: metav1.NewTime(base), Body: "one"},
+			{ID: 2, Author: "octocat", CreatedAt: metav1.NewTime(base.Add(time.Minute)), Body: "two"},
+		}
+		edited := newComment(2, 1, "two edited")
+		edited.UpdatedAt = base.Add(5 * time.Minute)
+		fetched := []GitHubComment{edited, newComment(3, 2, "three"), newComment(4, 3, "operator")}
+
+		merged := mergeMirroredComments(current, fetched, map[int64]bool{4: true}, 2)
+
+		Expect(merged).To(HaveLen(2))
+		Expect(merged[0].ID).To(Equal(int64(2)))
+		Expect(merged[0].Body).To(Equal("two edited"))
+		Expect(merged[0].UpdatedAt).NotTo(BeNil())
+		Expect(merged[1].ID).To(Equal(int64(3)))
+		Expect(merged[1].Author).To(Equal("octocat"))
+		Expect(merged[1].UpdatedAt).To(BeNil())
+	})
+
+	Context("When reading the comments of a GitHub issue", func() {
+		const (
+			fullName      = "owner/mirror"
+			configMapName = "test-mirrored-comments"
+		)
+
+		ctx := context.Background()
+
+		var (
+			reconciler *GithubIssueReconciler
+			ghi        *trainingv1alpha1.GithubIssue
+		)
+
+		BeforeEach(func() {
+			gitHubServer.Reset()
+			gitHubServer.SetToken("token")
+			gitHubServer.SetRateLimit(githubfake.DefaultRateLimit)
+			reconciler = &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+			gitHubServer.CreateIssue(fullName, githubfake.Issue{Title: "mirror"})
+			for i := 0; i < commentsPerPage+20; i++ {
+				gitHubServer.AddComment(fullName, 1, "octocat", fmt.Sprintf("comment %d", i+1))
+			}
+
+			ghi = &trainingv1alpha1.GithubIssue{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-mirror", Namespace: "default"},
+				Spec: trainingv1alpha1.GithubIssueSpec{
+					Repo:          "https://api.github.com/repos/" + fullName,
+					Title:         "mirror",
+					CommentMirror: &trainingv1alpha1.CommentMirrorSpec{Limit: 5, ConfigMapName: configMapName},
+				},
+			}
+			Expect(k8sClient.Create(ctx, ghi)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, ghi)).To(Succeed())
+			cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: configMapName, Namespace: "default"}}
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, cm))).To(Succeed())
+		})
+
+		It("should read every page of the comments updated since the last sync", func() {
+			since := len(gitHubServer.Requests())
+			comments, err := reconciler.fetchGithubIssueComments(gitHubServer.RepositoryURL(fullName), "1", nil, "token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(comments).To(HaveLen(commentsPerPage + 20))
+			Expect(comments[commentsPerPage].Body).To(Equal(fmt.Sprintf("comment %d", commentsPerPage+1)))
+			Expect(gitHubServer.Requests()[since:]).To(HaveLen(2))
+
+			By("passing the time of the last sync as since")
+			lastSync := metav1.NewTime(time.Now().Add(time.Hour))
+			since = len(gitHubServer.Requests())
+			comments, err = reconciler.fetchGithubIssueComments(gitHubServer.RepositoryURL(fullName), "1", &lastSync, "token")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(comments).To(BeEmpty())
+			requests := gitHubServer.Requests()[since:]
+			Expect(requests).To(HaveLen(1))
+			Expect(requests[0].Query).To(ContainSubstring("since=" + lastSync.UTC().Format(time.RFC3339)))
+		})
+
+		It("should write the latest comments to a ConfigMap it owns", func() {
+			Expect(reconciler.mirrorGithubIssueComments(ctx, ghi, gitHubServer.RepositoryURL(fullName), "1", "token")).To(Succeed())
+
+			cm := &corev1.ConfigMap{}
+			Expect(k8sClient.Get(ctx, client.ObjectKey{Name: configMapName, Namespace: "default"}, cm)).To(Succeed())
+			Expect(cm.Data).To(HaveKey(commentsSyncTimeKey))
+			Expect(cm.Data[mirroredCommentsKey]).To(ContainSubstring(fmt.Sprintf("comment %d", commentsPerPage+20)))
+			Expect(cm.Data[mirroredCommentsKey]).NotTo(ContainSubstring(fmt.Sprintf("comment %d\"", commentsPerPage+15)))
+			Expect(metav1.IsControlledBy(cm, ghi)).To(BeTrue())
+			Expect(ghi.Status.MirroredComments).To(BeEmpty())
+			Expect(ghi.Status.CommentsSyncTime).NotTo(BeNil())
+		})
+
+		It("should not take over a ConfigMap of the user", func() {
+			cm := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: configMapName, Namespace: "default"},
+				Data:       map[string]string{"owner": "user"},
+			}
+			Expect(k8sClient.Create(ctx, cm)).To(Succeed())
+
+			Expect(reconciler.mirrorGithubIssueComments(ctx, ghi, gitHubServer.RepositoryURL(fullName), "1", "token")).To(Succeed())
+
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(cm), cm)).To(Succeed())
+			Expect(cm.Data).To(HaveKeyWithValue("owner", "user"))
+			Expect(cm.Data).To(HaveKey(mirroredCommentsKey))
+			Expect(cm.OwnerReferences).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,313 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	"Shai1-Levi/githubissues-operator.git/internal/policy"
+)
+
+const (
+	linkSubIssue = "SubIssue"
+	linkTaskList = "TaskList"
+
+	// taskListMarker starts the task list the operator keeps at the end of the body of a parent issue
+	taskListMarker = "<!-- github-issue.kubebuilder.io/task-list -->"
+)
+
+// githubIssueParentQuery reads the node ID of the parent issue and the current parent of the sub-issue
+const githubIssueParentQuery = `query GithubIssueParent($owner: String!, $name: String!, $number: Int!, $issue: ID!) {
+  repository(owner: $owner, name: $name) {
+    issue(number: $number) { id }
+  }
+  node(id: $issue) {
+    ... on Issue { parent { id } }
+  }
+}`
+
+const addSubIssueMutation = `mutation AddSubIssue($parent: ID!, $issue: ID!) {
+  addSubIssue(input: {issueId: $parent, subIssueId: $issue, replaceParent: true}) { issue { id } }
+}`
+
+type gitHubIssueParentResponse struct {
+	Repository *struct {
+		Issue *struct {
+			ID string `json:"id"`
+		} `json:"issue"`
+	} `json:"repository"`
+	Node *struct {
+		Parent *struct {
+			ID string `json:"id"`
+		} `json:"parent"`
+	} `json:"node"`
+}
+
+// parentLink returns how the GithubIssue is attached to its parent issue
+func parentLink(ghi *trainingv1alpha1.GithubIssue) string {
+	if ghi.Spec.Parent.Link == "" {
+		return linkSubIssue
+	}
+	return ghi.Spec.Parent.Link
+}
+
+// childGithubIssues returns the GithubIssues referencing ghi in spec.parent that are not being deleted, oldest first
+func (r *GithubIssueReconciler) childGithubIssues(ctx context.Context, ghi *trainingv1alpha1.GithubIssue) ([]trainingv1alpha1.GithubIssue, error) {
+	issues := &trainingv1alpha1.GithubIssueList{}
+	if err := r.List(ctx, issues, client.InNamespace(ghi.Namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list the child GithubIssues: %w", err)
+	}
+
+	var children []trainingv1alpha1.GithubIssue
+	for _, issue := range issues.Items {
+		if issue.Spec.Parent == nil || issue.Spec.Parent.Name != ghi.Name || !issue.DeletionTimestamp.IsZero() {
+			continue
+		}
+		children = append(children, issue)
+	}
+	sort.Slice(children, func(i, j int) bool { return olderGithubIssue(&children[i], &children[j]) })
+	return children, nil
+}
+
+// observeGithubIssueChildren records the progress of the children of ghi in status.children and returns them
+func (r *GithubIssueReconciler) observeGithubIssueChildren(ctx context.Context, ghi *trainingv1alpha1.GithubIssue) ([]trainingv1alpha1.GithubIssue, error) {
+	children, err := r.childGithubIssues(ctx, ghi)
+	if err != nil {
+		return nil, err
+	}
+
+	var progress *trainingv1alpha1.ChildIssuesStatus
+	if len(children) > 0 {
+		progress = &trainingv1alpha1.ChildIssuesStatus{Total: int32(len(children))}
+		for _, child := range children {
+			if child.Status.State == "closed" {
+				progress.Closed++
+			}
+		}
+		progress.Progress = fmt.Sprintf("%d/%d", progress.Closed, progress.Total)
+	}
+
+	if !reflect.DeepEqual(ghi.Status.Children, progress) {
+		base := ghi.DeepCopy()
+		ghi.Status.Children = progress
+		if err := r.patchGithubIssueStatus(ctx, ghi, base); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to update GithubIssue children")
+			return nil, err
+		}
+	}
+	return children, nil
+}
+
+// withTaskList appends the task list of the children linked as TaskList to the issue body. An entry is
+// checked once the issue of the child is closed, children without an issue are left out until it is filed.
+// Each repository is resolved once, children filed in the repository of ghi reuse repository.
+func (r *GithubIssueReconciler) withTaskList(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, repository githubRepository,
+	description string, children []trainingv1alpha1.GithubIssue) (string, error) {
+	repositories := map[string]githubRepository{githubIssueRepositoryKey(ghi): repository}
+	var entries []string
+	for i := range children {
+		child := &children[i]
+		issueNumber := githubIssueNumber(child)
+		if parentLink(child) != linkTaskList || issueNumber == "" {
+			continue
+		}
+		key := githubIssueRepositoryKey(child)
+		childRepository, ok := repositories[key]
+		if !ok {
+			var err error
+			if childRepository, err = r.resolveGithubRepository(ctx, child); err != nil {
+				return "", err
+			}
+			repositories[key] = childRepository
+		}
+		if issueTrackerFor(childRepository) != nil {
+			continue
+		}
+		fullName, err := policy.RepositoryFullName(childRepository.URL)
+		if err != nil {
+			return "", err
+		}
+		check := " "
+		if child.Status.State == "closed" {
+			check = "x"
+		}
+		entries = append(entries, fmt.Sprintf("- [%s] %s#%s", check, fullName, issueNumber))
+	}
+	if len(entries) == 0 {
+		return description, nil
+	}
+	return fmt.Sprintf("%s\n\n%s\n### Tasks\n%s", description, taskListMarker, strings.Join(entries, "\n")), nil
+}
+
+// githubIssueRepositoryKey returns the fields resolveGithubRepository resolves the repository of ghi from, GithubIssues
+// with the same key are filed in the same repository
+func githubIssueRepositoryKey(ghi *trainingv1alpha1.GithubIssue) string {
+	if ref := ghi.Spec.RepositoryRef; ref != nil {
+		return githubIssueProvider(ghi) + " " + ref.Kind + "/" + ref.Name
+	}
+	return githubIssueProvider(ghi) + " " + ghi.Spec.Repo
+}
+
+// linkGithubIssueParent adds the GitHub issue as a sub-issue of the issue of the spec.parent GithubIssue.
+// It waits for the parent issue to be filed, and moves the issue over when it has another parent.
+func (r *GithubIssueReconciler) linkGithubIssueParent(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, repoURL string, issueNodeID string, accessToken string) error {
+	if ghi.Spec.Parent == nil || parentLink(ghi) != linkSubIssue {
+		return nil
+	}
+	log := log.FromContext(ctx)
+
+	parent := &trainingv1alpha1.GithubIssue{}
+	if err := r.Get(ctx, types.NamespacedName{Name: ghi.Spec.Parent.Name, Namespace: ghi.Namespace}, parent); err != nil {
+		if apiErrors.IsNotFound(err) {
+			return fmt.Errorf("parent GithubIssue %q was not found", ghi.Spec.Parent.Name)
+		}
+		return err
+	}
+	if parent.Status.IssueNumber == 0 {
+		log.Info("Waiting for the parent GitHub issue to be filed", "parent", parent.Name)
+		return nil
+	}
+	parentRepository, err := r.resolveGithubRepository(ctx, parent)
+	if err != nil {
+		return err
+	}
+	if issueTrackerFor(parentRepository) != nil {
+		return fmt.Errorf("parent GithubIssue %q is not filed in GitHub, sub-issues are only supported for GitHub", parent.Name)
+	}
+	if issueNodeID == "" {
+		return fmt.Errorf("GitHub issue %d has no node ID", ghi.Status.IssueNumber)
+	}
+	fullName, err := policy.RepositoryFullName(parentRepository.URL)
+	if err != nil {
+		return err
+	}
+	owner, name, _ := strings.Cut(fullName, "/")
+
+	url := gitHubGraphQLURL(repoURL)
+	var response gitHubIssueParentResponse
+	request := graphQLRequest{
+		Query:         githubIssueParentQuery,
+		OperationName: "GithubIssueParent",
+		Variables: map[string]interface{}{
+			"owner": owner, "name": name, "number": parent.Status.IssueNumber, "issue": issueNodeID,
+		},
+	}
+	if err := doGraphQLRequest(url, request, accessToken, &response); err != nil {
+		return err
+	}
+	if response.Repository == nil || response.Repository.Issue == nil {
+		return fmt.Errorf("parent GitHub issue %s#%d was not found", fullName, parent.Status.IssueNumber)
+	}
+	parentID := response.Repository.Issue.ID
+	if response.Node != nil && response.Node.Parent != nil && response.Node.Parent.ID == parentID {
+		return nil
+	}
+
+	log.Info("Adding GitHub issue as a sub-issue", "parent", fmt.Sprintf("%s#%d", fullName, parent.Status.IssueNumber))
+	request = graphQLRequest{
+		Query:         addSubIssueMutation,
+		OperationName: "AddSubIssue",
+		Variables:     map[string]interface{}{"parent": parentID, "issue": issueNodeID},
+	}
+	if r.dryRunRequest(ctx, http.MethodPost, url, request.Variables) {
+		return nil
+	}
+	return doGraphQLRequest(url, request, accessToken, nil)
+}
+
+// recordGithubIssueState records the state of the GitHub issue in status.state when it changed
+func (r *GithubIssueReconciler) recordGithubIssueState(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, state string) error {
+	if ghi.Status.State == state {
+		return nil
+	}
+	base := ghi.DeepCopy()
+	ghi.Status.State = state
+	if err := r.patchGithubIssueStatus(ctx, ghi, base); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to record the state of the GitHub issue")
+		return err
+	}
+	return nil
+}
+
+// githubIssueFamily maps a GithubIssue to its parent, whose progress and task list follow the children, and to
+// its children, which wait for the parent issue to be filed before they become its sub-issues
+func (r *GithubIssueReconciler) githubIssueFamily(ctx context.Context, obj client.Object) []reconcile.Request {
+	ghi, ok := obj.(*trainingv1alpha1.GithubIssue)
+	if !ok {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	if ghi.Spec.Parent != nil {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: ghi.Spec.Parent.Name, Namespace: ghi.Namespace},
+		})
+	}
+	children, err := r.childGithubIssues(ctx, ghi)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list the child GithubIssues")
+		return requests
+	}
+	for _, child := range children {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&child)})
+	}
+	return requests
+}
+
+// githubIssueFamilyHandler enqueues the family of a changed GithubIssue. The map function is applied to the old and
+// the new object of an update, so a changed spec.parent requeues the previous parent, which drops the child from its
+// progress and task list, as well as the new one.
+func (r *GithubIssueReconciler) githubIssueFamilyHandler() handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(r.githubIssueFamily)
+}
+
+// githubIssueFamilyPredicate passes the GithubIssue events the parent or the children of the GithubIssue
+// have to follow: creations, deletions, and changes of the issue number, the issue state or spec.parent
+func githubIssueFamilyPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldIssue, ok := e.ObjectOld.(*trainingv1alpha1.GithubIssue)
+			if !ok {
+				return false
+			}
+			newIssue, ok := e.ObjectNew.(*trainingv1alpha1.GithubIssue)
+			if !ok {
+				return false
+			}
+			return oldIssue.Status.IssueNumber != newIssue.Status.IssueNumber ||
+				oldIssue.Status.State != newIssue.Status.State ||
+				!reflect.DeepEqual(oldIssue.Spec.Parent, newIssue.Spec.Parent) ||
+				(oldIssue.DeletionTimestamp.IsZero() && !newIssue.DeletionTimestamp.IsZero())
+		},
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
@@ -0,0 +1,254 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	"Shai1-Levi/githubissues-operator.git/internal/githubfake"
+)
+
+var _ = Describe("GithubIssue parent", func() {
+	const fullName = "owner/family"
+
+	ctx := context.Background()
+	parentName := types.NamespacedName{Name: "test-parent", Namespace: "default"}
+	subIssueName := types.NamespacedName{Name: "test-child-sub-issue", Namespace: "default"}
+	taskName := types.NamespacedName{Name: "test-child-task", Namespace: "default"}
+
+	var reconciler *GithubIssueReconciler
+	reconcileGithubIssue := func(name types.NamespacedName, times int) {
+		for i := 0; i < times; i++ {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: name})
+			Expect(err).NotTo(HaveOccurred())
+		}
+	}
+	children := func() *trainingv1alpha1.ChildIssuesStatus {
+		parent := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, parentName, parent)).To(Succeed())
+		return parent.Status.Children
+	}
+	parentBody := func() string {
+		issue, found := gitHubServer.Issue(fullName, 1)
+		Expect(found).To(BeTrue())
+		return issue.Body
+	}
+
+	BeforeEach(func() {
+		gitHubServer.Reset()
+		gitHubServer.SetToken("token")
+		gitHubServer.SetRateLimit(githubfake.DefaultRateLimit)
+		Expect(os.Setenv(tokenEnvVar, "token")).To(Succeed())
+		reconciler = &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), GitHubAPIURL: gitHubServer.URL}
+
+		for _, resource := range []*trainingv1alpha1.GithubIssue{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: parentName.Name, Namespace: "default"},
+				Spec:       trainingv1alpha1.GithubIssueSpec{Title: "incident", Description: "body"},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: subIssueName.Name, Namespace: "default"},
+				Spec: trainingv1alpha1.GithubIssueSpec{Title: "sub-issue", Description: "body",
+					Parent: &trainingv1alpha1.ParentReference{Name: parentName.Name, Link: "SubIssue"}},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: taskName.Name, Namespace: "default"},
+				Spec: trainingv1alpha1.GithubIssueSpec{Title: "task", Description: "body",
+					Parent: &trainingv1alpha1.ParentReference{Name: parentName.Name, Link: "TaskList"}},
+			},
+		} {
+			resource.Spec.Repo = "https://api.github.com/repos/" + fullName
+			Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv(tokenEnvVar)).To(Succeed())
+
+		for _, name := range []types.NamespacedName{parentName, subIssueName, taskName} {
+			resource := &trainingv1alpha1.GithubIssue{}
+			Expect(k8sClient.Get(ctx, name, resource)).To(Succeed())
+			resource.Finalizers = nil
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).To(Succeed())
+		}
+	})
+
+	It("should link the children to the parent issue and report their progress", func() {
+		By("filing the parent and the children")
+		reconcileGithubIssue(parentName, 3)
+		reconcileGithubIssue(subIssueName, 3)
+		reconcileGithubIssue(taskName, 3)
+
+		By("adding the SubIssue child as a sub-issue")
+		subIssue, found := gitHubServer.Issue(fullName, 2)
+		Expect(found).To(BeTrue())
+		Expect(subIssue.ParentID).To(Equal(githubfake.IssueNodeID(fullName, 1)))
+		taskIssue, found := gitHubServer.Issue(fullName, 3)
+		Expect(found).To(BeTrue())
+		Expect(taskIssue.ParentID).To(BeEmpty())
+
+		By("listing the TaskList child in the parent body")
+		reconcileGithubIssue(parentName, 1)
+		Expect(parentBody()).To(Equal("body\n\n" + taskListMarker + "\n### Tasks\n- [ ] owner/family#3"))
+		Expect(children()).To(Equal(&trainingv1alpha1.ChildIssuesStatus{Total: 2, Closed: 0, Progress: "0/2"}))
+
+		By("checking off the task once its issue is closed")
+		task := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, taskName, task)).To(Succeed())
+		task.Spec.State = "Closed"
+		Expect(k8sClient.Update(ctx, task)).To(Succeed())
+		reconcileGithubIssue(taskName, 1)
+		Expect(k8sClient.Get(ctx, taskName, task)).To(Succeed())
+		Expect(task.Status.State).To(Equal("closed"))
+
+		reconcileGithubIssue(parentName, 1)
+		Expect(parentBody()).To(HaveSuffix("- [x] owner/family#3"))
+		Expect(children()).To(Equal(&trainingv1alpha1.ChildIssuesStatus{Total: 2, Closed: 1, Progress: "1/2"}))
+
+		By("counting a sub-issue closed on GitHub")
+		Expect(gitHubServer.UpdateIssue(fullName, 2, func(issue *githubfake.Issue) {
+			issue.State = "closed"
+		})).To(BeTrue())
+		reconcileGithubIssue(subIssueName, 1)
+		reconcileGithubIssue(parentName, 1)
+		Expect(children()).To(Equal(&trainingv1alpha1.ChildIssuesStatus{Total: 2, Closed: 2, Progress: "2/2"}))
+	})
+
+	It("should resolve the repository of the TaskList children once", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-task-credentials", Namespace: "default"},
+			Data:       map[string][]byte{"token": []byte("token")},
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, secret)
+		repository := &trainingv1alpha1.GithubRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-task-repository", Namespace: "default"},
+			Spec: trainingv1alpha1.GithubRepositorySpec{Owner: "owner", Name: "tasks",
+				CredentialsRef: &trainingv1alpha1.SecretKeyReference{Name: secret.Name, Key: "token"}},
+		}
+		Expect(k8sClient.Create(ctx, repository)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, repository)
+
+		parent := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, parentName, parent)).To(Succeed())
+		var tasks []trainingv1alpha1.GithubIssue
+		for i := 1; i <= 3; i++ {
+			task := trainingv1alpha1.GithubIssue{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("task-%d", i), Namespace: "default"},
+				Spec: trainingv1alpha1.GithubIssueSpec{
+					RepositoryRef: &trainingv1alpha1.RepositoryReference{Name: repository.Name},
+					Parent:        &trainingv1alpha1.ParentReference{Name: parentName.Name, Link: linkTaskList},
+				},
+				Status: trainingv1alpha1.GithubIssueStatus{IssueNumber: int64(i)},
+			}
+			tasks = append(tasks, task)
+		}
+
+		counter := &secretReadCounter{Client: k8sClient}
+		reconciler.Client = counter
+		parentRepository, err := reconciler.resolveGithubRepository(ctx, parent)
+		Expect(err).NotTo(HaveOccurred())
+		body, err := reconciler.withTaskList(ctx, parent, parentRepository, "body", tasks)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(body).To(HaveSuffix("- [ ] owner/tasks#1\n- [ ] owner/tasks#2\n- [ ] owner/tasks#3"))
+		Expect(counter.reads).To(Equal(1))
+	})
+
+	It("should wait for the parent issue and report a missing parent", func() {
+		By("waiting for the parent issue to be filed")
+		reconcileGithubIssue(subIssueName, 3)
+		subIssue, found := gitHubServer.Issue(fullName, 1)
+		Expect(found).To(BeTrue())
+		Expect(subIssue.ParentID).To(BeEmpty())
+
+		By("reporting a parent GithubIssue that does not exist")
+		child := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, subIssueName, child)).To(Succeed())
+		child.Spec.Parent.Name = "missing"
+		Expect(k8sClient.Update(ctx, child)).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: subIssueName})
+		Expect(err).To(MatchError(ContainSubstring(`parent GithubIssue "missing" was not found`)))
+		Expect(k8sClient.Get(ctx, subIssueName, child)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(child.Status.Conditions, conditionSynced)).To(BeTrue())
+	})
+
+	It("should reconcile the parent and the children of a changed GithubIssue", func() {
+		parent := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, parentName, parent)).To(Succeed())
+		child := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, taskName, child)).To(Succeed())
+
+		Expect(reconciler.githubIssueFamily(ctx, child)).To(ConsistOf(reconcile.Request{NamespacedName: parentName}))
+		Expect(reconciler.githubIssueFamily(ctx, parent)).To(ConsistOf(
+			reconcile.Request{NamespacedName: subIssueName},
+			reconcile.Request{NamespacedName: taskName},
+		))
+
+		By("requeueing the previous and the new parent when spec.parent changes")
+		moved := child.DeepCopy()
+		moved.Spec.Parent = &trainingv1alpha1.ParentReference{Name: subIssueName.Name, Link: linkTaskList}
+		queue := workqueue.NewTypedRateLimitingQueue(workqueue.DefaultTypedControllerRateLimiter[reconcile.Request]())
+		defer queue.ShutDown()
+		reconciler.githubIssueFamilyHandler().Update(ctx, event.UpdateEvent{ObjectOld: child, ObjectNew: moved}, queue)
+		var requeued []reconcile.Request
+		for queue.Len() > 0 {
+			request, _ := queue.Get()
+			requeued = append(requeued, request)
+			queue.Done(request)
+		}
+		Expect(requeued).To(ConsistOf(
+			reconcile.Request{NamespacedName: parentName},
+			reconcile.Request{NamespacedName: subIssueName},
+		))
+
+		closed := child.DeepCopy()
+		closed.Status.State = "closed"
+		relabeled := child.DeepCopy()
+		relabeled.Labels = map[string]string{"team": "sre"}
+		familyPredicate := githubIssueFamilyPredicate()
+		Expect(familyPredicate.Update(event.UpdateEvent{ObjectOld: child, ObjectNew: closed})).To(BeTrue())
+		Expect(familyPredicate.Update(event.UpdateEvent{ObjectOld: child, ObjectNew: relabeled})).To(BeFalse())
+	})
+})
+
+// secretReadCounter counts the Secrets read through the client
+type secretReadCounter struct {
+	client.Client
+	reads int
+}
+
+func (c *secretReadCounter) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	if _, ok := obj.(*corev1.Secret); ok {
+		c.reads++
+	}
+	return c.Client.Get(ctx, key, obj, opts...)
+}
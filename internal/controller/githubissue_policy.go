@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	"Shai1-Levi/githubissues-operator.git/internal/policy"
+)
+
+// conditionForbidden reports whether a GithubIssuePolicy forbids the repository of a GithubIssue or GithubPullRequest
+const conditionForbidden = "Forbidden"
+
+// checkGithubIssuePolicy evaluates the GithubIssuePolicies selecting the GithubIssue namespace against its
+// repository, records the result in the Forbidden condition and returns whether the repository is forbidden.
+// The admission webhook rejects such GithubIssues already, this covers policies created or changed afterwards
+// and clusters running without the webhook.
+func (r *GithubIssueReconciler) checkGithubIssuePolicy(ctx context.Context, ghi *trainingv1alpha1.GithubIssue) (bool, error) {
+	// The name is derived from the spec as in the admission webhook, the resolved URL may point at --github-api-url
+	name, err := policy.GithubIssueRepositoryName(ctx, r.Client, ghi)
+	if err != nil {
+		return false, err
+	}
+	condition, allowed, err := repositoryPolicyCondition(ctx, r.Client, ghi.Namespace, name, ghi.Generation)
+	if err != nil {
+		return false, err
+	}
+
+	// Only record the condition once a policy forbade the GithubIssue, so unrestricted namespaces are not touched
+	if allowed && meta.FindStatusCondition(ghi.Status.Conditions, conditionForbidden) == nil {
+		return false, nil
+	}
+	base := ghi.DeepCopy()
+	if meta.SetStatusCondition(&ghi.Status.Conditions, condition) {
+		if err := r.patchGithubIssueStatus(ctx, ghi, base); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to update GithubIssue status")
+			return false, err
+		}
+	}
+	return !allowed, nil
+}
+
+// repositoryPolicyCondition returns the Forbidden condition of the repository named name in namespace, see
+// policy.RepositoryName, and whether the GithubIssuePolicies allow it
+func repositoryPolicyCondition(ctx context.Context, c client.Reader, namespace string, name string, generation int64) (metav1.Condition, bool, error) {
+	condition := metav1.Condition{
+		Type:               conditionForbidden,
+		Status:             metav1.ConditionFalse,
+		Reason:             "RepositoryAllowed",
+		Message:            "Repository is allowed by the GithubIssuePolicies",
+		ObservedGeneration: generation,
+	}
+
+	allowed, message, err := policy.CheckRepository(ctx, c, namespace, name)
+	if err != nil {
+		return condition, false, err
+	}
+	if !allowed {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "RepositoryNotAllowed"
+		condition.Message = message
+	}
+	return condition, allowed, nil
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	"Shai1-Levi/githubissues-operator.git/internal/githubfake"
+)
+
+var _ = Describe("GithubIssue Controller with a GithubIssuePolicy", func() {
+	Context("When the repository is not allowed in the namespace", func() {
+		const (
+			resourceName  = "test-forbidden"
+			namespaceName = "policy-restricted"
+		)
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: namespaceName}
+
+		var githubIssuePolicy *trainingv1alpha1.GithubIssuePolicy
+
+		BeforeEach(func() {
+			ns := &corev1.Namespace{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: namespaceName}, ns); apiErrors.IsNotFound(err) {
+				ns = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: namespaceName, Labels: map[string]string{"team": "a"}}}
+				Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+			}
+
+			githubIssuePolicy = &trainingv1alpha1.GithubIssuePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+				Spec: trainingv1alpha1.GithubIssuePolicySpec{
+					NamespaceSelector:   metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+					AllowedRepositories: []string{"owner/allowed-*"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, githubIssuePolicy)).To(Succeed())
+
+			ghi := &trainingv1alpha1.GithubIssue{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: namespaceName},
+				Spec: trainingv1alpha1.GithubIssueSpec{
+					Repo:  "https://api.github.com/repos/owner/other",
+					Title: "forbidden",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ghi)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			Expect(k8sClient.Delete(ctx, githubIssuePolicy)).To(Succeed())
+
+			ghi := &trainingv1alpha1.GithubIssue{}
+			if err := k8sClient.Get(ctx, typeNamespacedName, ghi); err == nil {
+				ghi.Finalizers = nil
+				Expect(k8sClient.Update(ctx, ghi)).To(Succeed())
+				Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, ghi))).To(Succeed())
+			}
+		})
+
+		It("should report the GithubIssue as forbidden and release it on deletion", func() {
+			controllerReconciler := &GithubIssueReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			By("adding the finalizer and then checking the policy")
+			for i := 0; i < 2; i++ {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			ghi := &trainingv1alpha1.GithubIssue{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+			condition := meta.FindStatusCondition(ghi.Status.Conditions, conditionForbidden)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+			Expect(condition.Reason).To(Equal("RepositoryNotAllowed"))
+			Expect(condition.Message).To(ContainSubstring("team-a"))
+
+			By("removing the finalizer without contacting GitHub")
+			Expect(k8sClient.Delete(ctx, ghi)).To(Succeed())
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apiErrors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, ghi))).To(BeTrue())
+		})
+
+		It("should match a GitLab project by the same name as the admission webhook", func() {
+			githubIssuePolicy.Spec.AllowedRepositories = append(githubIssuePolicy.Spec.AllowedRepositories, "gitlab.example.com/group/*")
+			Expect(k8sClient.Update(ctx, githubIssuePolicy)).To(Succeed())
+			controllerReconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+			for project, forbidden := range map[string]bool{"group%2Fproject": false, "other%2Fproject": true} {
+				ghi := &trainingv1alpha1.GithubIssue{
+					ObjectMeta: metav1.ObjectMeta{GenerateName: "test-gitlab-", Namespace: namespaceName},
+					Spec: trainingv1alpha1.GithubIssueSpec{
+						Provider: providerGitLab,
+						Repo:     "https://gitlab.example.com/api/v4/projects/" + project,
+						Title:    "gitlab",
+					},
+				}
+				Expect(k8sClient.Create(ctx, ghi)).To(Succeed())
+				Expect(controllerReconciler.checkGithubIssuePolicy(ctx, ghi)).To(Equal(forbidden), project)
+				Expect(k8sClient.Delete(ctx, ghi)).To(Succeed())
+			}
+		})
+
+		It("should match a Jira project by the same key as the admission webhook", func() {
+			githubIssuePolicy.Spec.AllowedRepositories = append(githubIssuePolicy.Spec.AllowedRepositories, "jira.example.com/OPS")
+			Expect(k8sClient.Update(ctx, githubIssuePolicy)).To(Succeed())
+			controllerReconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+			for key, forbidden := range map[string]bool{"OPS": false, "DEV": true} {
+				ghi := &trainingv1alpha1.GithubIssue{
+					ObjectMeta: metav1.ObjectMeta{GenerateName: "test-jira-", Namespace: namespaceName},
+					Spec: trainingv1alpha1.GithubIssueSpec{
+						Provider: providerJira,
+						Repo:     "https://jira.example.com/rest/api/2/project/" + key,
+						Title:    "jira",
+					},
+				}
+				Expect(k8sClient.Create(ctx, ghi)).To(Succeed())
+				Expect(controllerReconciler.checkGithubIssuePolicy(ctx, ghi)).To(Equal(forbidden), key)
+				Expect(k8sClient.Delete(ctx, ghi)).To(Succeed())
+			}
+		})
+
+		It("should close an issue filed before the policy on deletion", func() {
+			gitHubServer.Reset()
+			gitHubServer.SetToken("token")
+			gitHubServer.SetRateLimit(githubfake.DefaultRateLimit)
+			Expect(os.Setenv(tokenEnvVar, "token")).To(Succeed())
+			defer func() { Expect(os.Unsetenv(tokenEnvVar)).To(Succeed()) }()
+			controllerReconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), GitHubAPIURL: gitHubServer.URL}
+
+			By("recording an issue filed before the policy")
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			gitHubServer.CreateIssue("owner/other", githubfake.Issue{Title: "forbidden"})
+			ghi := &trainingv1alpha1.GithubIssue{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+			ghi.Status.IssueNumber = 1
+			Expect(k8sClient.Status().Update(ctx, ghi)).To(Succeed())
+
+			By("closing the issue once the GithubIssue is deleted")
+			Expect(k8sClient.Delete(ctx, ghi)).To(Succeed())
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			issue, found := gitHubServer.Issue("owner/other", 1)
+			Expect(found).To(BeTrue())
+			Expect(issue.State).To(Equal("closed"))
+			Expect(apiErrors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, ghi))).To(BeTrue())
+		})
+	})
+})
@@ -0,0 +1,109 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"slices"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// githubIssuePredicate drops the GithubIssue updates caused by status, finalizer and label writes, which
+// would otherwise reconcile against GitHub after every write of the controller. Spec changes, annotation
+// changes such as pausing, and deletions are reconciled right away, everything else waits for the resync.
+// GithubPullRequests are filtered the same way.
+func githubIssuePredicate() predicate.Predicate {
+	return predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		predicate.AnnotationChangedPredicate{},
+		deletionStartedPredicate(),
+	)
+}
+
+// deletionStartedPredicate passes the update setting the deletion timestamp
+func deletionStartedPredicate() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			if e.ObjectOld == nil || e.ObjectNew == nil {
+				return false
+			}
+			return e.ObjectOld.GetDeletionTimestamp().IsZero() && !e.ObjectNew.GetDeletionTimestamp().IsZero()
+		},
+	}
+}
+
+// ruleTargetPredicate drops the updates of objects watched by GithubIssueRules that change nothing rules match
+// on, such as the heartbeats of Nodes and the counts of repeated Events. Creations, deletions and label changes,
+// which change what a selector selects, are passed.
+func ruleTargetPredicate() predicate.Predicate {
+	return predicate.Or(
+		predicate.LabelChangedPredicate{},
+		predicate.Funcs{
+			UpdateFunc: func(e event.UpdateEvent) bool {
+				if e.ObjectOld == nil || e.ObjectNew == nil {
+					return false
+				}
+				return !slices.Equal(ruleTargetState(e.ObjectOld), ruleTargetState(e.ObjectNew))
+			},
+		},
+	)
+}
+
+// ruleTargetState returns the state rule conditions are matched against: the type, status and reason of the
+// status conditions, the reasons of Pods and their containers, and the type and reason of Events
+func ruleTargetState(obj client.Object) []string {
+	var state []string
+	addCondition := func(conditionType string, status corev1.ConditionStatus, reason string) {
+		state = append(state, conditionType+"/"+string(status)+"/"+reason)
+	}
+
+	switch obj := obj.(type) {
+	case *corev1.Pod:
+		state = append(state, obj.Status.Reason)
+		for _, c := range obj.Status.Conditions {
+			addCondition(string(c.Type), c.Status, c.Reason)
+		}
+		for _, s := range slices.Concat(obj.Status.InitContainerStatuses, obj.Status.ContainerStatuses) {
+			if s.State.Waiting != nil {
+				state = append(state, s.Name+"/waiting/"+s.State.Waiting.Reason)
+			}
+			if s.State.Terminated != nil {
+				state = append(state, s.Name+"/terminated/"+s.State.Terminated.Reason)
+			}
+		}
+	case *appsv1.Deployment:
+		for _, c := range obj.Status.Conditions {
+			addCondition(string(c.Type), c.Status, c.Reason)
+		}
+	case *batchv1.Job:
+		for _, c := range obj.Status.Conditions {
+			addCondition(string(c.Type), c.Status, c.Reason)
+		}
+	case *corev1.Node:
+		for _, c := range obj.Status.Conditions {
+			addCondition(string(c.Type), c.Status, c.Reason)
+		}
+	case *corev1.Event:
+		state = append(state, obj.Type, obj.Reason)
+	}
+	return state
+}
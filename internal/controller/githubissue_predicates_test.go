@@ -0,0 +1,84 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+var _ = Describe("GithubIssue predicates", func() {
+	old := &trainingv1alpha1.GithubIssue{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-predicates", Namespace: "default", Generation: 1},
+	}
+
+	DescribeTable("filtering updates",
+		func(mutate func(*trainingv1alpha1.GithubIssue), expected bool) {
+			updated := old.DeepCopy()
+			mutate(updated)
+			Expect(githubIssuePredicate().Update(event.UpdateEvent{ObjectOld: old, ObjectNew: updated})).To(Equal(expected))
+		},
+		Entry("spec change", func(ghi *trainingv1alpha1.GithubIssue) { ghi.Generation = 2 }, true),
+		Entry("annotation change", func(ghi *trainingv1alpha1.GithubIssue) {
+			ghi.Annotations = map[string]string{pausedAnnotationKey: "true"}
+		}, true),
+		Entry("deletion", func(ghi *trainingv1alpha1.GithubIssue) {
+			now := metav1.Now()
+			ghi.DeletionTimestamp = &now
+		}, true),
+		Entry("status write", func(ghi *trainingv1alpha1.GithubIssue) { ghi.Status.IssueNumber = 3 }, false),
+		Entry("finalizer write", func(ghi *trainingv1alpha1.GithubIssue) {
+			ghi.Finalizers = []string{myFinalizerName}
+		}, false),
+		Entry("label write", func(ghi *trainingv1alpha1.GithubIssue) {
+			ghi.Labels = map[string]string{dedupLabelKey: "key"}
+		}, false),
+	)
+})
+
+var _ = Describe("GithubIssueRule target predicates", func() {
+	old := &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node", ResourceVersion: "1"},
+		Status: corev1.NodeStatus{Conditions: []corev1.NodeCondition{
+			{Type: corev1.NodeReady, Status: corev1.ConditionTrue, Reason: "KubeletReady"},
+		}},
+	}
+
+	DescribeTable("filtering updates",
+		func(mutate func(*corev1.Node), expected bool) {
+			updated := old.DeepCopy()
+			mutate(updated)
+			Expect(ruleTargetPredicate().Update(event.UpdateEvent{ObjectOld: old, ObjectNew: updated})).To(Equal(expected))
+		},
+		Entry("heartbeat", func(node *corev1.Node) {
+			node.ResourceVersion = "2"
+			node.Status.Conditions[0].LastHeartbeatTime = metav1.Now()
+		}, false),
+		Entry("condition change", func(node *corev1.Node) {
+			node.Status.Conditions[0].Status = corev1.ConditionFalse
+			node.Status.Conditions[0].Reason = "KubeletNotReady"
+		}, true),
+		Entry("label change", func(node *corev1.Node) {
+			node.Labels = map[string]string{"role": "worker"}
+		}, true),
+	)
+})
@@ -0,0 +1,320 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+// githubIssueProjectQuery reads the fields of the project and the project items of the issue in one request
+const githubIssueProjectQuery = `query GithubIssueProject($owner: String!, $number: Int!, $issue: ID!) {
+  repositoryOwner(login: $owner) {
+    ... on ProjectV2Owner {
+      projectV2(number: $number) {
+        id
+        fields(first: 100) {
+          nodes {
+            ... on ProjectV2FieldCommon { id name dataType }
+            ... on ProjectV2SingleSelectField { options { id name } }
+            ... on ProjectV2IterationField {
+              configuration { iterations { id title } completedIterations { id title } }
+            }
+          }
+        }
+      }
+    }
+  }
+  node(id: $issue) {
+    ... on Issue {
+      projectItems(first: 100) {
+        nodes {
+          id
+          project { id }
+          fieldValues(first: 100) {
+            nodes {
+              ... on ProjectV2ItemFieldSingleSelectValue { optionId field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldIterationValue { iterationId field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldTextValue { text field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldNumberValue { number field { ... on ProjectV2FieldCommon { name } } }
+              ... on ProjectV2ItemFieldDateValue { date field { ... on ProjectV2FieldCommon { name } } }
+            }
+          }
+        }
+      }
+    }
+  }
+}`
+
+const addProjectItemMutation = `mutation AddProjectItem($project: ID!, $content: ID!) {
+  addProjectV2ItemById(input: {projectId: $project, contentId: $content}) { item { id } }
+}`
+
+const updateProjectItemFieldMutation = `mutation UpdateProjectItemField($project: ID!, $item: ID!, $field: ID!, $value: ProjectV2FieldValue!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field, value: $value}) {
+    projectV2Item { id }
+  }
+}`
+
+type gitHubProjectResponse struct {
+	RepositoryOwner *struct {
+		ProjectV2 *gitHubProject `json:"projectV2"`
+	} `json:"repositoryOwner"`
+	Node *struct {
+		ProjectItems struct {
+			Nodes []gitHubProjectItem `json:"nodes"`
+		} `json:"projectItems"`
+	} `json:"node"`
+}
+
+type gitHubProject struct {
+	ID     string `json:"id"`
+	Fields struct {
+		Nodes []gitHubProjectField `json:"nodes"`
+	} `json:"fields"`
+}
+
+type gitHubProjectField struct {
+	ID            string                `json:"id"`
+	Name          string                `json:"name"`
+	DataType      string                `json:"dataType"`
+	Options       []gitHubProjectOption `json:"options"`
+	Configuration *struct {
+		Iterations          []gitHubProjectOption `json:"iterations"`
+		CompletedIterations []gitHubProjectOption `json:"completedIterations"`
+	} `json:"configuration"`
+}
+
+// gitHubProjectOption is an option of a single select field, or an iteration which has a title instead of a name
+type gitHubProjectOption struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Title string `json:"title"`
+}
+
+type gitHubProjectItem struct {
+	ID      string `json:"id"`
+	Project struct {
+		ID string `json:"id"`
+	} `json:"project"`
+	FieldValues struct {
+		Nodes []gitHubProjectFieldValue `json:"nodes"`
+	} `json:"fieldValues"`
+}
+
+// gitHubProjectFieldValue is a field value of a project item, values of other field types decode empty
+type gitHubProjectFieldValue struct {
+	Field struct {
+		Name string `json:"name"`
+	} `json:"field"`
+	OptionID    *string  `json:"optionId"`
+	IterationID *string  `json:"iterationId"`
+	Text        *string  `json:"text"`
+	Number      *float64 `json:"number"`
+	Date        *string  `json:"date"`
+}
+
+// syncGithubIssueProject adds the GitHub issue to the spec.project board and sets the custom fields of its
+// project item to spec.project.fields. The item ID is recorded in status.projectItemID. Removing spec.project
+// leaves the item on the board.
+func (r *GithubIssueReconciler) syncGithubIssueProject(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, repoURL string, issueNodeID string, accessToken string) error {
+	log := log.FromContext(ctx)
+
+	project := ghi.Spec.Project
+	if project == nil {
+		return r.recordGithubIssueProjectItem(ctx, ghi, "")
+	}
+	if issueNodeID == "" {
+		return fmt.Errorf("GitHub issue %d has no node ID", ghi.Status.IssueNumber)
+	}
+
+	url := gitHubGraphQLURL(repoURL)
+	var response gitHubProjectResponse
+	request := graphQLRequest{
+		Query:         githubIssueProjectQuery,
+		OperationName: "GithubIssueProject",
+		Variables:     map[string]interface{}{"owner": project.Owner, "number": project.Number, "issue": issueNodeID},
+	}
+	if err := doGraphQLRequest(url, request, accessToken, &response); err != nil {
+		return err
+	}
+	if response.RepositoryOwner == nil || response.RepositoryOwner.ProjectV2 == nil {
+		return fmt.Errorf("project %d of %s was not found", project.Number, project.Owner)
+	}
+	board := response.RepositoryOwner.ProjectV2
+
+	var item *gitHubProjectItem
+	if response.Node != nil {
+		for i, candidate := range response.Node.ProjectItems.Nodes {
+			if candidate.Project.ID == board.ID {
+				item = &response.Node.ProjectItems.Nodes[i]
+			}
+		}
+	}
+	if item == nil {
+		log.Info("Adding GitHub issue to project", "owner", project.Owner, "project", project.Number)
+		request := graphQLRequest{
+			Query:         addProjectItemMutation,
+			OperationName: "AddProjectItem",
+			Variables:     map[string]interface{}{"project": board.ID, "content": issueNodeID},
+		}
+		// In dry-run mode there is no item to set the fields of
+		if r.dryRunRequest(ctx, http.MethodPost, url, request.Variables) {
+			return nil
+		}
+		var added struct {
+			AddProjectV2ItemByID struct {
+				Item struct {
+					ID string `json:"id"`
+				} `json:"item"`
+			} `json:"addProjectV2ItemById"`
+		}
+		if err := doGraphQLRequest(url, request, accessToken, &added); err != nil {
+			return err
+		}
+		item = &gitHubProjectItem{ID: added.AddProjectV2ItemByID.Item.ID}
+	}
+
+	// The item ID is recorded first, so it is reported even when setting a field fails
+	if err := r.recordGithubIssueProjectItem(ctx, ghi, item.ID); err != nil {
+		return err
+	}
+
+	current := make(map[string]gitHubProjectFieldValue, len(item.FieldValues.Nodes))
+	for _, value := range item.FieldValues.Nodes {
+		current[strings.ToLower(value.Field.Name)] = value
+	}
+	for _, desired := range project.Fields {
+		field, found := projectField(board, desired.Name)
+		if !found {
+			return fmt.Errorf("project %d of %s has no field %q", project.Number, project.Owner, desired.Name)
+		}
+		value, err := projectFieldValue(field, desired.Value)
+		if err != nil {
+			return err
+		}
+		if reflect.DeepEqual(currentProjectFieldValue(current[strings.ToLower(field.Name)]), value) {
+			continue
+		}
+
+		log.Info("Setting project field", "field", field.Name, "value", desired.Value)
+		request := graphQLRequest{
+			Query:         updateProjectItemFieldMutation,
+			OperationName: "UpdateProjectItemField",
+			Variables:     map[string]interface{}{"project": board.ID, "item": item.ID, "field": field.ID, "value": value},
+		}
+		if r.dryRunRequest(ctx, http.MethodPost, url, request.Variables) {
+			continue
+		}
+		if err := doGraphQLRequest(url, request, accessToken, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// projectField returns the field of the project with the given name, ignoring case as the board does
+func projectField(board *gitHubProject, name string) (gitHubProjectField, bool) {
+	for _, field := range board.Fields.Nodes {
+		if strings.EqualFold(field.Name, name) {
+			return field, true
+		}
+	}
+	return gitHubProjectField{}, false
+}
+
+// projectFieldValue returns the ProjectV2FieldValue input setting field to value
+func projectFieldValue(field gitHubProjectField, value string) (map[string]interface{}, error) {
+	switch field.DataType {
+	case "SINGLE_SELECT":
+		for _, option := range field.Options {
+			if strings.EqualFold(option.Name, value) {
+				return map[string]interface{}{"singleSelectOptionId": option.ID}, nil
+			}
+		}
+		return nil, fmt.Errorf("project field %s has no option %q", field.Name, value)
+	case "ITERATION":
+		if field.Configuration != nil {
+			iterations := append(append([]gitHubProjectOption(nil), field.Configuration.Iterations...), field.Configuration.CompletedIterations...)
+			for _, iteration := range iterations {
+				if strings.EqualFold(iteration.Title, value) {
+					return map[string]interface{}{"iterationId": iteration.ID}, nil
+				}
+			}
+		}
+		return nil, fmt.Errorf("project field %s has no iteration %q", field.Name, value)
+	case "NUMBER":
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("value %q of project field %s is not a number", value, field.Name)
+		}
+		return map[string]interface{}{"number": number}, nil
+	case "DATE":
+		if _, err := time.Parse(time.DateOnly, value); err != nil {
+			return nil, fmt.Errorf("value %q of project field %s is not a date as YYYY-MM-DD", value, field.Name)
+		}
+		return map[string]interface{}{"date": value}, nil
+	case "TEXT":
+		return map[string]interface{}{"text": value}, nil
+	default:
+		return nil, fmt.Errorf("project field %s of type %s cannot be set", field.Name, field.DataType)
+	}
+}
+
+// currentProjectFieldValue returns the field value of the item in the form of projectFieldValue, nil when unset
+func currentProjectFieldValue(value gitHubProjectFieldValue) map[string]interface{} {
+	switch {
+	case value.OptionID != nil:
+		return map[string]interface{}{"singleSelectOptionId": *value.OptionID}
+	case value.IterationID != nil:
+		return map[string]interface{}{"iterationId": *value.IterationID}
+	case value.Number != nil:
+		return map[string]interface{}{"number": *value.Number}
+	case value.Date != nil:
+		// Dates may be returned with a time
+		date, _, _ := strings.Cut(*value.Date, "T")
+		return map[string]interface{}{"date": date}
+	case value.Text != nil:
+		return map[string]interface{}{"text": *value.Text}
+	default:
+		return nil
+	}
+}
+
+// recordGithubIssueProjectItem records the ID of the project item of the issue in the status when it changed
+func (r *GithubIssueReconciler) recordGithubIssueProjectItem(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, itemID string) error {
+	if ghi.Status.ProjectItemID == itemID {
+		return nil
+	}
+	base := ghi.DeepCopy()
+	ghi.Status.ProjectItemID = itemID
+	if err := r.patchGithubIssueStatus(ctx, ghi, base); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to record the project item of the GithubIssue")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	"Shai1-Levi/githubissues-operator.git/internal/githubfake"
+)
+
+var _ = Describe("GithubIssue project", func() {
+	const (
+		resourceName = "test-project"
+		fullName     = "owner/project"
+	)
+
+	ctx := context.Background()
+	typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	var reconciler *GithubIssueReconciler
+	reconcileGithubIssue := func() error {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		return err
+	}
+	projectItem := func() githubfake.ProjectItem {
+		project, found := gitHubServer.Project("org", 1)
+		Expect(found).To(BeTrue())
+		Expect(project.Items).To(HaveLen(1))
+		return project.Items[0]
+	}
+
+	BeforeEach(func() {
+		gitHubServer.Reset()
+		gitHubServer.SetToken("token")
+		gitHubServer.SetRateLimit(githubfake.DefaultRateLimit)
+		Expect(os.Setenv(tokenEnvVar, "token")).To(Succeed())
+		reconciler = &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), GitHubAPIURL: gitHubServer.URL}
+
+		gitHubServer.CreateProject("org", 1, []githubfake.ProjectField{
+			{Name: "Status", DataType: "SINGLE_SELECT", Options: []string{"Todo", "In Progress", "Done"}},
+			{Name: "Priority", DataType: "SINGLE_SELECT", Options: []string{"P0", "P1", "P2"}},
+			{Name: "Iteration", DataType: "ITERATION", Options: []string{"Sprint 1", "Sprint 2"}},
+			{Name: "Estimate", DataType: "NUMBER"},
+		})
+
+		resource := &trainingv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec: trainingv1alpha1.GithubIssueSpec{
+				Repo:        "https://api.github.com/repos/" + fullName,
+				Title:       "title",
+				Description: "body",
+				Project: &trainingv1alpha1.ProjectSpec{
+					Owner:  "org",
+					Number: 1,
+					Fields: []trainingv1alpha1.ProjectFieldValue{
+						{Name: "Status", Value: "Todo"},
+						{Name: "Priority", Value: "P1"},
+						{Name: "Iteration", Value: "Sprint 2"},
+						{Name: "Estimate", Value: "3"},
+					},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv(tokenEnvVar)).To(Succeed())
+
+		resource := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+		resource.Finalizers = nil
+		Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+		Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).To(Succeed())
+	})
+
+	It("should add the issue to the project and keep its fields in sync", func() {
+		By("filing the issue and adding it to the project")
+		Expect(reconcileGithubIssue()).To(Succeed())
+		Expect(reconcileGithubIssue()).To(Succeed())
+		Expect(reconcileGithubIssue()).To(Succeed())
+
+		item := projectItem()
+		Expect(item.ContentID).To(Equal(githubfake.IssueNodeID(fullName, 1)))
+		Expect(item.Values).To(Equal(map[string]string{"Status": "Todo", "Priority": "P1", "Iteration": "Sprint 2", "Estimate": "3"}))
+
+		ghi := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		Expect(ghi.Status.ProjectItemID).To(Equal(item.ID))
+
+		By("setting back a value changed on the board")
+		Expect(gitHubServer.SetProjectItemValue("org", 1, item.ID, "Priority", "P0")).To(BeTrue())
+		Expect(reconcileGithubIssue()).To(Succeed())
+		Expect(projectItem().Values).To(HaveKeyWithValue("Priority", "P1"))
+
+		By("applying a changed spec value")
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		ghi.Spec.Project.Fields[0].Value = "In Progress"
+		Expect(k8sClient.Update(ctx, ghi)).To(Succeed())
+		Expect(reconcileGithubIssue()).To(Succeed())
+		Expect(projectItem().Values).To(HaveKeyWithValue("Status", "In Progress"))
+
+		By("not writing values that are already set")
+		requests := len(gitHubServer.Requests())
+		Expect(reconcileGithubIssue()).To(Succeed())
+		for _, request := range gitHubServer.Requests()[requests:] {
+			Expect(request.Body).NotTo(ContainSubstring("UpdateProjectItemField"))
+			Expect(request.Body).NotTo(ContainSubstring("AddProjectItem"))
+		}
+	})
+
+	It("should report an unknown option in the Synced condition", func() {
+		ghi := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		ghi.Spec.Project.Fields[1].Value = "P9"
+		Expect(k8sClient.Update(ctx, ghi)).To(Succeed())
+
+		Expect(reconcileGithubIssue()).To(Succeed())
+		Expect(reconcileGithubIssue()).To(Succeed())
+		Expect(reconcileGithubIssue()).To(MatchError(ContainSubstring(`no option "P9"`)))
+
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(ghi.Status.Conditions, conditionSynced)).To(BeTrue())
+		Expect(ghi.Status.ProjectItemID).To(Equal(projectItem().ID))
+	})
+})
@@ -0,0 +1,66 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+const (
+	stateClosed = "Closed"
+
+	// conditionClosed is True while the issue is closed because spec.state is Closed
+	conditionClosed = "Closed"
+)
+
+// observeGithubIssueState records in the Closed condition whether spec.state asks for the issue to be closed,
+// which it returns. The condition is only added once the state is set to Closed, so it tells which open
+// GithubIssues had their issue closed by the operator.
+func (r *GithubIssueReconciler) observeGithubIssueState(ctx context.Context, ghi *trainingv1alpha1.GithubIssue) (bool, error) {
+	closed := ghi.Spec.State == stateClosed
+	if !closed && meta.FindStatusCondition(ghi.Status.Conditions, conditionClosed) == nil {
+		return false, nil
+	}
+
+	condition := metav1.Condition{
+		Type:               conditionClosed,
+		Status:             metav1.ConditionFalse,
+		Reason:             "StateOpen",
+		Message:            "spec.state is Open",
+		ObservedGeneration: ghi.Generation,
+	}
+	if closed {
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "StateClosed"
+		condition.Message = "spec.state is Closed"
+	}
+
+	base := ghi.DeepCopy()
+	if meta.SetStatusCondition(&ghi.Status.Conditions, condition) {
+		if err := r.patchGithubIssueStatus(ctx, ghi, base); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to update GithubIssue state condition")
+			return false, err
+		}
+	}
+	return closed, nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	"Shai1-Levi/githubissues-operator.git/internal/githubfake"
+)
+
+var _ = Describe("GithubIssue state", func() {
+	const (
+		resourceName = "test-state"
+		fullName     = "owner/state"
+	)
+
+	ctx := context.Background()
+	typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	BeforeEach(func() {
+		gitHubServer.Reset()
+		gitHubServer.SetToken("token")
+		gitHubServer.SetRateLimit(githubfake.DefaultRateLimit)
+		Expect(os.Setenv(tokenEnvVar, "token")).To(Succeed())
+
+		resource := &trainingv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec: trainingv1alpha1.GithubIssueSpec{
+				Repo:        "https://api.github.com/repos/" + fullName,
+				Title:       "title",
+				Description: "body",
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv(tokenEnvVar)).To(Succeed())
+
+		resource := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, resource)).To(Succeed())
+		resource.Finalizers = nil
+		Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+		Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).To(Succeed())
+	})
+
+	It("should close the issue while spec.state is Closed and reopen it afterwards", func() {
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), GitHubAPIURL: gitHubServer.URL}
+		reconcileGithubIssue := func() {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		setState := func(state string) {
+			ghi := &trainingv1alpha1.GithubIssue{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+			ghi.Spec.State = state
+			Expect(k8sClient.Update(ctx, ghi)).To(Succeed())
+		}
+		issueState := func() string {
+			issue, found := gitHubServer.Issue(fullName, 1)
+			Expect(found).To(BeTrue())
+			return issue.State
+		}
+
+		By("filing an open issue")
+		reconcileGithubIssue()
+		reconcileGithubIssue()
+		reconcileGithubIssue()
+		Expect(issueState()).To(Equal("open"))
+
+		By("closing the issue")
+		setState(stateClosed)
+		reconcileGithubIssue()
+		Expect(issueState()).To(Equal("closed"))
+		ghi := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		Expect(meta.IsStatusConditionTrue(ghi.Status.Conditions, conditionClosed)).To(BeTrue())
+
+		By("keeping the issue closed")
+		reconcileGithubIssue()
+		Expect(issueState()).To(Equal("closed"))
+
+		By("reopening the issue")
+		setState("Open")
+		reconcileGithubIssue()
+		Expect(issueState()).To(Equal("open"))
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		Expect(meta.IsStatusConditionFalse(ghi.Status.Conditions, conditionClosed)).To(BeTrue())
+	})
+})
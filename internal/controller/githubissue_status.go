@@ -0,0 +1,213 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+// conditionSynced reports whether the GitHub requests of the last reconcile succeeded
+const conditionSynced = "Synced"
+
+// githubIssueNumber returns the number of the GitHub issue managed by ghi, or an empty string when none was filed.
+// status.issueNumber is recorded by the controller, the annotation is kept for GithubIssues filed by older
+// versions and for referencing an existing issue.
+func githubIssueNumber(ghi *trainingv1alpha1.GithubIssue) string {
+	if ghi.Status.IssueNumber != 0 {
+		return strconv.FormatInt(ghi.Status.IssueNumber, 10)
+	}
+	return ghi.Annotations[annotationKey]
+}
+
+// recordGithubIssueNumber records the number of the filed GitHub issue in status.issueNumber
+func (r *GithubIssueReconciler) recordGithubIssueNumber(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, issueNumber string) error {
+	number, err := strconv.ParseInt(issueNumber, 10, 64)
+	if err != nil {
+		return err
+	}
+	if ghi.Status.IssueNumber == number {
+		return nil
+	}
+
+	base := ghi.DeepCopy()
+	ghi.Status.IssueNumber = number
+	if err := r.patchGithubIssueStatus(ctx, ghi, base); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to record the GitHub issue number", "issue", issueNumber)
+		return err
+	}
+	return nil
+}
+
+// patchGithubIssueStatus sends the status changes made to ghi since base as a merge patch, so status writes
+// do not conflict with concurrent edits of the spec or metadata
+func (r *GithubIssueReconciler) patchGithubIssueStatus(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, base *trainingv1alpha1.GithubIssue) error {
+	return r.Status().Patch(ctx, ghi, client.MergeFrom(base))
+}
+
+// patchGithubIssueFinalizer adds or removes the finalizer of the controller with an optimistic-lock merge patch,
+// so the finalizers of others are never overwritten. On conflict the GithubIssue is read again and the patch retried.
+func (r *GithubIssueReconciler) patchGithubIssueFinalizer(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, add bool) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		base := ghi.DeepCopy()
+		changed := false
+		if add {
+			changed = controllerutil.AddFinalizer(ghi, myFinalizerName)
+		} else {
+			changed = controllerutil.RemoveFinalizer(ghi, myFinalizerName)
+		}
+		if !changed {
+			return nil
+		}
+
+		err := r.Patch(ctx, ghi, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+		if apiErrors.IsConflict(err) {
+			if getErr := r.Get(ctx, client.ObjectKeyFromObject(ghi), ghi); getErr != nil {
+				return getErr
+			}
+		}
+		if !add && apiErrors.IsNotFound(err) {
+			// The GithubIssue is already gone
+			return nil
+		}
+		return err
+	})
+}
+
+// gitHubStatusError is returned when GitHub, or the issue tracker of another provider, answers with an
+// unexpected status code
+type gitHubStatusError struct {
+	StatusCode int
+	// RateLimited is set when the request was rejected by the rate limit
+	RateLimited bool
+	// Provider is the issue tracker that answered, GitHub when empty
+	Provider string
+}
+
+func newGitHubStatusError(resp *http.Response) *gitHubStatusError {
+	return &gitHubStatusError{
+		StatusCode: resp.StatusCode,
+		RateLimited: resp.StatusCode == http.StatusTooManyRequests ||
+			(resp.StatusCode == http.StatusForbidden && resp.Header.Get("X-RateLimit-Remaining") == "0"),
+	}
+}
+
+func (e *gitHubStatusError) Error() string {
+	provider := e.Provider
+	if provider == "" {
+		provider = providerGitHub
+	}
+	return fmt.Sprintf("%s API returned status: %d", provider, e.StatusCode)
+}
+
+// syncFailureReason returns the reason of the Synced condition for a failed GitHub request
+func syncFailureReason(err error) string {
+	var statusErr *gitHubStatusError
+	if !errors.As(err, &statusErr) {
+		return "RequestFailed"
+	}
+	switch {
+	case statusErr.RateLimited:
+		return "RateLimited"
+	case statusErr.StatusCode == http.StatusUnauthorized:
+		return "Unauthorized"
+	case statusErr.StatusCode == http.StatusForbidden:
+		return "Forbidden"
+	case statusErr.StatusCode == http.StatusNotFound:
+		return "NotFound"
+	case statusErr.StatusCode == http.StatusUnprocessableEntity:
+		return "ValidationFailed"
+	case statusErr.StatusCode >= http.StatusInternalServerError:
+		return "GitHubUnavailable"
+	default:
+		return "RequestFailed"
+	}
+}
+
+// recordGithubIssueSynced writes the Synced condition for the outcome of the GitHub requests of the reconcile.
+// status.observedGeneration is only moved forward once the spec was synced.
+func (r *GithubIssueReconciler) recordGithubIssueSynced(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, syncErr error) error {
+	base := ghi.DeepCopy()
+	condition := metav1.Condition{
+		Type:               conditionSynced,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Synced",
+		Message:            "GitHub issue was reconciled",
+		ObservedGeneration: ghi.Generation,
+	}
+	if syncErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = syncFailureReason(syncErr)
+		condition.Message = syncErr.Error()
+	}
+
+	changed := meta.SetStatusCondition(&ghi.Status.Conditions, condition)
+	if syncErr == nil && ghi.Status.ObservedGeneration != ghi.Generation {
+		ghi.Status.ObservedGeneration = ghi.Generation
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := r.patchGithubIssueStatus(ctx, ghi, base); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update GithubIssue status")
+		return err
+	}
+	return nil
+}
+
+// githubIssueSyncFailed records a failed GitHub request in the Synced condition and returns the error,
+// so the reconcile is retried with backoff
+func (r *GithubIssueReconciler) githubIssueSyncFailed(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, err error) (ctrl.Result, error) {
+	if recordErr := r.recordGithubIssueSynced(ctx, ghi, err); recordErr != nil && !apiErrors.IsNotFound(recordErr) {
+		return ctrl.Result{}, recordErr
+	}
+	return ctrl.Result{}, err
+}
+
+// abandonGithubIssue removes the finalizer of a deleted GithubIssue whose repository can no longer be resolved,
+// leaving its issue open. The failure is recorded in the Synced condition and as a warning event.
+func (r *GithubIssueReconciler) abandonGithubIssue(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, cause error) (ctrl.Result, error) {
+	err := fmt.Errorf("the issue was left open, the repository cannot be resolved: %w", cause)
+	log.FromContext(ctx).Error(err, "Removing the finalizer of the deleted GithubIssue")
+	if recordErr := r.recordGithubIssueSynced(ctx, ghi, err); recordErr != nil && !apiErrors.IsNotFound(recordErr) {
+		return ctrl.Result{}, recordErr
+	}
+	if r.Recorder != nil {
+		r.Recorder.Event(ghi, corev1.EventTypeWarning, "IssueLeftOpen", err.Error())
+	}
+	if err := r.patchGithubIssueFinalizer(ctx, ghi, false); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	"Shai1-Levi/githubissues-operator.git/internal/githubfake"
+)
+
+var _ = Describe("GithubIssue bookkeeping", func() {
+	const (
+		resourceName   = "test-bookkeeping"
+		otherFinalizer = "example.com/other"
+	)
+
+	ctx := context.Background()
+	typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	var server *githubfake.Server
+
+	BeforeEach(func() {
+		server = githubfake.NewServer()
+		server.SetToken("token")
+		Expect(os.Setenv(tokenEnvVar, "token")).To(Succeed())
+
+		resource := &trainingv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec: trainingv1alpha1.GithubIssueSpec{
+				Repo:        "https://api.github.com/repos/owner/repo",
+				Title:       "title",
+				Description: "body",
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		Expect(os.Unsetenv(tokenEnvVar)).To(Succeed())
+	})
+
+	It("should record the issue number in status and keep the finalizers of others", func() {
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), GitHubAPIURL: server.URL}
+
+		By("adding the finalizer to a stale copy")
+		stale := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, stale)).To(Succeed())
+		current := stale.DeepCopy()
+		current.Finalizers = append(current.Finalizers, otherFinalizer)
+		Expect(k8sClient.Update(ctx, current)).To(Succeed())
+
+		Expect(reconciler.patchGithubIssueFinalizer(ctx, stale, true)).To(Succeed())
+		ghi := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		Expect(ghi.Finalizers).To(ConsistOf(otherFinalizer, myFinalizerName))
+
+		By("filing the issue")
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Requeue).To(BeTrue())
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		Expect(ghi.Status.IssueNumber).To(Equal(int64(1)))
+		Expect(ghi.Annotations).NotTo(HaveKey(annotationKey))
+		Expect(githubIssueNumber(ghi)).To(Equal("1"))
+		Expect(server.Issues("owner/repo")).To(ConsistOf(HaveField("Title", "title")))
+
+		By("syncing the filed issue")
+		result, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(BeNumerically(">", 0))
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		Expect(ghi.Status.ObservedGeneration).To(Equal(ghi.Generation))
+
+		By("closing the issue on deletion")
+		Expect(k8sClient.Delete(ctx, ghi)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		Expect(ghi.Finalizers).To(ConsistOf(otherFinalizer))
+		issue, found := server.Issue("owner/repo", 1)
+		Expect(found).To(BeTrue())
+		Expect(issue.State).To(Equal("closed"))
+
+		ghi.Finalizers = nil
+		Expect(k8sClient.Update(ctx, ghi)).To(Succeed())
+		Expect(apiErrors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, ghi))).To(BeTrue())
+	})
+})
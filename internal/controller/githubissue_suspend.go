@@ -0,0 +1,73 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+const (
+	// pausedAnnotationKey suspends the GithubIssue like spec.suspend when set to "true"
+	pausedAnnotationKey = "github-issue.kubebuilder.io/paused"
+
+	// conditionSuspended reports whether reconciliation of the GithubIssue is suspended
+	conditionSuspended = "Suspended"
+)
+
+// checkGithubIssueSuspended records spec.suspend and the paused annotation in the Suspended condition and
+// returns whether the GithubIssue is suspended. The condition is only added once the GithubIssue was suspended.
+func (r *GithubIssueReconciler) checkGithubIssueSuspended(ctx context.Context, ghi *trainingv1alpha1.GithubIssue) (bool, error) {
+	condition := metav1.Condition{
+		Type:               conditionSuspended,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Resumed",
+		Message:            "GithubIssue is reconciled",
+		ObservedGeneration: ghi.Generation,
+	}
+
+	suspended := true
+	switch {
+	case ghi.Spec.Suspend:
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "SpecSuspended"
+		condition.Message = "spec.suspend is set, GitHub is not written to"
+	case ghi.Annotations[pausedAnnotationKey] == "true":
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "Paused"
+		condition.Message = "The " + pausedAnnotationKey + " annotation is set, GitHub is not written to"
+	default:
+		suspended = false
+	}
+
+	if !suspended && meta.FindStatusCondition(ghi.Status.Conditions, conditionSuspended) == nil {
+		return false, nil
+	}
+	base := ghi.DeepCopy()
+	if meta.SetStatusCondition(&ghi.Status.Conditions, condition) {
+		if err := r.patchGithubIssueStatus(ctx, ghi, base); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to update GithubIssue status")
+			return false, err
+		}
+	}
+	return suspended, nil
+}
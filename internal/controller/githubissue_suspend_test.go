@@ -0,0 +1,111 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+var _ = Describe("GithubIssue suspension", func() {
+	const resourceName = "test-paused"
+
+	ctx := context.Background()
+	typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	var (
+		server   *httptest.Server
+		mu       sync.Mutex
+		requests []string
+	)
+
+	BeforeEach(func() {
+		requests = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			requests = append(requests, req.Method+" "+req.URL.Path)
+			_, _ = w.Write([]byte(`{"total_count": 0, "items": []}`))
+		}))
+		Expect(os.Setenv(tokenEnvVar, "token")).To(Succeed())
+
+		resource := &trainingv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        resourceName,
+				Namespace:   "default",
+				Annotations: map[string]string{annotationKey: "3", pausedAnnotationKey: "true"},
+			},
+			Spec: trainingv1alpha1.GithubIssueSpec{
+				Repo:  server.URL + "/repos/owner/repo",
+				Title: "title",
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		Expect(os.Unsetenv(tokenEnvVar)).To(Succeed())
+	})
+
+	It("should keep the finalizer while paused and close the issue once resumed", func() {
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+		By("adding the finalizer and then reporting the suspension")
+		for i := 0; i < 2; i++ {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		}
+		Expect(requests).To(BeEmpty())
+
+		ghi := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		condition := meta.FindStatusCondition(ghi.Status.Conditions, conditionSuspended)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("Paused"))
+
+		By("deleting the paused GithubIssue")
+		Expect(k8sClient.Delete(ctx, ghi)).To(Succeed())
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(BeEmpty())
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		Expect(ghi.Finalizers).To(ContainElement(myFinalizerName))
+
+		By("resuming it")
+		delete(ghi.Annotations, pausedAnnotationKey)
+		Expect(k8sClient.Update(ctx, ghi)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(requests).To(ContainElement("PATCH /repos/owner/repo/issues/3"))
+		Expect(apiErrors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, ghi))).To(BeTrue())
+	})
+})
@@ -0,0 +1,129 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+const (
+	targetStateHealthy   = "Healthy"
+	targetStateUnhealthy = "Unhealthy"
+	targetStateDeleted   = "Deleted"
+
+	autoCloseDeleted          = "Deleted"
+	autoCloseHealthy          = "Healthy"
+	autoCloseHealthyOrDeleted = "HealthyOrDeleted"
+
+	defaultHealthyConditionType = "Ready"
+
+	// conditionTargetResolved is True while the issue is closed because of spec.targetRef.autoClose
+	conditionTargetResolved = "TargetResolved"
+)
+
+// observeGithubIssueTarget reads the object referenced by spec.targetRef and records its state in status.target.
+// It returns true when spec.targetRef.autoClose asks for the GitHub issue to be closed.
+func (r *GithubIssueReconciler) observeGithubIssueTarget(ctx context.Context, ghi *trainingv1alpha1.GithubIssue) (bool, error) {
+	ref := ghi.Spec.TargetRef
+	if ref == nil {
+		return false, nil
+	}
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+
+	namespace := ""
+	namespaced, err := r.IsObjectNamespaced(obj)
+	if err != nil {
+		return false, fmt.Errorf("failed to resolve target kind %s: %w", ref.Kind, err)
+	}
+	if namespaced {
+		// Only targets of the GithubIssue namespace are read, as for the template objects
+		if ref.Namespace != "" && ref.Namespace != ghi.Namespace {
+			return false, fmt.Errorf("target %s %s must be in the GithubIssue namespace %s, not %s",
+				ref.Kind, ref.Name, ghi.Namespace, ref.Namespace)
+		}
+		namespace = ghi.Namespace
+	}
+
+	state := targetStateUnhealthy
+	if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, obj); err != nil {
+		if !apiErrors.IsNotFound(err) {
+			return false, fmt.Errorf("failed to get target %s %s: %w", ref.Kind, ref.Name, err)
+		}
+		state = targetStateDeleted
+	} else {
+		conditionType := ref.HealthyConditionType
+		if conditionType == "" {
+			conditionType = defaultHealthyConditionType
+		}
+		if matchesRuleCondition(ref.Kind, obj.Object, trainingv1alpha1.RuleCondition{Type: conditionType}) {
+			state = targetStateHealthy
+		}
+	}
+
+	resolved := false
+	switch ref.AutoClose {
+	case autoCloseDeleted:
+		resolved = state == targetStateDeleted
+	case autoCloseHealthy:
+		resolved = state == targetStateHealthy
+	case autoCloseHealthyOrDeleted:
+		resolved = state == targetStateDeleted || state == targetStateHealthy
+	}
+
+	target := &trainingv1alpha1.TargetStatus{ObjectReference: ref.ObjectReference, State: state}
+	target.Namespace = namespace
+
+	condition := metav1.Condition{
+		Type:               conditionTargetResolved,
+		Status:             metav1.ConditionFalse,
+		Reason:             "Target" + state,
+		Message:            fmt.Sprintf("%s %s is %s", ref.Kind, ref.Name, state),
+		ObservedGeneration: ghi.Generation,
+	}
+	if resolved {
+		condition.Status = metav1.ConditionTrue
+	}
+
+	base := ghi.DeepCopy()
+	changed := meta.SetStatusCondition(&ghi.Status.Conditions, condition)
+	if !equality.Semantic.DeepEqual(ghi.Status.Target, target) {
+		ghi.Status.Target = target
+		changed = true
+	}
+	if changed {
+		if err := r.patchGithubIssueStatus(ctx, ghi, base); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to update GithubIssue target status")
+			return false, err
+		}
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+var _ = Describe("GithubIssue target", func() {
+	ctx := context.Background()
+
+	var (
+		pod *corev1.Pod
+		ghi *trainingv1alpha1.GithubIssue
+	)
+
+	BeforeEach(func() {
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "target-pod", Namespace: "default"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app"}}},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+
+		ghi = &trainingv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-target", Namespace: "default"},
+			Spec: trainingv1alpha1.GithubIssueSpec{
+				Title: "target-pod is not ready",
+				TargetRef: &trainingv1alpha1.TargetReference{
+					ObjectReference: trainingv1alpha1.ObjectReference{APIVersion: "v1", Kind: "Pod", Name: pod.Name},
+					AutoClose:       autoCloseHealthyOrDeleted,
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, ghi)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Delete(ctx, ghi)).To(Succeed())
+	})
+
+	It("should report the target state and resolve the issue once the target is healthy or deleted", func() {
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+		resolved, err := reconciler.observeGithubIssueTarget(ctx, ghi)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved).To(BeFalse())
+		Expect(ghi.Status.Target).NotTo(BeNil())
+		Expect(ghi.Status.Target.Name).To(Equal(pod.Name))
+		Expect(ghi.Status.Target.Namespace).To(Equal("default"))
+		Expect(ghi.Status.Target.State).To(Equal(targetStateUnhealthy))
+
+		By("marking the Pod ready")
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+		resolved, err = reconciler.observeGithubIssueTarget(ctx, ghi)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved).To(BeTrue())
+		Expect(ghi.Status.Target.State).To(Equal(targetStateHealthy))
+		Expect(meta.IsStatusConditionTrue(ghi.Status.Conditions, conditionTargetResolved)).To(BeTrue())
+
+		By("deleting the Pod")
+		Expect(k8sClient.Delete(ctx, pod)).To(Succeed())
+
+		resolved, err = reconciler.observeGithubIssueTarget(ctx, ghi)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(resolved).To(BeTrue())
+		Expect(ghi.Status.Target.State).To(Equal(targetStateDeleted))
+	})
+
+	It("should not read a target of another namespace", func() {
+		ghi.Spec.TargetRef.Namespace = "kube-system"
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+		_, err := reconciler.observeGithubIssueTarget(ctx, ghi)
+		Expect(err).To(MatchError(ContainSubstring("must be in the GithubIssue namespace default")))
+		Expect(ghi.Status.Target).To(BeNil())
+		Expect(k8sClient.Delete(ctx, pod)).To(Succeed())
+	})
+})
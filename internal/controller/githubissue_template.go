@@ -0,0 +1,154 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"text/template"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	"Shai1-Levi/githubissues-operator.git/internal/policy"
+)
+
+const (
+	// conditionTemplateRendered reports whether spec.template could be rendered
+	conditionTemplateRendered = "TemplateRendered"
+)
+
+// issueTemplateContext is the data the issue templates are executed with
+type issueTemplateContext struct {
+	Issue  *trainingv1alpha1.GithubIssue
+	Data   map[string]string
+	Object map[string]interface{}
+}
+
+// +kubebuilder:rbac:groups=core,resources=pods;nodes;events,verbs=get;list;watch
+// +kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch
+
+// desiredTitleAndDescription returns the issue title and body, rendered from spec.template when it is set.
+// The TemplateRendered condition is updated when the rendering result changes.
+func (r *GithubIssueReconciler) desiredTitleAndDescription(ctx context.Context, ghi *trainingv1alpha1.GithubIssue) (string, string, error) {
+	if ghi.Spec.Template == nil {
+		return ghi.Spec.Title, ghi.Spec.Description, nil
+	}
+
+	title, description, renderErr := r.renderGithubIssueTemplate(ctx, ghi)
+
+	condition := metav1.Condition{
+		Type:               conditionTemplateRendered,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Rendered",
+		Message:            "Issue title and body were rendered from the template",
+		ObservedGeneration: ghi.Generation,
+	}
+	if renderErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "RenderFailed"
+		condition.Message = renderErr.Error()
+	}
+	base := ghi.DeepCopy()
+	if meta.SetStatusCondition(&ghi.Status.Conditions, condition) {
+		if err := r.patchGithubIssueStatus(ctx, ghi, base); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to update GithubIssue template condition")
+			return "", "", err
+		}
+	}
+
+	if renderErr != nil {
+		return "", "", renderErr
+	}
+	return title, description, nil
+}
+
+// renderGithubIssueTemplate loads the template data sources and executes the title and body templates
+func (r *GithubIssueReconciler) renderGithubIssueTemplate(ctx context.Context, ghi *trainingv1alpha1.GithubIssue) (string, string, error) {
+	tmpl := ghi.Spec.Template
+
+	data := make(map[string]string)
+	if tmpl.ConfigMapName != "" {
+		cm := &corev1.ConfigMap{}
+		if err := r.Get(ctx, types.NamespacedName{Name: tmpl.ConfigMapName, Namespace: ghi.Namespace}, cm); err != nil {
+			return "", "", fmt.Errorf("failed to get template ConfigMap %s: %w", tmpl.ConfigMapName, err)
+		}
+		for k, v := range cm.Data {
+			data[k] = v
+		}
+	}
+	for k, v := range tmpl.Data {
+		data[k] = v
+	}
+
+	templateCtx := issueTemplateContext{Issue: ghi, Data: data}
+
+	if ref := tmpl.ObjectRef; ref != nil {
+		if !policy.TemplateObjectAllowed(ref.APIVersion, ref.Kind) {
+			return "", "", fmt.Errorf("template object kind %s %s is not allowed", ref.APIVersion, ref.Kind)
+		}
+		obj := &unstructured.Unstructured{}
+		obj.SetGroupVersionKind(schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind))
+		// Only objects of the GithubIssue namespace are read, the operator must not expose other namespaces to its users
+		if ref.Namespace != "" && ref.Namespace != ghi.Namespace {
+			return "", "", fmt.Errorf("template object %s %s must be in the GithubIssue namespace %s, not %s",
+				ref.Kind, ref.Name, ghi.Namespace, ref.Namespace)
+		}
+		if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ghi.Namespace}, obj); err != nil {
+			return "", "", fmt.Errorf("failed to get template object %s %s: %w", ref.Kind, ref.Name, err)
+		}
+		templateCtx.Object = obj.Object
+	}
+
+	title, err := executeIssueTemplate("title", tmpl.Title, ghi.Spec.Title, templateCtx)
+	if err != nil {
+		return "", "", err
+	}
+	description, err := executeIssueTemplate("body", tmpl.Body, ghi.Spec.Description, templateCtx)
+	if err != nil {
+		return "", "", err
+	}
+
+	return title, description, nil
+}
+
+// executeIssueTemplate executes text as a template, fallback is returned as is when text is empty
+func executeIssueTemplate(name string, text string, fallback string, templateCtx issueTemplateContext) (string, error) {
+	if text == "" {
+		return fallback, nil
+	}
+
+	t, err := template.New(name).Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse %s template: %w", name, err)
+	}
+
+	var out bytes.Buffer
+	if err := t.Execute(&out, templateCtx); err != nil {
+		return "", fmt.Errorf("failed to execute %s template: %w", name, err)
+	}
+
+	return out.String(), nil
+}
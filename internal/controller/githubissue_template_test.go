@@ -0,0 +1,128 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+var _ = Describe("GithubIssue templates", func() {
+	ctx := context.Background()
+
+	var (
+		cm  *corev1.ConfigMap
+		pod *corev1.Pod
+		ghi *trainingv1alpha1.GithubIssue
+	)
+
+	BeforeEach(func() {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "template-data", Namespace: "default"},
+			Data:       map[string]string{"team": "storage", "owner": "from-configmap"},
+		}
+		Expect(k8sClient.Create(ctx, cm)).To(Succeed())
+
+		pod = &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "failing-pod", Namespace: "default"},
+			Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app"}}},
+		}
+		Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+
+		ghi = &trainingv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-template", Namespace: "default"},
+			Spec: trainingv1alpha1.GithubIssueSpec{
+				Title:       "static title",
+				Description: "static body",
+				Template: &trainingv1alpha1.IssueTemplateSpec{
+					Title:         "[{{ .Data.team }}] {{ .Object.metadata.name }} is failing",
+					Data:          map[string]string{"owner": "from-spec"},
+					ConfigMapName: cm.Name,
+					ObjectRef:     &trainingv1alpha1.ObjectReference{APIVersion: "v1", Kind: "Pod", Name: pod.Name},
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, ghi)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(k8sClient.Delete(ctx, ghi)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, pod)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, cm)).To(Succeed())
+	})
+
+	It("should render the title from the referenced data sources", func() {
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+		title, description, err := reconciler.desiredTitleAndDescription(ctx, ghi)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(title).To(Equal("[storage] failing-pod is failing"))
+		Expect(description).To(Equal("static body"))
+		Expect(meta.IsStatusConditionTrue(ghi.Status.Conditions, conditionTemplateRendered)).To(BeTrue())
+	})
+
+	It("should prefer spec data over the ConfigMap", func() {
+		ghi.Spec.Template.Body = "owner: {{ .Data.owner }}, issue: {{ .Issue.Name }}"
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+		_, description, err := reconciler.renderGithubIssueTemplate(ctx, ghi)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(description).To(Equal("owner: from-spec, issue: test-template"))
+	})
+
+	It("should report a failed rendering in the conditions", func() {
+		ghi.Spec.Template.ObjectRef.Name = "missing-pod"
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+		_, _, err := reconciler.desiredTitleAndDescription(ctx, ghi)
+		Expect(err).To(HaveOccurred())
+		Expect(meta.IsStatusConditionFalse(ghi.Status.Conditions, conditionTemplateRendered)).To(BeTrue())
+	})
+
+	It("should not read an object of another namespace", func() {
+		ghi.Spec.Template.ObjectRef.Namespace = "kube-system"
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+		_, _, err := reconciler.desiredTitleAndDescription(ctx, ghi)
+		Expect(err).To(MatchError(ContainSubstring("must be in the GithubIssue namespace default")))
+		Expect(meta.IsStatusConditionFalse(ghi.Status.Conditions, conditionTemplateRendered)).To(BeTrue())
+	})
+
+	It("should not read a Secret", func() {
+		secret := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "template-secret", Namespace: "default"},
+			StringData: map[string]string{"token": "secret"},
+		}
+		Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, secret)
+
+		ghi.Spec.Template.ObjectRef = &trainingv1alpha1.ObjectReference{APIVersion: "v1", Kind: "Secret", Name: secret.Name}
+		ghi.Spec.Template.Body = "{{ .Object.data }}"
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+		_, _, err := reconciler.desiredTitleAndDescription(ctx, ghi)
+		Expect(err).To(MatchError(ContainSubstring("kind v1 Secret is not allowed")))
+		Expect(meta.IsStatusConditionFalse(ghi.Status.Conditions, conditionTemplateRendered)).To(BeTrue())
+	})
+})
@@ -0,0 +1,518 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	"Shai1-Levi/githubissues-operator.git/internal/policy"
+)
+
+const (
+	// ruleLabelKey is set on generated GithubIssues to the name of the GithubIssueRule that created them
+	ruleLabelKey = "github-issue.kubebuilder.io/rule"
+	// fingerprintLabelKey is set on generated GithubIssues to the fingerprint of the matched object
+	fingerprintLabelKey = "github-issue.kubebuilder.io/fingerprint"
+
+	// conditionRuleReady reports whether the GithubIssueRule targets could be evaluated
+	conditionRuleReady = "Ready"
+)
+
+// errNodeRulesNotAllowed is returned for Node rules in namespaces no GithubIssuePolicy allows them in
+var errNodeRulesNotAllowed = errors.New("no GithubIssuePolicy allows GithubIssueRules targeting Nodes in the namespace")
+
+// GithubIssueRuleReconciler reconciles a GithubIssueRule object
+type GithubIssueRuleReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// ResyncPeriod is how often the watched objects are matched again
+	ResyncPeriod time.Duration
+
+	// Options configures the workers and rate limiter of the controller
+	Options ControllerOptions
+}
+
+// ruleMatch is an object matching a GithubIssueRule
+type ruleMatch struct {
+	incident    trainingv1alpha1.RuleIncident
+	fingerprint string
+	objectRef   trainingv1alpha1.ObjectReference
+	targetRef   trainingv1alpha1.ObjectReference
+}
+
+// +kubebuilder:rbac:groups=training.redhat.com,resources=githubissuerules,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=training.redhat.com,resources=githubissuerules/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=training.redhat.com,resources=githubissuerules/finalizers,verbs=update
+
+// Reconcile files a GithubIssue for every object matching the GithubIssueRule and deletes the GithubIssues
+// of objects that no longer match, which closes their GitHub issues through the GithubIssue finalizer.
+// Generated GithubIssues are owned by the rule, so they are garbage collected with it.
+func (r *GithubIssueRuleReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	log.Info("Begin GithubIssueRule Reconcile")
+	defer log.Info("Finish GithubIssueRule Reconcile")
+
+	// Reconcile requeue results
+	emptyResult := ctrl.Result{}
+
+	rule := &trainingv1alpha1.GithubIssueRule{}
+	if err := r.Get(ctx, req.NamespacedName, rule); err != nil {
+		if apiErrors.IsNotFound(err) {
+			log.Info("GithubIssueRule CR was not found", "name", req.Name, "namespace", req.Namespace)
+			return emptyResult, nil
+		}
+		log.Error(err, "Failed to get GithubIssueRule CR")
+		return emptyResult, err
+	}
+
+	if !rule.DeletionTimestamp.IsZero() {
+		// Owned GithubIssues are removed by the garbage collector
+		return emptyResult, nil
+	}
+
+	matches, err := r.findRuleMatches(ctx, rule)
+	if err != nil {
+		log.Error(err, "Failed to evaluate GithubIssueRule targets")
+		if statusErr := r.updateRuleStatus(ctx, rule, rule.Status.Incidents, err); statusErr != nil {
+			return emptyResult, statusErr
+		}
+		// The GithubIssues already filed are kept until a policy allows the rule again
+		if errors.Is(err, errNodeRulesNotAllowed) {
+			return ctrl.Result{RequeueAfter: resyncAfter(r.ResyncPeriod)}, nil
+		}
+		return emptyResult, err
+	}
+
+	issues := &trainingv1alpha1.GithubIssueList{}
+	if err := r.List(ctx, issues, client.InNamespace(rule.Namespace), client.MatchingLabels{ruleLabelKey: ruleLabelValue(rule.Name)}); err != nil {
+		log.Error(err, "Failed to list generated GithubIssues")
+		return emptyResult, err
+	}
+	existing := make(map[string]*trainingv1alpha1.GithubIssue, len(issues.Items))
+	for i := range issues.Items {
+		existing[issues.Items[i].Labels[fingerprintLabelKey]] = &issues.Items[i]
+	}
+
+	var incidents []trainingv1alpha1.RuleIncident
+	for _, match := range matches {
+		if _, ok := existing[match.fingerprint]; !ok {
+			ghi, err := r.newRuleGithubIssue(rule, match)
+			if err != nil {
+				return emptyResult, err
+			}
+			log.Info("Creating GithubIssue for rule incident", "githubissue", ghi.Name, "kind", match.incident.Kind, "object", match.incident.Name)
+			if err := r.Create(ctx, ghi); err != nil && !apiErrors.IsAlreadyExists(err) {
+				log.Error(err, "Failed to create GithubIssue for rule incident")
+				return emptyResult, err
+			}
+		}
+		incidents = append(incidents, match.incident)
+		delete(existing, match.fingerprint)
+	}
+
+	// Whatever is left no longer matches the rule
+	for _, ghi := range existing {
+		if !ghi.DeletionTimestamp.IsZero() {
+			continue
+		}
+		log.Info("Deleting GithubIssue of resolved rule incident", "githubissue", ghi.Name)
+		if err := r.Delete(ctx, ghi); err != nil && !apiErrors.IsNotFound(err) {
+			log.Error(err, "Failed to delete GithubIssue of resolved rule incident")
+			return emptyResult, err
+		}
+	}
+
+	if err := r.updateRuleStatus(ctx, rule, incidents, nil); err != nil {
+		return emptyResult, err
+	}
+
+	return ctrl.Result{RequeueAfter: resyncAfter(r.ResyncPeriod)}, nil
+}
+
+// updateRuleStatus records the incidents and the Ready condition with a merge patch, the status is only written
+// when it changed
+func (r *GithubIssueRuleReconciler) updateRuleStatus(ctx context.Context, rule *trainingv1alpha1.GithubIssueRule, incidents []trainingv1alpha1.RuleIncident, evalErr error) error {
+	base := rule.DeepCopy()
+	condition := metav1.Condition{
+		Type:               conditionRuleReady,
+		Status:             metav1.ConditionTrue,
+		Reason:             "TargetsEvaluated",
+		Message:            fmt.Sprintf("%d object(s) match the rule", len(incidents)),
+		ObservedGeneration: rule.Generation,
+	}
+	if evalErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "EvaluationFailed"
+		condition.Message = evalErr.Error()
+		if errors.Is(evalErr, errNodeRulesNotAllowed) {
+			condition.Reason = "NodeRulesNotAllowed"
+		}
+	}
+
+	changed := meta.SetStatusCondition(&rule.Status.Conditions, condition)
+	if !equality.Semantic.DeepEqual(rule.Status.Incidents, incidents) {
+		rule.Status.Incidents = incidents
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	now := metav1.Now()
+	rule.Status.LastUpdateTime = &now
+	if err := r.Status().Patch(ctx, rule, client.MergeFrom(base)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update GithubIssueRule status")
+		return err
+	}
+	return nil
+}
+
+// findRuleMatches lists the objects selected by the rule target and returns the ones matching the rule
+// condition, deduplicated by the object they describe and sorted by fingerprint
+func (r *GithubIssueRuleReconciler) findRuleMatches(ctx context.Context, rule *trainingv1alpha1.GithubIssueRule) ([]ruleMatch, error) {
+	target := rule.Spec.Target
+
+	list, err := newRuleTargetList(target.Kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var opts []client.ListOption
+	if target.Kind == "Node" {
+		// Nodes are cluster scoped, any namespace could otherwise file issues about them
+		allowed, err := policy.NodeRulesAllowed(ctx, r.Client, rule.Namespace)
+		if err != nil {
+			return nil, err
+		}
+		if !allowed {
+			return nil, errNodeRulesNotAllowed
+		}
+	} else {
+		// Only objects of the rule namespace are watched, the operator must not expose other namespaces to its users
+		if target.Namespace != "" && target.Namespace != rule.Namespace {
+			return nil, fmt.Errorf("target namespace %s must be the GithubIssueRule namespace %s", target.Namespace, rule.Namespace)
+		}
+		opts = append(opts, client.InNamespace(rule.Namespace))
+	}
+	if target.Selector != nil {
+		selector, err := metav1.LabelSelectorAsSelector(target.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid target selector: %w", err)
+		}
+		opts = append(opts, client.MatchingLabelsSelector{Selector: selector})
+	}
+
+	if err := r.List(ctx, list, opts...); err != nil {
+		return nil, fmt.Errorf("failed to list %s objects: %w", target.Kind, err)
+	}
+	items, err := meta.ExtractList(list)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	var matches []ruleMatch
+	for _, item := range items {
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(item)
+		if err != nil {
+			return nil, err
+		}
+		if !matchesRuleCondition(target.Kind, obj, rule.Spec.Condition) {
+			continue
+		}
+
+		u := unstructured.Unstructured{Object: obj}
+		incident := trainingv1alpha1.RuleIncident{Kind: target.Kind, Namespace: u.GetNamespace(), Name: u.GetName()}
+		targetAPIVersion := ruleTargetAPIVersion(target.Kind)
+		if target.Kind == "Event" {
+			// An Event describes an incident of the object it is about
+			incident.Kind, _, _ = unstructured.NestedString(obj, "involvedObject", "kind")
+			incident.Namespace, _, _ = unstructured.NestedString(obj, "involvedObject", "namespace")
+			incident.Name, _, _ = unstructured.NestedString(obj, "involvedObject", "name")
+			targetAPIVersion, _, _ = unstructured.NestedString(obj, "involvedObject", "apiVersion")
+			if incident.Namespace != "" && incident.Namespace != rule.Namespace {
+				continue
+			}
+		}
+
+		fingerprint := ruleFingerprint(incident)
+		if seen[fingerprint] {
+			continue
+		}
+		seen[fingerprint] = true
+
+		incident.IssueName = ruleIssueName(rule.Name, fingerprint)
+		matches = append(matches, ruleMatch{
+			incident:    incident,
+			fingerprint: fingerprint,
+			objectRef: trainingv1alpha1.ObjectReference{
+				APIVersion: ruleTargetAPIVersion(target.Kind),
+				Kind:       target.Kind,
+				Name:       u.GetName(),
+				Namespace:  u.GetNamespace(),
+			},
+			targetRef: trainingv1alpha1.ObjectReference{
+				APIVersion: targetAPIVersion,
+				Kind:       incident.Kind,
+				Name:       incident.Name,
+				Namespace:  incident.Namespace,
+			},
+		})
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].fingerprint < matches[j].fingerprint })
+	return matches, nil
+}
+
+// newRuleGithubIssue builds the GithubIssue filed for a rule incident
+func (r *GithubIssueRuleReconciler) newRuleGithubIssue(rule *trainingv1alpha1.GithubIssueRule, match ruleMatch) (*trainingv1alpha1.GithubIssue, error) {
+	reason := rule.Spec.Condition.Reason
+	if reason == "" {
+		reason = rule.Spec.Condition.Type
+	}
+	objectName := match.incident.Name
+	if match.incident.Namespace != "" {
+		objectName = match.incident.Namespace + "/" + match.incident.Name
+	}
+
+	ghi := &trainingv1alpha1.GithubIssue{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      match.incident.IssueName,
+			Namespace: rule.Namespace,
+			Labels: map[string]string{
+				ruleLabelKey:        ruleLabelValue(rule.Name),
+				fingerprintLabelKey: match.fingerprint,
+			},
+		},
+		Spec: trainingv1alpha1.GithubIssueSpec{
+			Repo:          rule.Spec.Repo,
+			RepositoryRef: rule.Spec.RepositoryRef.DeepCopy(),
+			Title:         fmt.Sprintf("%s %s: %s", match.incident.Kind, objectName, reason),
+			Description: fmt.Sprintf("Filed by GithubIssueRule %s/%s because %s %s matched condition %s.",
+				rule.Namespace, rule.Name, match.incident.Kind, objectName, reason),
+		},
+	}
+
+	// The rule deletes the GithubIssue once the incident is resolved, the target is only reported
+	if match.targetRef.APIVersion != "" {
+		ghi.Spec.TargetRef = &trainingv1alpha1.TargetReference{ObjectReference: match.targetRef}
+	}
+
+	// Templates are rendered by the GithubIssue controller, so the issue follows the object as it changes
+	if rule.Spec.TitleTemplate != "" || rule.Spec.BodyTemplate != "" {
+		objectRef := match.objectRef
+		ghi.Spec.Template = &trainingv1alpha1.IssueTemplateSpec{
+			Title:     rule.Spec.TitleTemplate,
+			Body:      rule.Spec.BodyTemplate,
+			ObjectRef: &objectRef,
+		}
+	}
+
+	if err := controllerutil.SetControllerReference(rule, ghi, r.Scheme); err != nil {
+		return nil, err
+	}
+	return ghi, nil
+}
+
+// newRuleTargetList returns an empty list for the objects of a rule target kind
+func newRuleTargetList(kind string) (client.ObjectList, error) {
+	switch kind {
+	case "Pod":
+		return &corev1.PodList{}, nil
+	case "Deployment":
+		return &appsv1.DeploymentList{}, nil
+	case "Job":
+		return &batchv1.JobList{}, nil
+	case "Node":
+		return &corev1.NodeList{}, nil
+	case "Event":
+		return &corev1.EventList{}, nil
+	}
+	return nil, fmt.Errorf("unsupported target kind %q", kind)
+}
+
+// ruleTargetAPIVersion returns the API version of a rule target kind
+func ruleTargetAPIVersion(kind string) string {
+	switch kind {
+	case "Deployment":
+		return appsv1.SchemeGroupVersion.String()
+	case "Job":
+		return batchv1.SchemeGroupVersion.String()
+	}
+	return corev1.SchemeGroupVersion.String()
+}
+
+// ruleIssueName returns the name of the GithubIssue filed for the incident with the given fingerprint. Long rule
+// names are shortened so the name stays a valid object name.
+func ruleIssueName(ruleName string, fingerprint string) string {
+	return truncatedName(ruleName, validation.DNS1123SubdomainMaxLength-len(fingerprint)-1) + "-" + fingerprint
+}
+
+// ruleLabelValue returns the ruleLabelKey label value of the GithubIssues of a rule, its name shortened to
+// the length allowed for label values
+func ruleLabelValue(ruleName string) string {
+	return truncatedName(ruleName, validation.LabelValueMaxLength)
+}
+
+// truncatedName shortens name to maxLength characters. The cut off part is replaced by a hash of the whole name,
+// so distinct names stay distinct.
+func truncatedName(name string, maxLength int) string {
+	if len(name) <= maxLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(name))
+	hash := hex.EncodeToString(sum[:])[:10]
+	return strings.TrimRight(name[:maxLength-len(hash)-1], "-.") + "-" + hash
+}
+
+// ruleFingerprint identifies the object an incident is about
+func ruleFingerprint(incident trainingv1alpha1.RuleIncident) string {
+	sum := sha256.Sum256([]byte(incident.Kind + "/" + incident.Namespace + "/" + incident.Name))
+	return hex.EncodeToString(sum[:])[:10]
+}
+
+// matchesRuleCondition reports whether an object of the given kind is in the state described by the rule condition
+func matchesRuleCondition(kind string, obj map[string]interface{}, cond trainingv1alpha1.RuleCondition) bool {
+	if kind == "Event" {
+		if cond.Reason == "" && cond.Type == "" {
+			return false
+		}
+		reason, _, _ := unstructured.NestedString(obj, "reason")
+		eventType, _, _ := unstructured.NestedString(obj, "type")
+		return (cond.Reason == "" || reason == cond.Reason) && (cond.Type == "" || eventType == cond.Type)
+	}
+
+	status := string(cond.Status)
+	if status == "" {
+		status = string(metav1.ConditionTrue)
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(obj, "status", "conditions")
+	for _, c := range conditions {
+		condition, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if cond.Type == "" && cond.Reason == "" {
+			continue
+		}
+		if cond.Type != "" && condition["type"] != cond.Type {
+			continue
+		}
+		if cond.Reason != "" && condition["reason"] != cond.Reason {
+			continue
+		}
+		if condition["status"] == status {
+			return true
+		}
+	}
+
+	if kind != "Pod" || cond.Type != "" || cond.Reason == "" {
+		return false
+	}
+
+	// Pod problems such as CrashLoopBackOff are only visible in the container states
+	if reason, _, _ := unstructured.NestedString(obj, "status", "reason"); reason == cond.Reason {
+		return true
+	}
+	for _, field := range []string{"initContainerStatuses", "containerStatuses"} {
+		statuses, _, _ := unstructured.NestedSlice(obj, "status", field)
+		for _, s := range statuses {
+			containerStatus, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, state := range []string{"waiting", "terminated"} {
+				if reason, _, _ := unstructured.NestedString(containerStatus, "state", state, "reason"); reason == cond.Reason {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// rulesForKind returns a map function enqueuing the GithubIssueRules that target objects of the given kind.
+// Only the rules of the namespace of a namespaced object are listed.
+func (r *GithubIssueRuleReconciler) rulesForKind(kind string) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		var opts []client.ListOption
+		if kind != "Node" {
+			opts = append(opts, client.InNamespace(obj.GetNamespace()))
+		}
+		rules := &trainingv1alpha1.GithubIssueRuleList{}
+		if err := r.List(ctx, rules, opts...); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to list GithubIssueRules")
+			return nil
+		}
+
+		var requests []reconcile.Request
+		for _, rule := range rules.Items {
+			if rule.Spec.Target.Kind != kind {
+				continue
+			}
+			if kind != "Node" && rule.Namespace != obj.GetNamespace() {
+				continue
+			}
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&rule)})
+		}
+		return requests
+	}
+}
+
+// SetupWithManager sets up the controller with the Manager. Updates of the watched objects only enqueue the
+// rules when they change what rules match on, see ruleTargetPredicate.
+func (r *GithubIssueRuleReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	targets := builder.WithPredicates(ruleTargetPredicate())
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&trainingv1alpha1.GithubIssueRule{}).
+		Owns(&trainingv1alpha1.GithubIssue{}).
+		Watches(&corev1.Pod{}, handler.EnqueueRequestsFromMapFunc(r.rulesForKind("Pod")), targets).
+		Watches(&appsv1.Deployment{}, handler.EnqueueRequestsFromMapFunc(r.rulesForKind("Deployment")), targets).
+		Watches(&batchv1.Job{}, handler.EnqueueRequestsFromMapFunc(r.rulesForKind("Job")), targets).
+		Watches(&corev1.Node{}, handler.EnqueueRequestsFromMapFunc(r.rulesForKind("Node")), targets).
+		Watches(&corev1.Event{}, handler.EnqueueRequestsFromMapFunc(r.rulesForKind("Event")), targets).
+		WithOptions(r.Options.controllerOptions()).
+		Complete(r)
+}
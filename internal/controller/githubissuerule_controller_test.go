@@ -0,0 +1,222 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+var _ = Describe("GithubIssueRule Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-rule"
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+		var pod *corev1.Pod
+
+		BeforeEach(func() {
+			By("creating a crash looping Pod")
+			pod = &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "crashing", Namespace: "default", Labels: map[string]string{"app": "crashing"}},
+				Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app"}}},
+			}
+			Expect(k8sClient.Create(ctx, pod)).To(Succeed())
+			pod.Status.ContainerStatuses = []corev1.ContainerStatus{{
+				Name:  "app",
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+			}}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			By("creating the custom resource for the Kind GithubIssueRule")
+			rule := &trainingv1alpha1.GithubIssueRule{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: trainingv1alpha1.GithubIssueRuleSpec{
+					Repo: "https://api.github.com/repos/owner/repo",
+					Target: trainingv1alpha1.RuleTarget{
+						Kind:     "Pod",
+						Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"app": "crashing"}},
+					},
+					Condition: trainingv1alpha1.RuleCondition{Reason: "CrashLoopBackOff"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, rule)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			rule := &trainingv1alpha1.GithubIssueRule{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, rule)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, rule)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, pod)).To(Succeed())
+		})
+
+		It("should file a GithubIssue while the Pod matches and delete it once resolved", func() {
+			controllerReconciler := &GithubIssueRuleReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			issues := &trainingv1alpha1.GithubIssueList{}
+			Expect(k8sClient.List(ctx, issues, client.InNamespace("default"), client.MatchingLabels{ruleLabelKey: resourceName})).To(Succeed())
+			Expect(issues.Items).To(HaveLen(1))
+			Expect(issues.Items[0].Spec.Title).To(Equal("Pod default/crashing: CrashLoopBackOff"))
+			Expect(issues.Items[0].OwnerReferences).To(HaveLen(1))
+
+			By("reconciling again without creating a duplicate")
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.List(ctx, issues, client.InNamespace("default"), client.MatchingLabels{ruleLabelKey: resourceName})).To(Succeed())
+			Expect(issues.Items).To(HaveLen(1))
+
+			rule := &trainingv1alpha1.GithubIssueRule{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, rule)).To(Succeed())
+			Expect(rule.Status.Incidents).To(HaveLen(1))
+			Expect(rule.Status.Incidents[0].Name).To(Equal("crashing"))
+
+			By("recovering the Pod")
+			pod.Status.ContainerStatuses[0].State = corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+			Expect(k8sClient.Status().Update(ctx, pod)).To(Succeed())
+
+			_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(k8sClient.List(ctx, issues, client.InNamespace("default"), client.MatchingLabels{ruleLabelKey: resourceName})).To(Succeed())
+			Expect(issues.Items).To(BeEmpty())
+		})
+
+		It("should not watch objects of another namespace", func() {
+			rule := &trainingv1alpha1.GithubIssueRule{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, rule)).To(Succeed())
+			rule.Spec.Target.Namespace = "kube-system"
+			Expect(k8sClient.Update(ctx, rule)).To(Succeed())
+
+			controllerReconciler := &GithubIssueRuleReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).To(MatchError(ContainSubstring("must be the GithubIssueRule namespace default")))
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, rule)).To(Succeed())
+			Expect(meta.IsStatusConditionFalse(rule.Status.Conditions, conditionRuleReady)).To(BeTrue())
+		})
+	})
+
+	It("should only file issues about Nodes when a GithubIssuePolicy allows it", func() {
+		ctx := context.Background()
+		ns := &corev1.Namespace{}
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, ns); apiErrors.IsNotFound(err) {
+			ns = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+			Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+		} else {
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		node := &corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "test-rule-node", Labels: map[string]string{"rule": "node"}}}
+		Expect(k8sClient.Create(ctx, node)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, node)
+		node.Status.Conditions = []corev1.NodeCondition{{Type: corev1.NodeReady, Status: corev1.ConditionFalse, Reason: "KubeletNotReady"}}
+		Expect(k8sClient.Status().Update(ctx, node)).To(Succeed())
+
+		rule := &trainingv1alpha1.GithubIssueRule{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-node-rule", Namespace: "default"},
+			Spec: trainingv1alpha1.GithubIssueRuleSpec{
+				Repo: "https://api.github.com/repos/owner/repo",
+				Target: trainingv1alpha1.RuleTarget{
+					Kind:     "Node",
+					Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"rule": "node"}},
+				},
+				Condition: trainingv1alpha1.RuleCondition{Type: "Ready", Status: metav1.ConditionFalse},
+			},
+		}
+		Expect(k8sClient.Create(ctx, rule)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, rule)
+
+		controllerReconciler := &GithubIssueRuleReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(rule), rule)).To(Succeed())
+		condition := meta.FindStatusCondition(rule.Status.Conditions, conditionRuleReady)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("NodeRulesNotAllowed"))
+		issues := &trainingv1alpha1.GithubIssueList{}
+		Expect(k8sClient.List(ctx, issues, client.InNamespace("default"), client.MatchingLabels{ruleLabelKey: rule.Name})).To(Succeed())
+		Expect(issues.Items).To(BeEmpty())
+
+		By("allowing Node rules in the namespace")
+		githubIssuePolicy := &trainingv1alpha1.GithubIssuePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-node-rules"},
+			Spec: trainingv1alpha1.GithubIssuePolicySpec{
+				AllowedRepositories: []string{"owner/*"},
+				AllowNodeRules:      true,
+			},
+		}
+		Expect(k8sClient.Create(ctx, githubIssuePolicy)).To(Succeed())
+		DeferCleanup(k8sClient.Delete, ctx, githubIssuePolicy)
+
+		_, err = controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(rule)})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(k8sClient.List(ctx, issues, client.InNamespace("default"), client.MatchingLabels{ruleLabelKey: rule.Name})).To(Succeed())
+		Expect(issues.Items).To(HaveLen(1))
+		Expect(issues.Items[0].Spec.Title).To(Equal("Node test-rule-node: Ready"))
+		for i := range issues.Items {
+			Expect(k8sClient.Delete(ctx, &issues.Items[i])).To(Succeed())
+		}
+	})
+
+	It("should keep the names of the GithubIssues of long rule names valid", func() {
+		long := strings.Repeat("a", validation.DNS1123SubdomainMaxLength)
+		name := ruleIssueName(long, "0123456789")
+		Expect(len(name)).To(BeNumerically("<=", validation.DNS1123SubdomainMaxLength))
+		Expect(validation.IsDNS1123Subdomain(name)).To(BeEmpty())
+		Expect(name).To(HaveSuffix("-0123456789"))
+		Expect(ruleIssueName(long+"b", "0123456789")).NotTo(Equal(name))
+
+		Expect(validation.IsValidLabelValue(ruleLabelValue(long))).To(BeEmpty())
+		Expect(ruleIssueName("short", "0123456789")).To(Equal("short-0123456789"))
+		Expect(ruleLabelValue("short")).To(Equal("short"))
+	})
+
+	It("should match Jobs by condition type", func() {
+		job := &batchv1.Job{Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobFailed, Status: corev1.ConditionTrue, Reason: "BackoffLimitExceeded"},
+		}}}
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(job)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(matchesRuleCondition("Job", obj, trainingv1alpha1.RuleCondition{Type: "Failed"})).To(BeTrue())
+		Expect(matchesRuleCondition("Job", obj, trainingv1alpha1.RuleCondition{Type: "Failed", Reason: "DeadlineExceeded"})).To(BeFalse())
+		Expect(matchesRuleCondition("Job", obj, trainingv1alpha1.RuleCondition{Type: "Complete"})).To(BeFalse())
+	})
+})
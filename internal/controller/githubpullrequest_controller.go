@@ -0,0 +1,620 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	"Shai1-Levi/githubissues-operator.git/internal/policy"
+)
+
+const (
+	pullRequestFinalizerName = "github-pull-request.kubebuilder.io/finalizer"
+
+	// pullRequestMarkerPrefix starts the marker the operator appends to the body of the pull requests it opens
+	pullRequestMarkerPrefix = "<!-- github-pull-request.kubebuilder.io/owner: "
+
+	pullRequestStateOpen   = "Open"
+	pullRequestStateClosed = "Closed"
+	pullRequestStateMerged = "Merged"
+
+	checksStateSuccess = "Success"
+	checksStateFailure = "Failure"
+	checksStatePending = "Pending"
+	checksStateNone    = "None"
+
+	reviewDecisionApproved         = "Approved"
+	reviewDecisionChangesRequested = "ChangesRequested"
+	reviewDecisionReviewRequired   = "ReviewRequired"
+)
+
+// GithubPullRequestReconciler reconciles a GithubPullRequest object
+type GithubPullRequestReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// ResyncPeriod is how often the pull request is read again to refresh its mergeable state, checks and reviews
+	ResyncPeriod time.Duration
+
+	// Recorder emits the events of the skipped GitHub writes in dry-run mode
+	Recorder record.EventRecorder
+
+	// DryRun performs only read requests against GitHub, the writes are reported instead
+	DryRun bool
+
+	// GitHubAPIURL replaces https://api.github.com in repository URLs when set, e.g. to use a fake GitHub server
+	GitHubAPIURL string
+
+	// Options configures the workers and rate limiter of the controller
+	Options ControllerOptions
+
+	// repoLocks serializes the reconciles of pull requests of the same repository
+	repoLocks keyedMutex
+}
+
+// gitHubPullRequest holds the relevant parts of a GitHub pull request
+type gitHubPullRequest struct {
+	Number         int64  `json:"number"`
+	HTMLURL        string `json:"html_url"`
+	Title          string `json:"title"`
+	Body           string `json:"body"`
+	State          string `json:"state"`
+	Merged         bool   `json:"merged"`
+	Mergeable      *bool  `json:"mergeable"`
+	MergeableState string `json:"mergeable_state"`
+	Head           struct {
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	RequestedReviewers []struct {
+		Login string `json:"login"`
+	} `json:"requested_reviewers"`
+}
+
+type gitHubReview struct {
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	State string `json:"state"`
+}
+
+type gitHubCheckRuns struct {
+	CheckRuns []struct {
+		Name       string `json:"name"`
+		Status     string `json:"status"`
+		Conclusion string `json:"conclusion"`
+	} `json:"check_runs"`
+}
+
+// +kubebuilder:rbac:groups=training.redhat.com,resources=githubpullrequests,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=training.redhat.com,resources=githubpullrequests/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=training.redhat.com,resources=githubpullrequests/finalizers,verbs=update
+
+// Reconcile opens the pull request of the GithubPullRequest, keeps its title, body, labels and reviewers in sync
+// with the spec and reports its mergeable state, checks and reviews. The pull request is closed when the
+// GithubPullRequest is deleted, through the same finalizer pattern as GithubIssues.
+func (r *GithubPullRequestReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	pr := &trainingv1alpha1.GithubPullRequest{}
+	if err := r.Get(ctx, req.NamespacedName, pr); err != nil {
+		if apiErrors.IsNotFound(err) {
+			log.Info("GithubPullRequest CR was not found", "name", req.Name, "namespace", req.Namespace)
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get GithubPullRequest CR")
+		return ctrl.Result{}, err
+	}
+
+	// In dry-run mode the skipped GitHub writes are collected and reported once the reconcile is done
+	if r.DryRun {
+		ctx = withDryRunLog(ctx)
+		defer r.reportDryRunRequests(ctx, pr)
+	}
+
+	repository, err := r.resolvePullRequestRepository(ctx, pr)
+	if err != nil && !pr.DeletionTimestamp.IsZero() {
+		// A repository or credentials Secret deleted first must not keep the GithubPullRequest, and its namespace, around
+		log.Error(err, "Leaving the pull request open, the repository cannot be resolved")
+		return ctrl.Result{}, r.patchPullRequestFinalizer(ctx, pr, false)
+	}
+	if err != nil {
+		log.Error(err, "Failed to resolve the repository of the GithubPullRequest")
+		return r.pullRequestSyncFailed(ctx, pr, err)
+	}
+
+	// GithubIssuePolicies restrict pull requests as they restrict issues, a forbidden repository is never contacted
+	forbidden, err := r.checkPullRequestPolicy(ctx, pr)
+	if err != nil {
+		log.Error(err, "Failed to check the GithubIssuePolicies")
+		return ctrl.Result{}, err
+	}
+	// A pull request opened before the policy forbade the repository is still closed on deletion
+	if forbidden && (pr.DeletionTimestamp.IsZero() || pr.Status.Number == 0) {
+		if !pr.DeletionTimestamp.IsZero() {
+			return ctrl.Result{}, r.patchPullRequestFinalizer(ctx, pr, false)
+		}
+		log.Info("Repository is forbidden by a GithubIssuePolicy", "repo", repository.URL)
+		return ctrl.Result{RequeueAfter: resyncAfter(r.ResyncPeriod)}, nil
+	}
+
+	// Concurrent workers never race on the pull requests of the same repository, as for GithubIssues
+	unlock := r.repoLocks.Lock(repository.URL)
+	defer unlock()
+
+	// The object is being deleted
+	if !pr.DeletionTimestamp.IsZero() {
+		if pr.Status.Number != 0 && repository.Token != "" {
+			if err := r.closePullRequest(ctx, pr, repository); err != nil {
+				return r.pullRequestSyncFailed(ctx, pr, err)
+			}
+		}
+		if err := r.patchPullRequestFinalizer(ctx, pr, false); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	if err := r.patchPullRequestFinalizer(ctx, pr, true); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	if repository.Token == "" {
+		log.Info("No token is set for the repository")
+		return ctrl.Result{RequeueAfter: resyncAfter(r.ResyncPeriod)}, nil
+	}
+
+	if pr.Status.Number == 0 {
+		created, skipped, err := r.openPullRequest(ctx, pr, repository)
+		if err != nil {
+			return r.pullRequestSyncFailed(ctx, pr, err)
+		}
+		if skipped {
+			return ctrl.Result{RequeueAfter: resyncAfter(r.ResyncPeriod)}, nil
+		}
+		base := pr.DeepCopy()
+		pr.Status.Number = created.Number
+		pr.Status.URL = created.HTMLURL
+		if err := r.Status().Patch(ctx, pr, client.MergeFrom(base)); err != nil {
+			log.Error(err, "Failed to record the pull request number")
+			return ctrl.Result{}, err
+		}
+	}
+
+	if err := r.syncPullRequest(ctx, pr, repository); err != nil {
+		return r.pullRequestSyncFailed(ctx, pr, err)
+	}
+	if err := r.recordPullRequestSynced(ctx, pr, nil); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: resyncAfter(r.ResyncPeriod)}, nil
+}
+
+// resolvePullRequestRepository returns the GitHub repository the pull request is opened in, pull requests are
+// not supported for the other providers
+func (r *GithubPullRequestReconciler) resolvePullRequestRepository(ctx context.Context, pr *trainingv1alpha1.GithubPullRequest) (githubRepository, error) {
+	ref := pr.Spec.RepositoryRef
+	if ref == nil {
+		return githubRepository{URL: gitHubAPIURL(pr.Spec.Repo, r.GitHubAPIURL), Provider: providerGitHub, Token: os.Getenv(tokenEnvVar)}, nil
+	}
+
+	spec, secretNamespace, err := getRepositorySpec(ctx, r.Client, ref, pr.Namespace)
+	if err != nil {
+		return githubRepository{}, err
+	}
+	if provider := repositoryProvider(spec); provider != providerGitHub {
+		return githubRepository{}, fmt.Errorf("pull requests are only supported on GitHub, repository %s uses %s", ref.Name, provider)
+	}
+	url, err := repositoryURL(spec, providerGitHub, r.GitHubAPIURL)
+	if err != nil {
+		return githubRepository{}, fmt.Errorf("repository %s: %w", ref.Name, err)
+	}
+	token, err := repositoryToken(ctx, r.Client, spec, providerGitHub, secretNamespace)
+	if err != nil {
+		return githubRepository{}, err
+	}
+	return githubRepository{
+		URL:      url,
+		Provider: providerGitHub,
+		Token:    token,
+		Labels:   spec.DefaultLabels,
+	}, nil
+}
+
+// checkPullRequestPolicy records whether the GithubIssuePolicies selecting the namespace forbid the repository
+// of the GithubPullRequest in the Forbidden condition, as checkGithubIssuePolicy, and returns whether it is forbidden
+func (r *GithubPullRequestReconciler) checkPullRequestPolicy(ctx context.Context, pr *trainingv1alpha1.GithubPullRequest) (bool, error) {
+	name, err := policy.GithubPullRequestRepositoryName(ctx, r.Client, pr)
+	if err != nil {
+		return false, err
+	}
+	condition, allowed, err := repositoryPolicyCondition(ctx, r.Client, pr.Namespace, name, pr.Generation)
+	if err != nil {
+		return false, err
+	}
+
+	if allowed && meta.FindStatusCondition(pr.Status.Conditions, conditionForbidden) == nil {
+		return false, nil
+	}
+	base := pr.DeepCopy()
+	if meta.SetStatusCondition(&pr.Status.Conditions, condition) {
+		if err := r.Status().Patch(ctx, pr, client.MergeFrom(base)); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to update GithubPullRequest status")
+			return false, err
+		}
+	}
+	return !allowed, nil
+}
+
+// openPullRequest opens the pull request, or adopts the open pull request of the same head and base it opened
+// before, e.g. when the number could not be recorded after the pull request was opened. A pull request opened by
+// anyone else is never adopted, as it would be closed when the GithubPullRequest is deleted.
+func (r *GithubPullRequestReconciler) openPullRequest(ctx context.Context, pr *trainingv1alpha1.GithubPullRequest,
+	repository githubRepository) (gitHubPullRequest, bool, error) {
+	head := pr.Spec.Head
+	if !strings.Contains(head, ":") {
+		if fullName, err := policy.RepositoryFullName(repository.URL); err == nil {
+			owner, _, _ := strings.Cut(fullName, "/")
+			head = owner + ":" + head
+		}
+	}
+	query := url.Values{"state": {"open"}, "head": {head}, "base": {pr.Spec.Base}}
+	var existing []gitHubPullRequest
+	if _, err := r.gitHubRequest(ctx, http.MethodGet, repository.URL+"/pulls?"+query.Encode(), nil, http.StatusOK, repository.Token, &existing); err != nil {
+		return gitHubPullRequest{}, false, err
+	}
+	for _, candidate := range existing {
+		if strings.Contains(candidate.Body, pullRequestMarker(pr)) {
+			log.FromContext(ctx).Info("Adopting the open pull request", "number", candidate.Number)
+			return candidate, false, nil
+		}
+	}
+
+	log.FromContext(ctx).Info("Opening pull request", "head", pr.Spec.Head, "base", pr.Spec.Base)
+	payload := map[string]interface{}{
+		"title": pr.Spec.Title,
+		"body":  pullRequestBody(pr),
+		"head":  pr.Spec.Head,
+		"base":  pr.Spec.Base,
+		"draft": pr.Spec.Draft,
+	}
+	var created gitHubPullRequest
+	skipped, err := r.gitHubRequest(ctx, http.MethodPost, repository.URL+"/pulls", payload, http.StatusCreated, repository.Token, &created)
+	return created, skipped, err
+}
+
+// syncPullRequest corrects the title and body, adds missing labels, requests missing reviews of an open
+// pull request, and records the state, mergeability, checks and reviews in the status
+func (r *GithubPullRequestReconciler) syncPullRequest(ctx context.Context, pr *trainingv1alpha1.GithubPullRequest, repository githubRepository) error {
+	pullURL := repository.URL + "/pulls/" + strconv.FormatInt(pr.Status.Number, 10)
+	var current gitHubPullRequest
+	if _, err := r.gitHubRequest(ctx, http.MethodGet, pullURL, nil, http.StatusOK, repository.Token, &current); err != nil {
+		return err
+	}
+	var reviews []gitHubReview
+	if _, err := r.gitHubRequest(ctx, http.MethodGet, pullURL+"/reviews?per_page=100", nil, http.StatusOK, repository.Token, &reviews); err != nil {
+		return err
+	}
+
+	if current.State == "open" {
+		if current.Title != pr.Spec.Title || current.Body != pullRequestBody(pr) {
+			payload := map[string]string{"title": pr.Spec.Title, "body": pullRequestBody(pr)}
+			if _, err := r.gitHubRequest(ctx, http.MethodPatch, pullURL, payload, http.StatusOK, repository.Token, nil); err != nil {
+				return err
+			}
+		}
+
+		var currentLabels []string
+		for _, label := range current.Labels {
+			currentLabels = append(currentLabels, label.Name)
+		}
+		var missingLabels []string
+		for _, label := range append(append([]string(nil), repository.Labels...), pr.Spec.Labels...) {
+			if !containsLabel(currentLabels, label) && !containsLabel(missingLabels, label) {
+				missingLabels = append(missingLabels, label)
+			}
+		}
+		if len(missingLabels) > 0 {
+			labelsURL := repository.URL + "/issues/" + strconv.FormatInt(pr.Status.Number, 10) + "/labels"
+			if _, err := r.gitHubRequest(ctx, http.MethodPost, labelsURL, map[string][]string{"labels": missingLabels},
+				http.StatusOK, repository.Token, nil); err != nil {
+				return err
+			}
+		}
+
+		// Reviewers who already reviewed are no longer requested, they are not asked again
+		var requested []string
+		for _, reviewer := range current.RequestedReviewers {
+			requested = append(requested, reviewer.Login)
+		}
+		for _, review := range reviews {
+			requested = append(requested, review.User.Login)
+		}
+		var missingReviewers []string
+		for _, reviewer := range pr.Spec.Reviewers {
+			if !containsLabel(requested, reviewer) {
+				missingReviewers = append(missingReviewers, reviewer)
+			}
+		}
+		if len(missingReviewers) > 0 {
+			if _, err := r.gitHubRequest(ctx, http.MethodPost, pullURL+"/requested_reviewers", map[string][]string{"reviewers": missingReviewers},
+				http.StatusCreated, repository.Token, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	var checks gitHubCheckRuns
+	if current.Head.SHA != "" {
+		checksURL := repository.URL + "/commits/" + current.Head.SHA + "/check-runs?per_page=100"
+		if _, err := r.gitHubRequest(ctx, http.MethodGet, checksURL, nil, http.StatusOK, repository.Token, &checks); err != nil {
+			return err
+		}
+	}
+
+	base := pr.DeepCopy()
+	pr.Status.URL = current.HTMLURL
+	pr.Status.State = pullRequestState(current)
+	pr.Status.Mergeable = current.Mergeable
+	pr.Status.MergeableState = current.MergeableState
+	pr.Status.Checks = nil
+	for _, run := range checks.CheckRuns {
+		pr.Status.Checks = append(pr.Status.Checks, trainingv1alpha1.PullRequestCheck{Name: run.Name, Status: run.Status, Conclusion: run.Conclusion})
+	}
+	pr.Status.ChecksState = checksState(pr.Status.Checks)
+	pr.Status.Reviews = latestReviews(reviews)
+	pr.Status.ReviewDecision = reviewDecision(pr.Status.Reviews)
+	if equality.Semantic.DeepEqual(base.Status, pr.Status) {
+		return nil
+	}
+	if err := r.Status().Patch(ctx, pr, client.MergeFrom(base)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update GithubPullRequest status")
+		return err
+	}
+	return nil
+}
+
+// closePullRequest closes the pull request unless it was already closed or merged
+func (r *GithubPullRequestReconciler) closePullRequest(ctx context.Context, pr *trainingv1alpha1.GithubPullRequest, repository githubRepository) error {
+	pullURL := repository.URL + "/pulls/" + strconv.FormatInt(pr.Status.Number, 10)
+	var current gitHubPullRequest
+	if _, err := r.gitHubRequest(ctx, http.MethodGet, pullURL, nil, http.StatusOK, repository.Token, &current); err != nil {
+		return err
+	}
+	if current.State != "open" {
+		return nil
+	}
+	log.FromContext(ctx).Info("Closing pull request", "number", pr.Status.Number)
+	_, err := r.gitHubRequest(ctx, http.MethodPatch, pullURL, map[string]string{"state": "closed"}, http.StatusOK, repository.Token, nil)
+	return err
+}
+
+// gitHubRequest sends the request and decodes the response into out when it is set. Writes are skipped in
+// dry-run mode, in which case skipped is true.
+func (r *GithubPullRequestReconciler) gitHubRequest(ctx context.Context, method string, url string, payload interface{},
+	expected int, token string, out interface{}) (skipped bool, err error) {
+	if method != http.MethodGet && r.DryRun {
+		skipDryRunRequest(ctx, method, url, payload)
+		return true, nil
+	}
+
+	resp, body, err := doGitHubHTTPRequest(method, url, payload, token)
+	if err != nil {
+		return false, err
+	}
+	if resp.StatusCode != expected {
+		return false, newGitHubStatusError(resp)
+	}
+	if out != nil {
+		if err := json.Unmarshal(body, out); err != nil {
+			return false, fmt.Errorf("error unmarshaling JSON: %w", err)
+		}
+	}
+	return false, nil
+}
+
+// pullRequestMarker returns the hidden comment identifying the pull requests opened for the GithubPullRequest
+func pullRequestMarker(pr *trainingv1alpha1.GithubPullRequest) string {
+	return pullRequestMarkerPrefix + pr.Namespace + "/" + pr.Name + " -->"
+}
+
+// pullRequestBody returns spec.body followed by the marker of the GithubPullRequest
+func pullRequestBody(pr *trainingv1alpha1.GithubPullRequest) string {
+	return pr.Spec.Body + "\n\n" + pullRequestMarker(pr)
+}
+
+// reportDryRunRequests emits an event for every GitHub write skipped during the reconcile and records them in
+// status.dryRunRequests when they changed, as for GithubIssues
+func (r *GithubPullRequestReconciler) reportDryRunRequests(ctx context.Context, pr *trainingv1alpha1.GithubPullRequest) {
+	requests, ok := skippedDryRunRequests(ctx, r.Recorder, pr)
+	if !ok || equality.Semantic.DeepEqual(pr.Status.DryRunRequests, requests) {
+		return
+	}
+	base := pr.DeepCopy()
+	pr.Status.DryRunRequests = requests
+	if err := r.Status().Patch(ctx, pr, client.MergeFrom(base)); err != nil && !apiErrors.IsNotFound(err) {
+		log.FromContext(ctx).Error(err, "Failed to record the dry run requests in the GithubPullRequest status")
+	}
+}
+
+// pullRequestState returns the Open, Closed or Merged state of a pull request
+func pullRequestState(pr gitHubPullRequest) string {
+	switch {
+	case pr.Merged:
+		return pullRequestStateMerged
+	case pr.State == "closed":
+		return pullRequestStateClosed
+	default:
+		return pullRequestStateOpen
+	}
+}
+
+// checksState summarizes check runs, a failed run wins over a pending one
+func checksState(checks []trainingv1alpha1.PullRequestCheck) string {
+	if len(checks) == 0 {
+		return checksStateNone
+	}
+	state := checksStateSuccess
+	for _, check := range checks {
+		switch {
+		case check.Status != "completed":
+			state = checksStatePending
+		case check.Conclusion == "failure" || check.Conclusion == "timed_out" || check.Conclusion == "cancelled" ||
+			check.Conclusion == "action_required":
+			return checksStateFailure
+		}
+	}
+	return state
+}
+
+// latestReviews returns the latest review of every reviewer in the order they first reviewed. Comments do
+// not replace an approval or a change request, as on GitHub.
+func latestReviews(reviews []gitHubReview) []trainingv1alpha1.PullRequestReview {
+	var result []trainingv1alpha1.PullRequestReview
+	index := map[string]int{}
+	for _, review := range reviews {
+		if review.State == "PENDING" {
+			continue
+		}
+		i, found := index[review.User.Login]
+		if !found {
+			index[review.User.Login] = len(result)
+			result = append(result, trainingv1alpha1.PullRequestReview{Reviewer: review.User.Login, State: review.State})
+			continue
+		}
+		if review.State != "COMMENTED" || result[i].State == "COMMENTED" {
+			result[i].State = review.State
+		}
+	}
+	return result
+}
+
+// reviewDecision summarizes the latest reviews, a change request wins over approvals
+func reviewDecision(reviews []trainingv1alpha1.PullRequestReview) string {
+	decision := reviewDecisionReviewRequired
+	for _, review := range reviews {
+		switch review.State {
+		case "CHANGES_REQUESTED":
+			return reviewDecisionChangesRequested
+		case "APPROVED":
+			decision = reviewDecisionApproved
+		}
+	}
+	return decision
+}
+
+// patchPullRequestFinalizer adds or removes the finalizer of the controller, as patchGithubIssueFinalizer
+func (r *GithubPullRequestReconciler) patchPullRequestFinalizer(ctx context.Context, pr *trainingv1alpha1.GithubPullRequest, add bool) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		base := pr.DeepCopy()
+		changed := false
+		if add {
+			changed = controllerutil.AddFinalizer(pr, pullRequestFinalizerName)
+		} else {
+			changed = controllerutil.RemoveFinalizer(pr, pullRequestFinalizerName)
+		}
+		if !changed {
+			return nil
+		}
+
+		err := r.Patch(ctx, pr, client.MergeFromWithOptions(base, client.MergeFromWithOptimisticLock{}))
+		if apiErrors.IsConflict(err) {
+			if getErr := r.Get(ctx, client.ObjectKeyFromObject(pr), pr); getErr != nil {
+				return getErr
+			}
+		}
+		if !add && apiErrors.IsNotFound(err) {
+			// The GithubPullRequest is already gone
+			return nil
+		}
+		return err
+	})
+}
+
+// recordPullRequestSynced writes the Synced condition for the outcome of the GitHub requests of the reconcile
+func (r *GithubPullRequestReconciler) recordPullRequestSynced(ctx context.Context, pr *trainingv1alpha1.GithubPullRequest, syncErr error) error {
+	base := pr.DeepCopy()
+	condition := metav1.Condition{
+		Type:               conditionSynced,
+		Status:             metav1.ConditionTrue,
+		Reason:             "Synced",
+		Message:            "GitHub pull request was reconciled",
+		ObservedGeneration: pr.Generation,
+	}
+	if syncErr != nil {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = syncFailureReason(syncErr)
+		condition.Message = syncErr.Error()
+	}
+
+	changed := meta.SetStatusCondition(&pr.Status.Conditions, condition)
+	if syncErr == nil && pr.Status.ObservedGeneration != pr.Generation {
+		pr.Status.ObservedGeneration = pr.Generation
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := r.Status().Patch(ctx, pr, client.MergeFrom(base)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update GithubPullRequest status")
+		return err
+	}
+	return nil
+}
+
+// pullRequestSyncFailed records a failed GitHub request in the Synced condition and returns the error,
+// so the reconcile is retried with backoff
+func (r *GithubPullRequestReconciler) pullRequestSyncFailed(ctx context.Context, pr *trainingv1alpha1.GithubPullRequest, err error) (ctrl.Result, error) {
+	if recordErr := r.recordPullRequestSynced(ctx, pr, err); recordErr != nil && !apiErrors.IsNotFound(recordErr) {
+		return ctrl.Result{}, recordErr
+	}
+	return ctrl.Result{}, err
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GithubPullRequestReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		// Status and finalizer writes of the controller do not reconcile against GitHub again, as for GithubIssues
+		For(&trainingv1alpha1.GithubPullRequest{}, builder.WithPredicates(githubIssuePredicate())).
+		WithOptions(r.Options.controllerOptions()).
+		Complete(r)
+}
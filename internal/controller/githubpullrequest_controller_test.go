@@ -0,0 +1,304 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"os"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	"Shai1-Levi/githubissues-operator.git/internal/githubfake"
+)
+
+var _ = Describe("GithubPullRequest Controller", func() {
+	const (
+		resourceName = "test-pull-request"
+		fullName     = "owner/pulls"
+	)
+
+	ctx := context.Background()
+	typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	var reconciler *GithubPullRequestReconciler
+	reconcilePullRequest := func() {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+	}
+	pullRequest := func() githubfake.Issue {
+		issue, found := gitHubServer.Issue(fullName, 1)
+		Expect(found).To(BeTrue())
+		Expect(issue.PullRequest).NotTo(BeNil())
+		return issue
+	}
+
+	BeforeEach(func() {
+		gitHubServer.Reset()
+		gitHubServer.SetToken("token")
+		gitHubServer.SetRateLimit(githubfake.DefaultRateLimit)
+		Expect(os.Setenv(tokenEnvVar, "token")).To(Succeed())
+		reconciler = &GithubPullRequestReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), GitHubAPIURL: gitHubServer.URL}
+
+		resource := &trainingv1alpha1.GithubPullRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec: trainingv1alpha1.GithubPullRequestSpec{
+				Repo:      "https://api.github.com/repos/" + fullName,
+				Head:      "feature",
+				Base:      "main",
+				Title:     "title",
+				Body:      "body",
+				Labels:    []string{"enhancement"},
+				Reviewers: []string{"alice", "bob"},
+				Draft:     true,
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		Expect(os.Unsetenv(tokenEnvVar)).To(Succeed())
+
+		resource := &trainingv1alpha1.GithubPullRequest{}
+		err := k8sClient.Get(ctx, typeNamespacedName, resource)
+		if errors.IsNotFound(err) {
+			return
+		}
+		Expect(err).NotTo(HaveOccurred())
+		resource.Finalizers = nil
+		Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+		Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, resource))).To(Succeed())
+	})
+
+	It("should open the pull request with its labels and reviewers", func() {
+		reconcilePullRequest()
+		reconcilePullRequest()
+
+		issue := pullRequest()
+		Expect(issue.Title).To(Equal("title"))
+		Expect(issue.Body).To(Equal("body\n\n<!-- github-pull-request.kubebuilder.io/owner: default/test-pull-request -->"))
+		Expect(issue.Labels).To(ConsistOf("enhancement"))
+		Expect(issue.PullRequest.Head).To(Equal("feature"))
+		Expect(issue.PullRequest.Base).To(Equal("main"))
+		Expect(issue.PullRequest.Draft).To(BeTrue())
+		Expect(issue.PullRequest.RequestedReviewers).To(ConsistOf("alice", "bob"))
+
+		pr := &trainingv1alpha1.GithubPullRequest{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, pr)).To(Succeed())
+		Expect(pr.Status.Number).To(Equal(int64(1)))
+		Expect(pr.Status.URL).To(HaveSuffix("/" + fullName + "/pull/1"))
+		Expect(pr.Status.State).To(Equal(pullRequestStateOpen))
+		Expect(pr.Status.Mergeable).To(HaveValue(BeTrue()))
+		Expect(pr.Status.MergeableState).To(Equal("clean"))
+		Expect(pr.Status.ChecksState).To(Equal(checksStateNone))
+		Expect(pr.Status.ReviewDecision).To(Equal(reviewDecisionReviewRequired))
+		Expect(meta.IsStatusConditionTrue(pr.Status.Conditions, conditionSynced)).To(BeTrue())
+
+		By("not opening a second pull request")
+		reconcilePullRequest()
+		Expect(gitHubServer.Issues(fullName)).To(HaveLen(1))
+	})
+
+	It("should adopt the open pull request it opened for the same head and base", func() {
+		gitHubServer.CreatePullRequest(fullName,
+			githubfake.Issue{Title: "title", Body: "body\n\n<!-- github-pull-request.kubebuilder.io/owner: default/test-pull-request -->"},
+			githubfake.PullRequest{Head: "owner:feature", Base: "main"})
+
+		reconcilePullRequest()
+		reconcilePullRequest()
+
+		Expect(gitHubServer.Issues(fullName)).To(HaveLen(1))
+		pr := &trainingv1alpha1.GithubPullRequest{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, pr)).To(Succeed())
+		Expect(pr.Status.Number).To(Equal(int64(1)))
+	})
+
+	It("should neither adopt nor close a pull request opened by someone else", func() {
+		gitHubServer.CreatePullRequest(fullName, githubfake.Issue{Title: "title", Body: "body"},
+			githubfake.PullRequest{Head: "owner:feature", Base: "main"})
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).To(HaveOccurred())
+
+		pr := &trainingv1alpha1.GithubPullRequest{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, pr)).To(Succeed())
+		Expect(pr.Status.Number).To(BeZero())
+		Expect(meta.IsStatusConditionFalse(pr.Status.Conditions, conditionSynced)).To(BeTrue())
+
+		Expect(k8sClient.Delete(ctx, pr)).To(Succeed())
+		reconcilePullRequest()
+		Expect(pullRequest().State).To(Equal("open"))
+	})
+
+	It("should report the checks and reviews of the pull request", func() {
+		reconcilePullRequest()
+		reconcilePullRequest()
+
+		By("reporting running and failed checks")
+		Expect(gitHubServer.UpdateIssue(fullName, 1, func(issue *githubfake.Issue) {
+			issue.PullRequest.CheckRuns = []githubfake.CheckRun{
+				{Name: "lint", Status: "completed", Conclusion: "success"},
+				{Name: "test", Status: "in_progress"},
+			}
+			issue.PullRequest.Reviews = []githubfake.Review{{Author: "alice", State: "APPROVED"}}
+		})).To(BeTrue())
+		reconcilePullRequest()
+
+		pr := &trainingv1alpha1.GithubPullRequest{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, pr)).To(Succeed())
+		Expect(pr.Status.ChecksState).To(Equal(checksStatePending))
+		Expect(pr.Status.Checks).To(HaveLen(2))
+		Expect(pr.Status.ReviewDecision).To(Equal(reviewDecisionApproved))
+
+		By("letting a failure and a change request win")
+		Expect(gitHubServer.UpdateIssue(fullName, 1, func(issue *githubfake.Issue) {
+			issue.PullRequest.CheckRuns[1] = githubfake.CheckRun{Name: "test", Status: "completed", Conclusion: "failure"}
+			issue.PullRequest.Reviews = append(issue.PullRequest.Reviews,
+				githubfake.Review{Author: "bob", State: "CHANGES_REQUESTED"},
+				githubfake.Review{Author: "alice", State: "COMMENTED"})
+			mergeable := false
+			issue.PullRequest.Mergeable = &mergeable
+			issue.PullRequest.MergeableState = "blocked"
+		})).To(BeTrue())
+		reconcilePullRequest()
+
+		Expect(k8sClient.Get(ctx, typeNamespacedName, pr)).To(Succeed())
+		Expect(pr.Status.ChecksState).To(Equal(checksStateFailure))
+		Expect(pr.Status.ReviewDecision).To(Equal(reviewDecisionChangesRequested))
+		Expect(pr.Status.Reviews).To(ConsistOf(
+			trainingv1alpha1.PullRequestReview{Reviewer: "alice", State: "APPROVED"},
+			trainingv1alpha1.PullRequestReview{Reviewer: "bob", State: "CHANGES_REQUESTED"},
+		))
+		Expect(pr.Status.Mergeable).To(HaveValue(BeFalse()))
+		Expect(pr.Status.MergeableState).To(Equal("blocked"))
+
+		By("reporting a merged pull request")
+		Expect(gitHubServer.UpdateIssue(fullName, 1, func(issue *githubfake.Issue) {
+			issue.State = "closed"
+			issue.PullRequest.Merged = true
+		})).To(BeTrue())
+		reconcilePullRequest()
+
+		Expect(k8sClient.Get(ctx, typeNamespacedName, pr)).To(Succeed())
+		Expect(pr.Status.State).To(Equal(pullRequestStateMerged))
+	})
+
+	It("should correct the title and body", func() {
+		reconcilePullRequest()
+		reconcilePullRequest()
+
+		Expect(gitHubServer.UpdateIssue(fullName, 1, func(issue *githubfake.Issue) {
+			issue.Title = "edited"
+			issue.Body = "edited"
+		})).To(BeTrue())
+		reconcilePullRequest()
+
+		issue := pullRequest()
+		Expect(issue.Title).To(Equal("title"))
+		Expect(issue.Body).To(HavePrefix("body\n\n"))
+	})
+
+	It("should close the pull request when the GithubPullRequest is deleted", func() {
+		reconcilePullRequest()
+		reconcilePullRequest()
+		Expect(pullRequest().State).To(Equal("open"))
+
+		pr := &trainingv1alpha1.GithubPullRequest{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, pr)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, pr)).To(Succeed())
+		reconcilePullRequest()
+
+		Expect(pullRequest().State).To(Equal("closed"))
+		err := k8sClient.Get(ctx, typeNamespacedName, pr)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should not open a pull request in a repository forbidden by a GithubIssuePolicy", func() {
+		ns := &corev1.Namespace{}
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, ns); errors.IsNotFound(err) {
+			Expect(k8sClient.Create(ctx, &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}})).To(Succeed())
+		}
+		githubIssuePolicy := &trainingv1alpha1.GithubIssuePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-pull-requests"},
+			Spec:       trainingv1alpha1.GithubIssuePolicySpec{AllowedRepositories: []string{"owner/other"}},
+		}
+		Expect(k8sClient.Create(ctx, githubIssuePolicy)).To(Succeed())
+		defer func() { Expect(k8sClient.Delete(ctx, githubIssuePolicy)).To(Succeed()) }()
+
+		reconcilePullRequest()
+		reconcilePullRequest()
+		Expect(gitHubServer.Issues(fullName)).To(BeEmpty())
+
+		pr := &trainingv1alpha1.GithubPullRequest{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, pr)).To(Succeed())
+		condition := meta.FindStatusCondition(pr.Status.Conditions, conditionForbidden)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("RepositoryNotAllowed"))
+	})
+
+	It("should remove the finalizer of a GithubPullRequest deleted after its repository", func() {
+		pr := &trainingv1alpha1.GithubPullRequest{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, pr)).To(Succeed())
+		pr.Spec.RepositoryRef = &trainingv1alpha1.RepositoryReference{Name: "deleted"}
+		pr.Finalizers = []string{pullRequestFinalizerName}
+		Expect(k8sClient.Update(ctx, pr)).To(Succeed())
+		pr.Status.Number = 1
+		Expect(k8sClient.Status().Update(ctx, pr)).To(Succeed())
+
+		Expect(k8sClient.Delete(ctx, pr)).To(Succeed())
+		reconcilePullRequest()
+		Expect(errors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, pr))).To(BeTrue())
+	})
+
+	It("should report a pull request request rejected by the rate limit", func() {
+		gitHubServer.SetRateLimit(0)
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+		Expect(err).To(HaveOccurred())
+
+		pr := &trainingv1alpha1.GithubPullRequest{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, pr)).To(Succeed())
+		condition := meta.FindStatusCondition(pr.Status.Conditions, conditionSynced)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Reason).To(Equal("RateLimited"))
+	})
+
+	It("should report the pull request it would open in dry-run mode", func() {
+		recorder := record.NewFakeRecorder(10)
+		reconciler.DryRun = true
+		reconciler.Recorder = recorder
+		reconcilePullRequest()
+		reconcilePullRequest()
+
+		Expect(gitHubServer.Issues(fullName)).To(BeEmpty())
+		pr := &trainingv1alpha1.GithubPullRequest{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, pr)).To(Succeed())
+		Expect(pr.Status.DryRunRequests).To(HaveLen(1))
+		Expect(pr.Status.DryRunRequests[0].Method).To(Equal("POST"))
+		Expect(pr.Status.DryRunRequests[0].URL).To(Equal(gitHubServer.RepositoryURL(fullName) + "/pulls"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("DryRun Skipped POST")))
+	})
+})
@@ -0,0 +1,391 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	"Shai1-Levi/githubissues-operator.git/internal/policy"
+)
+
+const (
+	// tokenEnvVar is the environment variable holding the global GitHub token
+	tokenEnvVar = "SECRET_Token"
+
+	defaultGitHubAPIBaseURL = "https://api.github.com"
+	defaultCredentialsKey   = "token"
+
+	kindGithubRepository        = "GithubRepository"
+	kindClusterGithubRepository = "ClusterGithubRepository"
+
+	// conditionRepositoryReachable reports whether the repository can be accessed with its credentials
+	conditionRepositoryReachable = "Reachable"
+
+	// repositoryCheckPeriod is how often repository access is validated again
+	repositoryCheckPeriod = 5 * time.Minute
+)
+
+// githubRepository is the repository a GithubIssue is filed in
+type githubRepository struct {
+	// URL is the API URL of the repository, e.g. https://api.github.com/repos/owner/name
+	URL string
+	// Provider is the issue tracker hosting the repository
+	Provider  string
+	Token     string
+	Labels    []string
+	Assignees []string
+	// Jira configures the issues filed with the Jira provider
+	Jira *trainingv1alpha1.JiraSpec
+}
+
+// GithubRepositoryReconciler reconciles a GithubRepository object
+type GithubRepositoryReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// GitHubAPIURL replaces https://api.github.com in repository URLs when set
+	GitHubAPIURL string
+
+	// Options configures the workers and rate limiter of the controller
+	Options ControllerOptions
+}
+
+// ClusterGithubRepositoryReconciler reconciles a ClusterGithubRepository object
+type ClusterGithubRepositoryReconciler struct {
+	client.Client
+	Scheme *runtime.Scheme
+
+	// GitHubAPIURL replaces https://api.github.com in repository URLs when set
+	GitHubAPIURL string
+
+	// Options configures the workers and rate limiter of the controller
+	Options ControllerOptions
+}
+
+// +kubebuilder:rbac:groups=training.redhat.com,resources=githubrepositories,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=training.redhat.com,resources=githubrepositories/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=training.redhat.com,resources=githubrepositories/finalizers,verbs=update
+// +kubebuilder:rbac:groups=training.redhat.com,resources=clustergithubrepositories,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=training.redhat.com,resources=clustergithubrepositories/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=training.redhat.com,resources=clustergithubrepositories/finalizers,verbs=update
+// +kubebuilder:rbac:groups=core,resources=secrets,verbs=get;list;watch
+
+// Reconcile validates that the GithubRepository can be reached and reports it in the Reachable condition.
+func (r *GithubRepositoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	repo := &trainingv1alpha1.GithubRepository{}
+	if err := r.Get(ctx, req.NamespacedName, repo); err != nil {
+		if apiErrors.IsNotFound(err) {
+			log.Info("GithubRepository CR was not found", "name", req.Name, "namespace", req.Namespace)
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get GithubRepository CR")
+		return ctrl.Result{}, err
+	}
+
+	if err := updateRepositoryStatus(ctx, r.Client, repo, &repo.Spec, &repo.Status, repo.Namespace, r.GitHubAPIURL); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: repositoryCheckPeriod}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *GithubRepositoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&trainingv1alpha1.GithubRepository{}).
+		WithOptions(r.Options.controllerOptions()).
+		Complete(r)
+}
+
+// Reconcile validates that the ClusterGithubRepository can be reached and reports it in the Reachable condition.
+func (r *ClusterGithubRepositoryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	repo := &trainingv1alpha1.ClusterGithubRepository{}
+	if err := r.Get(ctx, req.NamespacedName, repo); err != nil {
+		if apiErrors.IsNotFound(err) {
+			log.Info("ClusterGithubRepository CR was not found", "name", req.Name)
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "Failed to get ClusterGithubRepository CR")
+		return ctrl.Result{}, err
+	}
+
+	if err := updateRepositoryStatus(ctx, r.Client, repo, &repo.Spec, &repo.Status, "", r.GitHubAPIURL); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: repositoryCheckPeriod}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *ClusterGithubRepositoryReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&trainingv1alpha1.ClusterGithubRepository{}).
+		WithOptions(r.Options.controllerOptions()).
+		Complete(r)
+}
+
+// updateRepositoryStatus checks access to the repository and writes the Reachable condition when it changed.
+// secretNamespace is the namespace of the credentials Secret, empty for cluster scoped repositories.
+func updateRepositoryStatus(ctx context.Context, c client.Client, obj client.Object, spec *trainingv1alpha1.GithubRepositorySpec,
+	status *trainingv1alpha1.GithubRepositoryStatus, secretNamespace string, apiURL string) error {
+	condition := checkRepositoryAccess(ctx, c, spec, secretNamespace, apiURL)
+	condition.ObservedGeneration = obj.GetGeneration()
+
+	changed := meta.SetStatusCondition(&status.Conditions, condition)
+	// An invalid repository has no URL, checkRepositoryAccess reports why
+	url, _ := repositoryURL(spec, repositoryProvider(spec), apiURL)
+	if status.URL != url {
+		status.URL = url
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+
+	if err := c.Status().Update(ctx, obj); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to update repository status")
+		return err
+	}
+	return nil
+}
+
+// checkRepositoryAccess reads the repository with its credentials and returns the resulting Reachable condition
+func checkRepositoryAccess(ctx context.Context, c client.Client, spec *trainingv1alpha1.GithubRepositorySpec, secretNamespace string, apiURL string) metav1.Condition {
+	condition := metav1.Condition{
+		Type:    conditionRepositoryReachable,
+		Status:  metav1.ConditionFalse,
+		Reason:  "Reachable",
+		Message: "Repository is accessible",
+	}
+
+	provider := repositoryProvider(spec)
+	url, err := repositoryURL(spec, provider, apiURL)
+	if err != nil {
+		condition.Reason = "InvalidRepository"
+		condition.Message = err.Error()
+		return condition
+	}
+
+	// Repositories of a namespace are subject to its GithubIssuePolicies, their host is not contacted when forbidden
+	if secretNamespace != "" {
+		name, err := policy.RepositorySpecName(spec, provider)
+		if err != nil {
+			condition.Reason = "InvalidRepository"
+			condition.Message = err.Error()
+			return condition
+		}
+		allowed, message, err := policy.CheckRepository(ctx, c, secretNamespace, name)
+		if err != nil {
+			condition.Reason = "PolicyUnavailable"
+			condition.Message = err.Error()
+			return condition
+		}
+		if !allowed {
+			condition.Reason = "RepositoryNotAllowed"
+			condition.Message = message
+			return condition
+		}
+	}
+
+	token, err := repositoryToken(ctx, c, spec, provider, secretNamespace)
+	if err != nil {
+		condition.Reason = "CredentialsUnavailable"
+		condition.Message = err.Error()
+		return condition
+	}
+
+	var status int
+	if tracker := newIssueTracker(provider, spec.Jira); tracker != nil {
+		// Other providers are checked by reading the project
+		status = http.StatusOK
+		var statusErr *gitHubStatusError
+		_, err = doTrackerRequest(ctx, tracker, trackerRequest{Method: http.MethodGet, URL: url, Expected: http.StatusOK}, token)
+		if errors.As(err, &statusErr) {
+			status, err = statusErr.StatusCode, nil
+		}
+	} else {
+		status, _, err = doGitHubRequest("GET", url, nil, token)
+	}
+	switch {
+	case err != nil:
+		condition.Reason = "RequestFailed"
+		condition.Message = err.Error()
+	case status == http.StatusOK:
+		condition.Status = metav1.ConditionTrue
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		condition.Reason = "Unauthorized"
+		condition.Message = fmt.Sprintf("%s API returned status: %d", provider, status)
+	case status == http.StatusNotFound:
+		condition.Reason = "NotFound"
+		condition.Message = "Repository was not found or the credentials cannot see it"
+	default:
+		condition.Reason = "RequestFailed"
+		condition.Message = fmt.Sprintf("%s API returned status: %d", provider, status)
+	}
+	return condition
+}
+
+// repositoryProvider returns spec.provider, defaulting to GitHub
+func repositoryProvider(spec *trainingv1alpha1.GithubRepositorySpec) string {
+	if spec.Provider == "" {
+		return providerGitHub
+	}
+	return spec.Provider
+}
+
+// repositoryURL returns the API URL of the repository on provider. Repositories without spec.apiBaseURL use the
+// default of their provider, see policy.RepositoryAPIBaseURL.
+func repositoryURL(spec *trainingv1alpha1.GithubRepositorySpec, provider string, apiURL string) (string, error) {
+	baseURL, err := policy.RepositoryAPIBaseURL(spec, provider)
+	if err != nil {
+		return "", err
+	}
+	switch provider {
+	case providerGitLab:
+		return gitLabProjectURL(baseURL, spec.Owner, spec.Name), nil
+	case providerJira:
+		return jiraProjectURL(baseURL, spec.Name), nil
+	case providerGitea:
+		return giteaRepositoryURL(baseURL, spec.Owner, spec.Name), nil
+	}
+	return gitHubAPIURL(strings.TrimSuffix(baseURL, "/")+"/repos/"+spec.Owner+"/"+spec.Name, apiURL), nil
+}
+
+// gitHubAPIURL moves a URL of the public GitHub API to apiURL when it is set, e.g. to point the controllers
+// at a fake server. URLs of other GitHub servers are kept.
+func gitHubAPIURL(url string, apiURL string) string {
+	if apiURL == "" || !strings.HasPrefix(url, defaultGitHubAPIBaseURL) {
+		return url
+	}
+	return strings.TrimSuffix(apiURL, "/") + strings.TrimPrefix(url, defaultGitHubAPIBaseURL)
+}
+
+// repositoryToken reads the token from the repository credentials Secret, or from the global token when a
+// repository of the public GitHub API has no credentials
+func repositoryToken(ctx context.Context, c client.Client, spec *trainingv1alpha1.GithubRepositorySpec, provider string, secretNamespace string) (string, error) {
+	ref := spec.CredentialsRef
+	if ref == nil {
+		// The global token must not be sent to a host chosen by the author of the repository
+		if provider != providerGitHub || (spec.APIBaseURL != "" && strings.TrimSuffix(spec.APIBaseURL, "/") != defaultGitHubAPIBaseURL) {
+			return "", fmt.Errorf("credentialsRef must be set for repositories outside of %s", defaultGitHubAPIBaseURL)
+		}
+		return os.Getenv(tokenEnvVar), nil
+	}
+
+	namespace := secretNamespace
+	if namespace == "" {
+		namespace = ref.Namespace
+	}
+	if namespace == "" {
+		return "", fmt.Errorf("credentialsRef.namespace must be set for cluster scoped repositories")
+	}
+	key := ref.Key
+	if key == "" {
+		key = defaultCredentialsKey
+	}
+
+	secret := &corev1.Secret{}
+	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, secret); err != nil {
+		return "", fmt.Errorf("failed to get credentials Secret %s/%s: %w", namespace, ref.Name, err)
+	}
+	token, ok := secret.Data[key]
+	if !ok || len(token) == 0 {
+		return "", fmt.Errorf("credentials Secret %s/%s has no %q key", namespace, ref.Name, key)
+	}
+	return strings.TrimSpace(string(token)), nil
+}
+
+// getRepositorySpec returns the spec of the repository referenced from namespace, and the namespace of its
+// credentials Secret, which is empty for cluster scoped repositories
+func getRepositorySpec(ctx context.Context, c client.Client, ref *trainingv1alpha1.RepositoryReference,
+	namespace string) (*trainingv1alpha1.GithubRepositorySpec, string, error) {
+	if ref.Kind == kindClusterGithubRepository {
+		repo := &trainingv1alpha1.ClusterGithubRepository{}
+		if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, repo); err != nil {
+			return nil, "", fmt.Errorf("failed to get ClusterGithubRepository %s: %w", ref.Name, err)
+		}
+		return &repo.Spec, "", nil
+	}
+
+	repo := &trainingv1alpha1.GithubRepository{}
+	if err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, repo); err != nil {
+		return nil, "", fmt.Errorf("failed to get GithubRepository %s: %w", ref.Name, err)
+	}
+	return &repo.Spec, namespace, nil
+}
+
+// resolveGithubRepository returns the repository the GithubIssue is filed in, either from spec.repositoryRef
+// or from spec.repo with the global token
+func (r *GithubIssueReconciler) resolveGithubRepository(ctx context.Context, ghi *trainingv1alpha1.GithubIssue) (githubRepository, error) {
+	ref := ghi.Spec.RepositoryRef
+	if ref == nil {
+		provider := githubIssueProvider(ghi)
+		url := ghi.Spec.Repo
+		if provider == providerGitHub {
+			url = gitHubAPIURL(url, r.GitHubAPIURL)
+		}
+		return githubRepository{URL: url, Provider: provider, Token: os.Getenv(providerTokenEnvVar(provider))}, nil
+	}
+
+	spec, secretNamespace, err := getRepositorySpec(ctx, r.Client, ref, ghi.Namespace)
+	if err != nil {
+		return githubRepository{}, err
+	}
+
+	// The provider of the repository wins over the one of the GithubIssue
+	provider := githubIssueProvider(ghi)
+	if spec.Provider != "" {
+		provider = spec.Provider
+	}
+
+	url, err := repositoryURL(spec, provider, r.GitHubAPIURL)
+	if err != nil {
+		return githubRepository{}, fmt.Errorf("repository %s: %w", ref.Name, err)
+	}
+	token, err := repositoryToken(ctx, r.Client, spec, provider, secretNamespace)
+	if err != nil {
+		return githubRepository{}, err
+	}
+
+	return githubRepository{
+		URL:       url,
+		Provider:  provider,
+		Token:     token,
+		Labels:    spec.DefaultLabels,
+		Assignees: spec.DefaultAssignees,
+		Jira:      spec.Jira,
+	}, nil
+}
@@ -0,0 +1,241 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+var _ = Describe("GithubRepository Controller", func() {
+	Context("When reconciling a resource", func() {
+		const resourceName = "test-repository"
+
+		ctx := context.Background()
+		typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+		var (
+			server   *httptest.Server
+			secret   *corev1.Secret
+			requests atomic.Int32
+		)
+
+		BeforeEach(func() {
+			requests.Store(0)
+			server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				requests.Add(1)
+				if req.Header.Get("Authorization") != "token repo-token" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+				if req.URL.Path != "/repos/owner/repo" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write([]byte(`{"full_name": "owner/repo"}`))
+			}))
+
+			secret = &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "repo-credentials", Namespace: "default"},
+				Data:       map[string][]byte{"token": []byte("repo-token\n")},
+			}
+			Expect(k8sClient.Create(ctx, secret)).To(Succeed())
+
+			repo := &trainingv1alpha1.GithubRepository{
+				ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+				Spec: trainingv1alpha1.GithubRepositorySpec{
+					Owner:          "owner",
+					Name:           "repo",
+					APIBaseURL:     server.URL,
+					CredentialsRef: &trainingv1alpha1.SecretKeyReference{Name: secret.Name, Key: "token"},
+					DefaultLabels:  []string{"operator"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, repo)).To(Succeed())
+		})
+
+		AfterEach(func() {
+			server.Close()
+			repo := &trainingv1alpha1.GithubRepository{}
+			Expect(client.IgnoreNotFound(k8sClient.Get(ctx, typeNamespacedName, repo))).To(Succeed())
+			Expect(client.IgnoreNotFound(k8sClient.Delete(ctx, repo))).To(Succeed())
+			Expect(k8sClient.Delete(ctx, secret)).To(Succeed())
+		})
+
+		It("should report the repository as reachable", func() {
+			controllerReconciler := &GithubRepositoryReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			repo := &trainingv1alpha1.GithubRepository{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, repo)).To(Succeed())
+			Expect(meta.IsStatusConditionTrue(repo.Status.Conditions, conditionRepositoryReachable)).To(BeTrue())
+			Expect(repo.Status.URL).To(Equal(server.URL + "/repos/owner/repo"))
+		})
+
+		It("should report a repository that cannot be found", func() {
+			repo := &trainingv1alpha1.GithubRepository{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, repo)).To(Succeed())
+			repo.Spec.Name = "missing"
+			Expect(k8sClient.Update(ctx, repo)).To(Succeed())
+
+			controllerReconciler := &GithubRepositoryReconciler{
+				Client: k8sClient,
+				Scheme: k8sClient.Scheme(),
+			}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, repo)).To(Succeed())
+			condition := meta.FindStatusCondition(repo.Status.Conditions, conditionRepositoryReachable)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("NotFound"))
+		})
+
+		It("should not send the global token to another host", func() {
+			repo := &trainingv1alpha1.GithubRepository{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, repo)).To(Succeed())
+			repo.Spec.CredentialsRef = nil
+			Expect(k8sClient.Update(ctx, repo)).To(Succeed())
+
+			controllerReconciler := &GithubRepositoryReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, repo)).To(Succeed())
+			condition := meta.FindStatusCondition(repo.Status.Conditions, conditionRepositoryReachable)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("CredentialsUnavailable"))
+			Expect(condition.Message).To(ContainSubstring("credentialsRef must be set"))
+			Expect(requests.Load()).To(BeZero())
+		})
+
+		It("should require apiBaseURL for providers without a public instance", func() {
+			repo := &trainingv1alpha1.GithubRepository{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, repo)).To(Succeed())
+			repo.Spec.Provider = "Gitea"
+			repo.Spec.APIBaseURL = ""
+			Expect(k8sClient.Update(ctx, repo)).To(Succeed())
+
+			controllerReconciler := &GithubRepositoryReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(k8sClient.Get(ctx, typeNamespacedName, repo)).To(Succeed())
+			condition := meta.FindStatusCondition(repo.Status.Conditions, conditionRepositoryReachable)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Reason).To(Equal("InvalidRepository"))
+			Expect(condition.Message).To(ContainSubstring("apiBaseURL must be set for Gitea repositories"))
+			Expect(repo.Status.URL).To(BeEmpty())
+			Expect(requests.Load()).To(BeZero())
+		})
+
+		It("should not contact a repository forbidden by a GithubIssuePolicy", func() {
+			ns := &corev1.Namespace{}
+			if err := k8sClient.Get(ctx, types.NamespacedName{Name: "default"}, ns); apiErrors.IsNotFound(err) {
+				ns = &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "default"}}
+				Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+			} else {
+				Expect(err).NotTo(HaveOccurred())
+			}
+			githubIssuePolicy := &trainingv1alpha1.GithubIssuePolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-repository-policy"},
+				Spec: trainingv1alpha1.GithubIssuePolicySpec{
+					NamespaceSelector:   metav1.LabelSelector{},
+					AllowedRepositories: []string{"owner/other"},
+				},
+			}
+			Expect(k8sClient.Create(ctx, githubIssuePolicy)).To(Succeed())
+			DeferCleanup(k8sClient.Delete, ctx, githubIssuePolicy)
+
+			controllerReconciler := &GithubRepositoryReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+			_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+
+			repo := &trainingv1alpha1.GithubRepository{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, repo)).To(Succeed())
+			condition := meta.FindStatusCondition(repo.Status.Conditions, conditionRepositoryReachable)
+			Expect(condition).NotTo(BeNil())
+			Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+			Expect(condition.Reason).To(Equal("RepositoryNotAllowed"))
+			Expect(requests.Load()).To(BeZero())
+		})
+
+		It("should resolve a GithubIssue repositoryRef", func() {
+			ghi := &trainingv1alpha1.GithubIssue{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-repository-ref", Namespace: "default"},
+				Spec: trainingv1alpha1.GithubIssueSpec{
+					RepositoryRef: &trainingv1alpha1.RepositoryReference{Kind: kindGithubRepository, Name: resourceName},
+				},
+			}
+			reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+
+			repository, err := reconciler.resolveGithubRepository(ctx, ghi)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(repository.URL).To(Equal(server.URL + "/repos/owner/repo"))
+			Expect(repository.Token).To(Equal("repo-token"))
+			Expect(repository.Labels).To(ConsistOf("operator"))
+		})
+
+		It("should remove the finalizer of a GithubIssue deleted after its repository", func() {
+			issueName := types.NamespacedName{Name: "test-repository-gone", Namespace: "default"}
+			ghi := &trainingv1alpha1.GithubIssue{
+				ObjectMeta: metav1.ObjectMeta{Name: issueName.Name, Namespace: "default", Finalizers: []string{myFinalizerName}},
+				Spec: trainingv1alpha1.GithubIssueSpec{
+					RepositoryRef: &trainingv1alpha1.RepositoryReference{Kind: kindGithubRepository, Name: resourceName},
+					Title:         "title",
+				},
+			}
+			Expect(k8sClient.Create(ctx, ghi)).To(Succeed())
+			ghi.Status.IssueNumber = 1
+			Expect(k8sClient.Status().Update(ctx, ghi)).To(Succeed())
+
+			By("deleting the repository before the GithubIssue")
+			repo := &trainingv1alpha1.GithubRepository{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, repo)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, repo)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, ghi)).To(Succeed())
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme(), Recorder: recorder}
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: issueName})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(apiErrors.IsNotFound(k8sClient.Get(ctx, issueName, &trainingv1alpha1.GithubIssue{}))).To(BeTrue())
+			Expect(recorder.Events).To(Receive(ContainSubstring("IssueLeftOpen")))
+		})
+	})
+})
@@ -0,0 +1,113 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// gitLabTokenEnvVar is the environment variable holding the token of the GitLab provider
+const gitLabTokenEnvVar = "GITLAB_TOKEN"
+
+// gitLabTracker files issues with the GitLab REST API v4, issues are identified by their project IID
+type gitLabTracker struct{}
+
+// gitLabIssue holds the relevant parts of a GitLab issue
+type gitLabIssue struct {
+	IID         int64    `json:"iid"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	State       string   `json:"state"`
+	Labels      []string `json:"labels"`
+}
+
+// gitLabProjectURL returns the API URL of the project "namespace/name" on the GitLab API at baseURL,
+// e.g. https://gitlab.example.com/api/v4
+func gitLabProjectURL(baseURL string, namespace string, name string) string {
+	return strings.TrimSuffix(baseURL, "/") + "/projects/" + url.PathEscape(namespace+"/"+name)
+}
+
+func (gitLabTracker) name() string {
+	return providerGitLab
+}
+
+func (gitLabTracker) authorize(req *http.Request, token string) {
+	req.Header.Set("PRIVATE-TOKEN", token)
+}
+
+func (gitLabTracker) createIssue(projectURL string, issue trackerIssue) trackerRequest {
+	payload := map[string]string{"title": issue.Title, "description": issue.Body}
+	if len(issue.Labels) > 0 {
+		payload["labels"] = strings.Join(issue.Labels, ",")
+	}
+	return trackerRequest{Method: http.MethodPost, URL: projectURL + "/issues", Payload: payload, Expected: http.StatusCreated}
+}
+
+func (gitLabTracker) getIssue(projectURL string, id string) trackerRequest {
+	return trackerRequest{Method: http.MethodGet, URL: projectURL + "/issues/" + id, Expected: http.StatusOK}
+}
+
+// updateIssue only adds missing labels, labels added on GitLab are kept. Closing and reopening
+// is done with state_event.
+func (gitLabTracker) updateIssue(projectURL string, current trackerIssue, desired trackerIssue) ([]trackerRequest, error) {
+	payload := map[string]string{}
+	if current.Title != desired.Title {
+		payload["title"] = desired.Title
+	}
+	if current.Body != desired.Body {
+		payload["description"] = desired.Body
+	}
+	var missing []string
+	for _, label := range desired.Labels {
+		if !containsLabel(current.Labels, label) {
+			missing = append(missing, label)
+		}
+	}
+	if len(missing) > 0 {
+		payload["add_labels"] = strings.Join(missing, ",")
+	}
+	switch {
+	case desired.Closed && !current.Closed:
+		payload["state_event"] = "close"
+	case !desired.Closed && current.Closed:
+		payload["state_event"] = "reopen"
+	}
+	request := trackerRequest{Method: http.MethodPut, URL: projectURL + "/issues/" + current.ID, Payload: payload, Expected: http.StatusOK}
+	return []trackerRequest{request}, nil
+}
+
+func (gitLabTracker) parseIssue(body []byte) (trackerIssue, error) {
+	var issue gitLabIssue
+	if err := json.Unmarshal(body, &issue); err != nil {
+		return trackerIssue{}, fmt.Errorf("error unmarshaling JSON: %w", err)
+	}
+	if issue.IID == 0 {
+		return trackerIssue{}, fmt.Errorf("GitLab issue has no iid")
+	}
+	return trackerIssue{
+		ID:     strconv.FormatInt(issue.IID, 10),
+		Title:  issue.Title,
+		Body:   issue.Description,
+		Labels: issue.Labels,
+		Closed: issue.State == "closed",
+	}, nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+var _ = Describe("GitLab provider", func() {
+	const (
+		resourceName = "test-gitlab"
+		projectPath  = "/api/v4/projects/group%2Fproject"
+	)
+
+	ctx := context.Background()
+	typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+
+	var (
+		server   *httptest.Server
+		mu       sync.Mutex
+		issue    gitLabIssue
+		requests []string
+	)
+
+	BeforeEach(func() {
+		issue = gitLabIssue{}
+		requests = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			body, _ := io.ReadAll(req.Body)
+			requests = append(requests, req.Method+" "+req.URL.EscapedPath()+" "+string(body))
+			if req.Header.Get("PRIVATE-TOKEN") != "gitlab-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			var payload map[string]string
+			_ = json.Unmarshal(body, &payload)
+			switch {
+			case req.Method == http.MethodPost && req.URL.EscapedPath() == projectPath+"/issues":
+				issue = gitLabIssue{IID: 4, Title: payload["title"], Description: payload["description"], State: "opened",
+					Labels: strings.Split(payload["labels"], ",")}
+				w.WriteHeader(http.StatusCreated)
+			case issue.IID == 0 || req.URL.EscapedPath() != projectPath+"/issues/4":
+				w.WriteHeader(http.StatusNotFound)
+				return
+			case req.Method == http.MethodPut:
+				if title, ok := payload["title"]; ok {
+					issue.Title = title
+				}
+				switch payload["state_event"] {
+				case "close":
+					issue.State = "closed"
+				case "reopen":
+					issue.State = "opened"
+				}
+			}
+			_ = json.NewEncoder(w).Encode(issue)
+		}))
+		Expect(os.Setenv(gitLabTokenEnvVar, "gitlab-token")).To(Succeed())
+
+		resource := &trainingv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec: trainingv1alpha1.GithubIssueSpec{
+				Provider:    providerGitLab,
+				Repo:        server.URL + projectPath,
+				Title:       "title",
+				Description: "body",
+				Labels:      []string{"operator"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		Expect(os.Unsetenv(gitLabTokenEnvVar)).To(Succeed())
+	})
+
+	It("should create, correct, reopen and close the GitLab issue", func() {
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		reconcileGithubIssue := func() {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		By("creating the issue")
+		reconcileGithubIssue()
+		reconcileGithubIssue()
+		Expect(requests).To(ConsistOf(
+			`POST ` + projectPath + `/issues {"description":"body","labels":"operator","title":"title"}`))
+		ghi := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		Expect(ghi.Status.IssueNumber).To(Equal(int64(4)))
+
+		By("correcting the title and reopening the issue")
+		mu.Lock()
+		issue.Title = "edited on GitLab"
+		issue.State = "closed"
+		mu.Unlock()
+		reconcileGithubIssue()
+		Expect(requests).To(ContainElement(
+			`PUT ` + projectPath + `/issues/4 {"state_event":"reopen","title":"title"}`))
+		Expect(issue.Title).To(Equal("title"))
+		Expect(issue.State).To(Equal("opened"))
+
+		By("closing the issue on deletion")
+		Expect(k8sClient.Delete(ctx, ghi)).To(Succeed())
+		reconcileGithubIssue()
+		Expect(requests[len(requests)-1]).To(Equal(`PUT ` + projectPath + `/issues/4 {"state_event":"close"}`))
+		Expect(issue.State).To(Equal("closed"))
+		Expect(apiErrors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, ghi))).To(BeTrue())
+	})
+})
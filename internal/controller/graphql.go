@@ -0,0 +1,102 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// graphQLRequest is the body of a request to the GitHub GraphQL API
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// graphQLResponse is the body of a response of the GitHub GraphQL API
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []graphQLError  `json:"errors"`
+}
+
+// graphQLError is an error of a GraphQL response, path points at the field that could not be resolved
+type graphQLError struct {
+	Type    string        `json:"type"`
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path"`
+}
+
+// gitHubGraphQLURL returns the GraphQL endpoint next to the REST API of the repository URL, e.g.
+// https://api.github.com/graphql, or https://github.example.com/api/graphql for GitHub Enterprise
+func gitHubGraphQLURL(repoURL string) string {
+	base := repoURL
+	if i := strings.Index(repoURL, "/repos/"); i >= 0 {
+		base = repoURL[:i]
+	}
+	if strings.HasSuffix(base, "/api/v3") {
+		return strings.TrimSuffix(base, "/v3") + "/graphql"
+	}
+	return base + "/graphql"
+}
+
+// doGraphQLRequest sends the operation to the GitHub GraphQL API and decodes its data into out. GraphQL reports
+// most failures with a 200 status, so the errors of the response are returned as an error.
+func doGraphQLRequest(url string, request graphQLRequest, accessToken string, out interface{}) error {
+	response, err := sendGraphQLRequest(url, request, accessToken)
+	if err != nil {
+		return err
+	}
+	if len(response.Errors) > 0 {
+		return graphQLErrors(request.OperationName, response.Errors)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(response.Data, out); err != nil {
+		return fmt.Errorf("error unmarshaling JSON: %w", err)
+	}
+	return nil
+}
+
+// sendGraphQLRequest sends the operation to the GitHub GraphQL API and returns the response, which may hold
+// partial data next to its errors
+func sendGraphQLRequest(url string, request graphQLRequest, accessToken string) (graphQLResponse, error) {
+	var response graphQLResponse
+	resp, body, err := doGitHubHTTPRequest(http.MethodPost, url, request, accessToken)
+	if err != nil {
+		return response, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return response, newGitHubStatusError(resp)
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return response, fmt.Errorf("error unmarshaling JSON: %w", err)
+	}
+	return response, nil
+}
+
+// graphQLErrors returns the errors of a GraphQL response as a single error
+func graphQLErrors(operation string, errors []graphQLError) error {
+	messages := make([]string, 0, len(errors))
+	for _, e := range errors {
+		messages = append(messages, e.Message)
+	}
+	return fmt.Errorf("GitHub GraphQL %s failed: %s", operation, strings.Join(messages, "; "))
+}
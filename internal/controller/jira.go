@@ -0,0 +1,241 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+const (
+	// jiraTokenEnvVar is the environment variable holding the token of the Jira provider
+	jiraTokenEnvVar = "JIRA_TOKEN"
+
+	// jiraProjectPath separates the site URL from the project key in the API URL of a Jira project
+	jiraProjectPath = "/rest/api/2/project/"
+
+	defaultJiraIssueType        = "Task"
+	defaultJiraCloseTransition  = "Done"
+	defaultJiraReopenTransition = "To Do"
+)
+
+// jiraTracker files issues with the REST API v2 of Jira Cloud and Server, issues are identified by their
+// numeric ID. Title, description and labels map onto summary, description and labels, and the state is
+// changed with the configured workflow transitions.
+type jiraTracker struct {
+	issueType        string
+	closeTransition  string
+	reopenTransition string
+}
+
+// jiraIssue holds the relevant parts of a Jira issue
+type jiraIssue struct {
+	ID          string           `json:"id"`
+	Key         string           `json:"key"`
+	Fields      jiraIssueFields  `json:"fields"`
+	Transitions []jiraTransition `json:"transitions"`
+}
+
+type jiraIssueFields struct {
+	Summary     string   `json:"summary"`
+	Description string   `json:"description"`
+	Labels      []string `json:"labels"`
+	Status      struct {
+		StatusCategory struct {
+			// Key is "done" for the statuses closing an issue
+			Key string `json:"key"`
+		} `json:"statusCategory"`
+	} `json:"status"`
+}
+
+type jiraTransition struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// newJiraTracker returns the jiraTracker configured by spec, spec may be nil
+func newJiraTracker(spec *trainingv1alpha1.JiraSpec) jiraTracker {
+	tracker := jiraTracker{
+		issueType:        defaultJiraIssueType,
+		closeTransition:  defaultJiraCloseTransition,
+		reopenTransition: defaultJiraReopenTransition,
+	}
+	if spec == nil {
+		return tracker
+	}
+	if spec.IssueType != "" {
+		tracker.issueType = spec.IssueType
+	}
+	if spec.CloseTransition != "" {
+		tracker.closeTransition = spec.CloseTransition
+	}
+	if spec.ReopenTransition != "" {
+		tracker.reopenTransition = spec.ReopenTransition
+	}
+	return tracker
+}
+
+// jiraProjectURL returns the API URL of the project with the given key on the Jira site at baseURL,
+// e.g. https://example.atlassian.net
+func jiraProjectURL(baseURL string, key string) string {
+	return strings.TrimSuffix(baseURL, "/") + jiraProjectPath + url.PathEscape(key)
+}
+
+// splitJiraProjectURL returns the site URL and the project key of a project API URL
+func splitJiraProjectURL(projectURL string) (string, string) {
+	i := strings.LastIndex(projectURL, jiraProjectPath)
+	if i < 0 {
+		return strings.TrimSuffix(projectURL, "/"), ""
+	}
+	key, err := url.PathUnescape(projectURL[i+len(jiraProjectPath):])
+	if err != nil {
+		key = projectURL[i+len(jiraProjectPath):]
+	}
+	return projectURL[:i], key
+}
+
+func jiraIssueURL(projectURL string, id string) string {
+	baseURL, _ := splitJiraProjectURL(projectURL)
+	return baseURL + "/rest/api/2/issue/" + id
+}
+
+func (jiraTracker) name() string {
+	return providerJira
+}
+
+// authorize uses basic authentication for "email:api-token" tokens of Jira Cloud and bearer authentication
+// for the personal access tokens of Jira Server
+func (jiraTracker) authorize(req *http.Request, token string) {
+	if user, password, ok := strings.Cut(token, ":"); ok {
+		req.SetBasicAuth(user, password)
+		return
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+}
+
+func (t jiraTracker) createIssue(projectURL string, issue trackerIssue) trackerRequest {
+	baseURL, key := splitJiraProjectURL(projectURL)
+	fields := map[string]interface{}{
+		"project":     map[string]string{"key": key},
+		"issuetype":   map[string]string{"name": t.issueType},
+		"summary":     issue.Title,
+		"description": issue.Body,
+	}
+	if len(issue.Labels) > 0 {
+		fields["labels"] = issue.Labels
+	}
+	return trackerRequest{Method: http.MethodPost, URL: baseURL + "/rest/api/2/issue",
+		Payload: map[string]interface{}{"fields": fields}, Expected: http.StatusCreated}
+}
+
+func (jiraTracker) getIssue(projectURL string, id string) trackerRequest {
+	return trackerRequest{Method: http.MethodGet, URL: jiraIssueURL(projectURL, id) + "?fields=summary,description,labels,status&expand=transitions",
+		Expected: http.StatusOK}
+}
+
+// updateIssue only adds missing labels, labels added on Jira are kept. The state is changed with the
+// close or reopen transition, which has to be available in the current status of the issue. A closed issue
+// is reopened before it is edited, as workflows commonly forbid editing closed issues.
+func (t jiraTracker) updateIssue(projectURL string, current trackerIssue, desired trackerIssue) ([]trackerRequest, error) {
+	var transition *trackerRequest
+	transitionName := ""
+	switch {
+	case desired.Closed && !current.Closed:
+		transitionName = t.closeTransition
+	case !desired.Closed && current.Closed:
+		transitionName = t.reopenTransition
+	}
+	if transitionName != "" {
+		id, ok := jiraTransitionID(current.Transitions, transitionName)
+		if !ok {
+			return nil, fmt.Errorf("Jira issue %s has no %q transition", current.ID, transitionName)
+		}
+		transition = &trackerRequest{Method: http.MethodPost, URL: jiraIssueURL(projectURL, current.ID) + "/transitions",
+			Payload: map[string]interface{}{"transition": map[string]string{"id": id}}, Expected: http.StatusNoContent}
+	}
+
+	payload := map[string]interface{}{}
+	fields := map[string]string{}
+	if current.Title != desired.Title {
+		fields["summary"] = desired.Title
+	}
+	if current.Body != desired.Body {
+		fields["description"] = desired.Body
+	}
+	if len(fields) > 0 {
+		payload["fields"] = fields
+	}
+	var addLabels []map[string]string
+	for _, label := range desired.Labels {
+		if !containsLabel(current.Labels, label) {
+			addLabels = append(addLabels, map[string]string{"add": label})
+		}
+	}
+	if len(addLabels) > 0 {
+		payload["update"] = map[string]interface{}{"labels": addLabels}
+	}
+
+	var requests []trackerRequest
+	if transition != nil && current.Closed {
+		requests = append(requests, *transition)
+	}
+	if len(payload) > 0 {
+		requests = append(requests, trackerRequest{Method: http.MethodPut, URL: jiraIssueURL(projectURL, current.ID),
+			Payload: payload, Expected: http.StatusNoContent})
+	}
+	if transition != nil && !current.Closed {
+		requests = append(requests, *transition)
+	}
+	return requests, nil
+}
+
+// jiraTransitionID returns the ID of the named transition, names are matched case insensitively
+func jiraTransitionID(transitions map[string]string, name string) (string, bool) {
+	for transitionName, id := range transitions {
+		if strings.EqualFold(transitionName, name) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+func (jiraTracker) parseIssue(body []byte) (trackerIssue, error) {
+	var issue jiraIssue
+	if err := json.Unmarshal(body, &issue); err != nil {
+		return trackerIssue{}, fmt.Errorf("error unmarshaling JSON: %w", err)
+	}
+	if issue.ID == "" {
+		return trackerIssue{}, fmt.Errorf("Jira issue has no id")
+	}
+	transitions := map[string]string{}
+	for _, transition := range issue.Transitions {
+		transitions[transition.Name] = transition.ID
+	}
+	return trackerIssue{
+		ID:          issue.ID,
+		Title:       issue.Fields.Summary,
+		Body:        issue.Fields.Description,
+		Labels:      issue.Fields.Labels,
+		Closed:      issue.Fields.Status.StatusCategory.Key == "done",
+		Transitions: transitions,
+	}, nil
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+var _ = Describe("Jira provider", func() {
+	const (
+		resourceName   = "test-jira"
+		repositoryName = "test-jira-project"
+		issuePath      = "/rest/api/2/issue/10002"
+	)
+
+	ctx := context.Background()
+	typeNamespacedName := types.NamespacedName{Name: resourceName, Namespace: "default"}
+	repositoryNamespacedName := types.NamespacedName{Name: repositoryName, Namespace: "default"}
+
+	var (
+		server   *httptest.Server
+		mu       sync.Mutex
+		issue    *jiraIssue
+		requests []string
+	)
+
+	BeforeEach(func() {
+		issue = nil
+		requests = nil
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			body, _ := io.ReadAll(req.Body)
+			requests = append(requests, req.Method+" "+req.URL.Path+" "+string(body))
+			if user, password, ok := req.BasicAuth(); !ok || user != "user@example.com" || password != "api-token" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			var payload struct {
+				Fields     jiraIssueFields `json:"fields"`
+				Transition jiraTransition  `json:"transition"`
+			}
+			_ = json.Unmarshal(body, &payload)
+			switch {
+			case req.Method == http.MethodGet && req.URL.Path == "/rest/api/2/project/OPS":
+				_, _ = w.Write([]byte(`{"key":"OPS"}`))
+			case req.Method == http.MethodPost && req.URL.Path == "/rest/api/2/issue":
+				issue = &jiraIssue{ID: "10002", Key: "OPS-1", Fields: payload.Fields}
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"id":"10002","key":"OPS-1"}`))
+			case issue == nil || (req.URL.Path != issuePath && req.URL.Path != issuePath+"/transitions"):
+				w.WriteHeader(http.StatusNotFound)
+			case req.Method == http.MethodGet:
+				// Only the transition leaving the current status category is available
+				issue.Transitions = []jiraTransition{{ID: "31", Name: "Resolve"}}
+				if issue.Fields.Status.StatusCategory.Key == "done" {
+					issue.Transitions = []jiraTransition{{ID: "41", Name: "Reopen"}}
+				}
+				_ = json.NewEncoder(w).Encode(issue)
+			case req.Method == http.MethodPut:
+				if payload.Fields.Summary != "" {
+					issue.Fields.Summary = payload.Fields.Summary
+				}
+				w.WriteHeader(http.StatusNoContent)
+			case req.Method == http.MethodPost:
+				switch payload.Transition.ID {
+				case "31":
+					issue.Fields.Status.StatusCategory.Key = "done"
+				case "41":
+					issue.Fields.Status.StatusCategory.Key = "new"
+				default:
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(http.StatusNoContent)
+			}
+		}))
+		credentials := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: repositoryName, Namespace: "default"},
+			Data:       map[string][]byte{"token": []byte("user@example.com:api-token")},
+		}
+		Expect(k8sClient.Create(ctx, credentials)).To(Succeed())
+
+		repository := &trainingv1alpha1.GithubRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: repositoryName, Namespace: "default"},
+			Spec: trainingv1alpha1.GithubRepositorySpec{
+				Provider:       providerJira,
+				Name:           "OPS",
+				APIBaseURL:     server.URL,
+				CredentialsRef: &trainingv1alpha1.SecretKeyReference{Name: repositoryName, Key: "token"},
+				Jira: &trainingv1alpha1.JiraSpec{
+					IssueType:        "Bug",
+					CloseTransition:  "Resolve",
+					ReopenTransition: "Reopen",
+				},
+			},
+		}
+		Expect(k8sClient.Create(ctx, repository)).To(Succeed())
+
+		resource := &trainingv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: resourceName, Namespace: "default"},
+			Spec: trainingv1alpha1.GithubIssueSpec{
+				RepositoryRef: &trainingv1alpha1.RepositoryReference{Name: repositoryName},
+				Title:         "title",
+				Description:   "body",
+				Labels:        []string{"operator"},
+			},
+		}
+		Expect(k8sClient.Create(ctx, resource)).To(Succeed())
+	})
+
+	AfterEach(func() {
+		server.Close()
+		Expect(k8sClient.Delete(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: repositoryName, Namespace: "default"}})).To(Succeed())
+
+		repository := &trainingv1alpha1.GithubRepository{}
+		Expect(k8sClient.Get(ctx, repositoryNamespacedName, repository)).To(Succeed())
+		Expect(k8sClient.Delete(ctx, repository)).To(Succeed())
+		resource := &trainingv1alpha1.GithubIssue{}
+		if err := k8sClient.Get(ctx, typeNamespacedName, resource); err == nil {
+			resource.Finalizers = nil
+			Expect(k8sClient.Update(ctx, resource)).To(Succeed())
+			Expect(k8sClient.Delete(ctx, resource)).To(Succeed())
+		}
+	})
+
+	It("should create, correct, reopen and close the Jira issue with the configured transitions", func() {
+		reconciler := &GithubIssueReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		reconcileGithubIssue := func() {
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: typeNamespacedName})
+			Expect(err).NotTo(HaveOccurred())
+		}
+
+		By("creating the issue in the project of the repository")
+		reconcileGithubIssue()
+		reconcileGithubIssue()
+		Expect(requests).To(ConsistOf(`POST /rest/api/2/issue {"fields":{"description":"body","issuetype":{"name":"Bug"},` +
+			`"labels":["operator"],"project":{"key":"OPS"},"summary":"title"}}`))
+		ghi := &trainingv1alpha1.GithubIssue{}
+		Expect(k8sClient.Get(ctx, typeNamespacedName, ghi)).To(Succeed())
+		Expect(ghi.Status.IssueNumber).To(Equal(int64(10002)))
+
+		By("reopening the issue before correcting its summary")
+		mu.Lock()
+		issue.Fields.Summary = "edited on Jira"
+		issue.Fields.Status.StatusCategory.Key = "done"
+		mu.Unlock()
+		requests = nil
+		reconcileGithubIssue()
+		Expect(requests).To(Equal([]string{
+			`GET ` + issuePath + ` `,
+			`POST ` + issuePath + `/transitions {"transition":{"id":"41"}}`,
+			`PUT ` + issuePath + ` {"fields":{"summary":"title"}}`,
+		}))
+		Expect(issue.Fields.Summary).To(Equal("title"))
+		Expect(issue.Fields.Status.StatusCategory.Key).To(Equal("new"))
+
+		By("closing the issue on deletion")
+		Expect(k8sClient.Delete(ctx, ghi)).To(Succeed())
+		reconcileGithubIssue()
+		Expect(requests[len(requests)-1]).To(Equal(`POST ` + issuePath + `/transitions {"transition":{"id":"31"}}`))
+		Expect(issue.Fields.Status.StatusCategory.Key).To(Equal("done"))
+		Expect(apiErrors.IsNotFound(k8sClient.Get(ctx, typeNamespacedName, ghi))).To(BeTrue())
+	})
+
+	It("should report the Jira project as reachable", func() {
+		reconciler := &GithubRepositoryReconciler{Client: k8sClient, Scheme: k8sClient.Scheme()}
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: repositoryNamespacedName})
+		Expect(err).NotTo(HaveOccurred())
+
+		repository := &trainingv1alpha1.GithubRepository{}
+		Expect(k8sClient.Get(ctx, repositoryNamespacedName, repository)).To(Succeed())
+		Expect(repository.Status.URL).To(Equal(server.URL + "/rest/api/2/project/OPS"))
+		Expect(meta.IsStatusConditionTrue(repository.Status.Conditions, conditionRepositoryReachable)).To(BeTrue())
+	})
+})
@@ -0,0 +1,127 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// DefaultRequestTimeout bounds a request to GitHub or another issue tracker unless SetRequestTimeout is called
+const DefaultRequestTimeout = 30 * time.Second
+
+// gitHubHTTPClient sends the requests of all controllers to GitHub and the other issue trackers, so they share
+// its connections
+var gitHubHTTPClient = &http.Client{Timeout: DefaultRequestTimeout}
+
+// SetRequestTimeout sets how long a request to GitHub or another issue tracker may take, it must be called
+// before the controllers are started
+func SetRequestTimeout(timeout time.Duration) {
+	if timeout > 0 {
+		gitHubHTTPClient.Timeout = timeout
+	}
+}
+
+// ControllerOptions configures the workers and the workqueue rate limiter of a controller.
+// Zero values keep the controller-runtime defaults.
+type ControllerOptions struct {
+	// MaxConcurrentReconciles is the number of objects reconciled in parallel
+	MaxConcurrentReconciles int
+
+	// RateLimiterBaseDelay and RateLimiterMaxDelay bound the exponential backoff of failing objects
+	RateLimiterBaseDelay time.Duration
+	RateLimiterMaxDelay  time.Duration
+
+	// RateLimiterQPS and RateLimiterBurst limit how fast objects are taken from the workqueue overall
+	RateLimiterQPS   float64
+	RateLimiterBurst int
+}
+
+// controllerOptions returns the controller-runtime options, every controller gets its own rate limiter
+func (o ControllerOptions) controllerOptions() controller.Options {
+	opts := controller.Options{MaxConcurrentReconciles: o.MaxConcurrentReconciles}
+	if o.RateLimiterBaseDelay <= 0 && o.RateLimiterMaxDelay <= 0 && o.RateLimiterQPS <= 0 {
+		return opts
+	}
+
+	// Mirrors workqueue.DefaultTypedControllerRateLimiter with the configured values
+	baseDelay, maxDelay := 5*time.Millisecond, 1000*time.Second
+	if o.RateLimiterBaseDelay > 0 {
+		baseDelay = o.RateLimiterBaseDelay
+	}
+	if o.RateLimiterMaxDelay > 0 {
+		maxDelay = o.RateLimiterMaxDelay
+	}
+	qps, burst := 10.0, 100
+	if o.RateLimiterQPS > 0 {
+		qps = o.RateLimiterQPS
+	}
+	if o.RateLimiterBurst > 0 {
+		burst = o.RateLimiterBurst
+	}
+
+	opts.RateLimiter = workqueue.NewTypedMaxOfRateLimiter(
+		workqueue.NewTypedItemExponentialFailureRateLimiter[reconcile.Request](baseDelay, maxDelay),
+		&workqueue.TypedBucketRateLimiter[reconcile.Request]{Limiter: rate.NewLimiter(rate.Limit(qps), burst)},
+	)
+	return opts
+}
+
+// keyedMutex serializes work per key, e.g. per repository, while different keys proceed in parallel
+type keyedMutex struct {
+	mu    sync.Mutex
+	locks map[string]*keyedMutexEntry
+}
+
+// keyedMutexEntry is the mutex of a key with the number of callers holding or waiting for it
+type keyedMutexEntry struct {
+	sync.Mutex
+	refs int
+}
+
+// Lock locks the mutex of key and returns the function unlocking it, the mutex is dropped once no caller
+// holds or waits for it so keys of deleted resources don't pile up
+func (m *keyedMutex) Lock(key string) func() {
+	m.mu.Lock()
+	if m.locks == nil {
+		m.locks = map[string]*keyedMutexEntry{}
+	}
+	entry, ok := m.locks[key]
+	if !ok {
+		entry = &keyedMutexEntry{}
+		m.locks[key] = entry
+	}
+	entry.refs++
+	m.mu.Unlock()
+
+	entry.Lock()
+	return func() {
+		entry.Unlock()
+		m.mu.Lock()
+		entry.refs--
+		if entry.refs == 0 {
+			delete(m.locks, key)
+		}
+		m.mu.Unlock()
+	}
+}
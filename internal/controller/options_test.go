@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Controller options", func() {
+	It("should keep the controller-runtime defaults when unset", func() {
+		opts := ControllerOptions{}.controllerOptions()
+		Expect(opts.MaxConcurrentReconciles).To(BeZero())
+		Expect(opts.RateLimiter).To(BeNil())
+	})
+
+	It("should build a rate limiter from the configured delays", func() {
+		opts := ControllerOptions{
+			MaxConcurrentReconciles: 4,
+			RateLimiterBaseDelay:    time.Second,
+			RateLimiterMaxDelay:     time.Minute,
+		}.controllerOptions()
+		Expect(opts.MaxConcurrentReconciles).To(Equal(4))
+		Expect(opts.RateLimiter).NotTo(BeNil())
+	})
+
+	It("should share one HTTP client with the configured timeout", func() {
+		DeferCleanup(SetRequestTimeout, gitHubHTTPClient.Timeout)
+		Expect(gitHubHTTPClient.Timeout).To(Equal(DefaultRequestTimeout))
+
+		SetRequestTimeout(time.Minute)
+		Expect(gitHubHTTPClient.Timeout).To(Equal(time.Minute))
+		SetRequestTimeout(0)
+		Expect(gitHubHTTPClient.Timeout).To(Equal(time.Minute))
+	})
+
+	It("should serialize work on the same key", func() {
+		var (
+			locks   keyedMutex
+			wg      sync.WaitGroup
+			running int32
+			overlap atomic.Bool
+		)
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				unlock := locks.Lock("https://api.github.com/repos/owner/repo")
+				defer unlock()
+				if atomic.AddInt32(&running, 1) > 1 {
+					overlap.Store(true)
+				}
+				time.Sleep(time.Millisecond)
+				atomic.AddInt32(&running, -1)
+			}()
+		}
+		wg.Wait()
+		Expect(overlap.Load()).To(BeFalse())
+	})
+
+	It("should drop the mutex of a key once it is unlocked", func() {
+		var locks keyedMutex
+		unlock := locks.Lock("https://api.github.com/repos/owner/repo")
+		second := make(chan func())
+		go func() { second <- locks.Lock("https://api.github.com/repos/owner/repo") }()
+		Eventually(func() int {
+			locks.mu.Lock()
+			defer locks.mu.Unlock()
+			return locks.locks["https://api.github.com/repos/owner/repo"].refs
+		}).Should(Equal(2))
+
+		unlock()
+		(<-second)()
+		Expect(locks.locks).To(BeEmpty())
+	})
+})
@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/wait"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+const (
+	// defaultResyncPeriod is used when neither --resync-period nor spec.resyncInterval is set
+	defaultResyncPeriod = time.Minute
+
+	// minResyncInterval bounds spec.resyncInterval so a single GithubIssue cannot exhaust the GitHub rate limit
+	minResyncInterval = 10 * time.Second
+
+	// resyncJitterFactor spreads the resyncs of objects created together by up to 10% of the period
+	resyncJitterFactor = 0.1
+)
+
+// resyncAfter returns the period, or defaultResyncPeriod when it is not set, with jitter added
+func resyncAfter(period time.Duration) time.Duration {
+	if period <= 0 {
+		period = defaultResyncPeriod
+	}
+	return wait.Jitter(period, resyncJitterFactor)
+}
+
+// resyncResult requeues the GithubIssue after spec.resyncInterval, or after the global resync period
+func (r *GithubIssueReconciler) resyncResult(ghi *trainingv1alpha1.GithubIssue) ctrl.Result {
+	period := r.ResyncPeriod
+	if interval := ghi.Spec.ResyncInterval; interval != nil && interval.Duration > 0 {
+		period = max(interval.Duration, minResyncInterval)
+	}
+	return ctrl.Result{RequeueAfter: resyncAfter(period)}
+}
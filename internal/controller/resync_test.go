@@ -0,0 +1,54 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+var _ = Describe("GithubIssue resync", func() {
+	It("should resync after the global period with jitter", func() {
+		reconciler := &GithubIssueReconciler{ResyncPeriod: 5 * time.Minute}
+		ghi := &trainingv1alpha1.GithubIssue{}
+
+		for i := 0; i < 20; i++ {
+			result := reconciler.resyncResult(ghi)
+			Expect(result.RequeueAfter).To(BeNumerically(">=", 5*time.Minute))
+			Expect(result.RequeueAfter).To(BeNumerically("<", 5*time.Minute+30*time.Second))
+		}
+	})
+
+	It("should prefer spec.resyncInterval and bound it", func() {
+		reconciler := &GithubIssueReconciler{}
+		ghi := &trainingv1alpha1.GithubIssue{Spec: trainingv1alpha1.GithubIssueSpec{
+			ResyncInterval: &metav1.Duration{Duration: time.Hour},
+		}}
+		Expect(reconciler.resyncResult(ghi).RequeueAfter).To(BeNumerically(">=", time.Hour))
+
+		ghi.Spec.ResyncInterval.Duration = time.Second
+		Expect(reconciler.resyncResult(ghi).RequeueAfter).To(BeNumerically(">=", minResyncInterval))
+
+		ghi.Spec.ResyncInterval = nil
+		Expect(reconciler.resyncResult(ghi).RequeueAfter).To(BeNumerically(">=", defaultResyncPeriod))
+	})
+})
@@ -0,0 +1,308 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+const (
+	providerGitHub = "GitHub"
+	providerGitLab = "GitLab"
+	providerJira   = "Jira"
+	providerGitea  = "Gitea"
+)
+
+// trackerIssue is the provider neutral view of an issue
+type trackerIssue struct {
+	ID     string
+	Title  string
+	Body   string
+	Labels []string
+	Closed bool
+	// Transitions maps the names of the workflow transitions available to the issue to their IDs,
+	// only set by trackers with workflows
+	Transitions map[string]string
+}
+
+// trackerRequest is a request to the API of an issue tracker
+type trackerRequest struct {
+	Method  string
+	URL     string
+	Payload interface{}
+	// Expected is the status code of a successful response
+	Expected int
+}
+
+// issueTracker builds the API requests of an issue tracker other than GitHub and parses its responses.
+// The requests are sent by the reconciler, so dry-run mode and the Synced condition work for every provider.
+type issueTracker interface {
+	// name is the provider name used in errors
+	name() string
+	// authorize sets the credentials of a request
+	authorize(req *http.Request, token string)
+	createIssue(projectURL string, issue trackerIssue) trackerRequest
+	getIssue(projectURL string, id string) trackerRequest
+	// updateIssue returns the requests changing the current issue into the desired one, in order
+	updateIssue(projectURL string, current trackerIssue, desired trackerIssue) ([]trackerRequest, error)
+	parseIssue(body []byte) (trackerIssue, error)
+}
+
+// githubIssueProvider returns spec.provider, defaulting to GitHub
+func githubIssueProvider(ghi *trainingv1alpha1.GithubIssue) string {
+	if ghi.Spec.Provider == "" {
+		return providerGitHub
+	}
+	return ghi.Spec.Provider
+}
+
+// issueTrackerFor returns the issueTracker of the provider of the repository, GitHub repositories have none
+func issueTrackerFor(repository githubRepository) issueTracker {
+	return newIssueTracker(repository.Provider, repository.Jira)
+}
+
+// newIssueTracker returns the issueTracker of provider, nil for GitHub
+func newIssueTracker(provider string, jira *trainingv1alpha1.JiraSpec) issueTracker {
+	switch provider {
+	case providerGitLab:
+		return gitLabTracker{}
+	case providerJira:
+		return newJiraTracker(jira)
+	case providerGitea:
+		return giteaTracker{}
+	default:
+		return nil
+	}
+}
+
+// providerTokenEnvVar returns the environment variable holding the global token of provider
+func providerTokenEnvVar(provider string) string {
+	switch provider {
+	case providerGitLab:
+		return gitLabTokenEnvVar
+	case providerJira:
+		return jiraTokenEnvVar
+	case providerGitea:
+		return giteaTokenEnvVar
+	default:
+		return tokenEnvVar
+	}
+}
+
+// issueLabels returns the default labels of the repository followed by spec.labels
+func issueLabels(ghi *trainingv1alpha1.GithubIssue, repository githubRepository) []string {
+	labels := append([]string(nil), repository.Labels...)
+	for _, label := range ghi.Spec.Labels {
+		if !containsLabel(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+func containsLabel(labels []string, label string) bool {
+	for _, l := range labels {
+		if strings.EqualFold(l, label) {
+			return true
+		}
+	}
+	return false
+}
+
+// sendTrackerRequest sends the request and returns the response body. Writes are skipped in dry-run mode,
+// in which case skipped is true.
+func (r *GithubIssueReconciler) sendTrackerRequest(ctx context.Context, tracker issueTracker, request trackerRequest, token string) (body []byte, skipped bool, err error) {
+	if request.Method != http.MethodGet && r.dryRunRequest(ctx, request.Method, request.URL, request.Payload) {
+		return nil, true, nil
+	}
+	body, err = doTrackerRequest(ctx, tracker, request, token)
+	return body, false, err
+}
+
+// sendTrackerRequests sends the requests in order, stopping at the first failure
+func (r *GithubIssueReconciler) sendTrackerRequests(ctx context.Context, tracker issueTracker, requests []trackerRequest, token string) error {
+	for _, request := range requests {
+		if _, _, err := r.sendTrackerRequest(ctx, tracker, request, token); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// doTrackerRequest sends the request to the tracker and returns the response body. A response with another
+// status than the expected one is returned as a *gitHubStatusError.
+func doTrackerRequest(ctx context.Context, tracker issueTracker, request trackerRequest, token string) ([]byte, error) {
+	var reqBody io.Reader
+	if request.Payload != nil {
+		jsonData, err := json.Marshal(request.Payload)
+		if err != nil {
+			return nil, fmt.Errorf("error marshaling JSON: %w", err)
+		}
+		reqBody = bytes.NewBuffer(jsonData)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, request.Method, request.URL, reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if reqBody != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	tracker.authorize(req, strings.TrimSpace(token))
+
+	resp, err := gitHubHTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != request.Expected {
+		statusErr := newGitHubStatusError(resp)
+		statusErr.Provider = tracker.name()
+		return nil, statusErr
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("error reading response: %w", err)
+	}
+	return body, nil
+}
+
+// fetchTrackerIssue reads the issue with the given ID from the tracker
+func (r *GithubIssueReconciler) fetchTrackerIssue(ctx context.Context, tracker issueTracker, projectURL string, id string, token string) (trackerIssue, error) {
+	body, _, err := r.sendTrackerRequest(ctx, tracker, tracker.getIssue(projectURL, id), token)
+	if err != nil {
+		return trackerIssue{}, err
+	}
+	return tracker.parseIssue(body)
+}
+
+// reconcileTrackerIssue files, updates and closes the issue of ghi in an issue tracker other than GitHub.
+// It follows the GitHub reconcile: the issue is created once, kept in sync with the spec according to
+// spec.mode, kept open or closed according to spec.state and closed when the GithubIssue is deleted.
+func (r *GithubIssueReconciler) reconcileTrackerIssue(ctx context.Context, ghi *trainingv1alpha1.GithubIssue, tracker issueTracker,
+	repository githubRepository, token string) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+	mode := githubIssueMode(ghi)
+	id := githubIssueNumber(ghi)
+
+	// The object is being deleted
+	if !ghi.DeletionTimestamp.IsZero() {
+		if id != "" && mode != modeObserveOnly {
+			current, err := r.fetchTrackerIssue(ctx, tracker, repository.URL, id, token)
+			if err != nil {
+				return r.githubIssueSyncFailed(ctx, ghi, err)
+			}
+			if !current.Closed {
+				closed := current
+				closed.Closed = true
+				log.Info("Closing issue", "provider", tracker.name(), "issue", id)
+				requests, err := tracker.updateIssue(repository.URL, current, closed)
+				if err == nil {
+					err = r.sendTrackerRequests(ctx, tracker, requests, token)
+				}
+				if err != nil {
+					return r.githubIssueSyncFailed(ctx, ghi, err)
+				}
+			}
+		}
+		if err := r.patchGithubIssueFinalizer(ctx, ghi, false); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
+	title, description, err := r.desiredTitleAndDescription(ctx, ghi)
+	if err != nil {
+		log.Error(err, "Failed to render GithubIssue template")
+		return ctrl.Result{}, err
+	}
+	desired := trackerIssue{Title: title, Body: description, Labels: issueLabels(ghi, repository), Closed: ghi.Spec.State == stateClosed}
+
+	if id == "" {
+		// ObserveOnly never files an issue, it waits for an existing one to be referenced
+		if mode == modeObserveOnly {
+			if err := r.recordGithubIssueDrift(ctx, ghi, nil, false); err != nil {
+				return ctrl.Result{}, err
+			}
+			return r.resyncResult(ghi), nil
+		}
+
+		log.Info("Creating issue", "provider", tracker.name(), "project", repository.URL)
+		body, skipped, err := r.sendTrackerRequest(ctx, tracker, tracker.createIssue(repository.URL, desired), token)
+		if err != nil {
+			return r.githubIssueSyncFailed(ctx, ghi, err)
+		}
+		if skipped {
+			return r.resyncResult(ghi), nil
+		}
+		created, err := tracker.parseIssue(body)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if err := r.recordGithubIssueNumber(ctx, ghi, created.ID); err != nil {
+			return ctrl.Result{}, err
+		}
+		// Status changes are filtered by the predicates, so the new issue is synced by an explicit requeue
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	current, err := r.fetchTrackerIssue(ctx, tracker, repository.URL, id, token)
+	if err != nil {
+		return r.githubIssueSyncFailed(ctx, ghi, err)
+	}
+
+	// Outside the Enforce mode differences are reported instead of overwritten
+	drift := githubIssueDrift(title, description, map[string]interface{}{"title": current.Title, "body": current.Body})
+	if err := r.recordGithubIssueDrift(ctx, ghi, drift, true); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	missingLabel := false
+	for _, label := range desired.Labels {
+		if !containsLabel(current.Labels, label) {
+			missingLabel = true
+		}
+	}
+	if mode == modeEnforce && (len(drift) > 0 || current.Closed != desired.Closed || missingLabel) {
+		log.Info("Updating issue", "provider", tracker.name(), "issue", id)
+		requests, err := tracker.updateIssue(repository.URL, current, desired)
+		if err == nil {
+			err = r.sendTrackerRequests(ctx, tracker, requests, token)
+		}
+		if err != nil {
+			return r.githubIssueSyncFailed(ctx, ghi, err)
+		}
+	}
+
+	if err := r.recordGithubIssueSynced(ctx, ghi, nil); err != nil {
+		return ctrl.Result{}, err
+	}
+	return r.resyncResult(ghi), nil
+}
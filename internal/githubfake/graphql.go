@@ -0,0 +1,459 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package githubfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// issueAlias matches the aliased issue fields of a batch read, e.g. i12: issue(number: 12)
+var issueAlias = regexp.MustCompile(`(\w+):\s*issue\(number:\s*(\d+)\)`)
+
+// Project is a GitHub Projects (v2) board stored by the fake server
+type Project struct {
+	ID     string
+	Owner  string
+	Number int
+	Fields []ProjectField
+	Items  []ProjectItem
+}
+
+// ProjectField is a custom field of a project
+type ProjectField struct {
+	ID   string
+	Name string
+	// DataType is SINGLE_SELECT, ITERATION, TEXT, NUMBER or DATE
+	DataType string
+	// Options are the options of a SINGLE_SELECT field or the iteration titles of an ITERATION field
+	Options []string
+}
+
+// ProjectItem is an issue added to a project
+type ProjectItem struct {
+	ID string
+	// ContentID is the node ID of the issue, see IssueNodeID
+	ContentID string
+	// Values are the field values by field name, as option names, iteration titles, numbers, dates or text
+	Values map[string]string
+}
+
+// graphQLRequest is the body of a request to the GraphQL API. The fake does not parse the query, it answers
+// the operations the operator sends by their name.
+type graphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// IssueNodeID returns the GraphQL node ID of an issue of the repository "owner/name"
+func IssueNodeID(fullName string, number int64) string {
+	return fmt.Sprintf("I_%s_%d", strings.ToLower(fullName), number)
+}
+
+// CreateProject stores a project of the organization or user owner. IDs of the project and its fields are
+// set when empty.
+func (s *Server) CreateProject(owner string, number int, fields []ProjectField) Project {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	project := &Project{ID: fmt.Sprintf("PVT_%s_%d", strings.ToLower(owner), number), Owner: owner, Number: number}
+	for i, field := range fields {
+		if field.ID == "" {
+			field.ID = fmt.Sprintf("%s_F%d", project.ID, i+1)
+		}
+		project.Fields = append(project.Fields, field)
+	}
+	s.projects = append(s.projects, project)
+	return project.copy()
+}
+
+// Project returns the project of owner with the given number
+func (s *Server) Project(owner string, number int) (Project, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	project := s.project(owner, number)
+	if project == nil {
+		return Project{}, false
+	}
+	return project.copy(), true
+}
+
+// SetProjectItemValue changes a field value of a project item as if it was edited on the board
+func (s *Server) SetProjectItemValue(owner string, number int, itemID string, field string, value string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	item := s.project(owner, number).item(itemID)
+	if item == nil {
+		return false
+	}
+	item.Values[field] = value
+	return true
+}
+
+func (s *Server) project(owner string, number int) *Project {
+	for _, project := range s.projects {
+		if strings.EqualFold(project.Owner, owner) && project.Number == number {
+			return project
+		}
+	}
+	return nil
+}
+
+func (s *Server) projectByID(id string) *Project {
+	for _, project := range s.projects {
+		if project.ID == id {
+			return project
+		}
+	}
+	return nil
+}
+
+func (project *Project) item(id string) *ProjectItem {
+	if project == nil {
+		return nil
+	}
+	for i := range project.Items {
+		if project.Items[i].ID == id {
+			return &project.Items[i]
+		}
+	}
+	return nil
+}
+
+func (project *Project) field(id string) *ProjectField {
+	for i := range project.Fields {
+		if project.Fields[i].ID == id {
+			return &project.Fields[i]
+		}
+	}
+	return nil
+}
+
+func (project *Project) copy() Project {
+	result := *project
+	result.Fields = append([]ProjectField(nil), project.Fields...)
+	result.Items = nil
+	for _, item := range project.Items {
+		values := make(map[string]string, len(item.Values))
+		for name, value := range item.Values {
+			values[name] = value
+		}
+		item.Values = values
+		result.Items = append(result.Items, item)
+	}
+	return result
+}
+
+// optionID returns the ID of an option of a SINGLE_SELECT field or an iteration of an ITERATION field
+func (field *ProjectField) optionID(index int) string {
+	return fmt.Sprintf("%s_O%d", field.ID, index+1)
+}
+
+// optionIDOf returns the ID of the option or iteration with the given name
+func (field *ProjectField) optionIDOf(name string) string {
+	for i, option := range field.Options {
+		if option == name {
+			return field.optionID(i)
+		}
+	}
+	return ""
+}
+
+func (s *Server) serveGraphQL(w http.ResponseWriter, body []byte) {
+	var request graphQLRequest
+	if err := json.Unmarshal(body, &request); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+
+	var data interface{}
+	var err error
+	switch request.OperationName {
+	case "GithubIssues":
+		s.githubIssues(w, request)
+		return
+	case "GithubIssueProject":
+		data, err = s.githubIssueProject(request.Variables)
+	case "AddProjectItem":
+		data, err = s.addProjectItem(request.Variables)
+	case "UpdateProjectItemField":
+		data, err = s.updateProjectItemField(request.Variables)
+	case "GithubIssueParent":
+		data, err = s.githubIssueParent(request.Variables)
+	case "AddSubIssue":
+		data, err = s.addSubIssue(request.Variables)
+	default:
+		err = fmt.Errorf("unknown operation %q", request.OperationName)
+	}
+	if err != nil {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data":   nil,
+			"errors": []map[string]string{{"type": "NOT_FOUND", "message": err.Error()}},
+		})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"data": data})
+}
+
+func (s *Server) githubIssueProject(variables map[string]interface{}) (interface{}, error) {
+	owner, _ := variables["owner"].(string)
+	number, _ := variables["number"].(float64)
+	issue, _ := variables["issue"].(string)
+	project := s.project(owner, int(number))
+	if project == nil {
+		return nil, fmt.Errorf("could not resolve to a ProjectV2 with the number %d.", int(number))
+	}
+
+	fields := []map[string]interface{}{}
+	for i := range project.Fields {
+		field := &project.Fields[i]
+		node := map[string]interface{}{"id": field.ID, "name": field.Name, "dataType": field.DataType}
+		options := []map[string]string{}
+		for i, option := range field.Options {
+			options = append(options, map[string]string{"id": field.optionID(i), "name": option, "title": option})
+		}
+		switch field.DataType {
+		case "SINGLE_SELECT":
+			node["options"] = options
+		case "ITERATION":
+			node["configuration"] = map[string]interface{}{"iterations": options}
+		}
+		fields = append(fields, node)
+	}
+
+	// The project items of the issue span all projects, as on GitHub
+	items := []map[string]interface{}{}
+	for _, other := range s.projects {
+		for _, item := range other.Items {
+			if item.ContentID != issue {
+				continue
+			}
+			values := []map[string]interface{}{}
+			for i := range other.Fields {
+				field := &other.Fields[i]
+				value, found := item.Values[field.Name]
+				if !found {
+					continue
+				}
+				node := map[string]interface{}{"field": map[string]string{"name": field.Name}}
+				switch field.DataType {
+				case "SINGLE_SELECT":
+					node["optionId"] = field.optionIDOf(value)
+					node["name"] = value
+				case "ITERATION":
+					node["iterationId"] = field.optionIDOf(value)
+					node["title"] = value
+				case "NUMBER":
+					number, _ := strconv.ParseFloat(value, 64)
+					node["number"] = number
+				case "DATE":
+					node["date"] = value
+				default:
+					node["text"] = value
+				}
+				values = append(values, node)
+			}
+			items = append(items, map[string]interface{}{
+				"id":          item.ID,
+				"project":     map[string]string{"id": other.ID},
+				"fieldValues": map[string]interface{}{"nodes": values},
+			})
+		}
+	}
+
+	return map[string]interface{}{
+		"repositoryOwner": map[string]interface{}{
+			"projectV2": map[string]interface{}{"id": project.ID, "fields": map[string]interface{}{"nodes": fields}},
+		},
+		"node": map[string]interface{}{"projectItems": map[string]interface{}{"nodes": items}},
+	}, nil
+}
+
+func (s *Server) addProjectItem(variables map[string]interface{}) (interface{}, error) {
+	projectID, _ := variables["project"].(string)
+	content, _ := variables["content"].(string)
+	project := s.projectByID(projectID)
+	if project == nil {
+		return nil, fmt.Errorf("could not resolve to a node with the global id of '%s'", projectID)
+	}
+
+	// Adding an issue twice returns its existing item, as on GitHub
+	var id string
+	for _, item := range project.Items {
+		if item.ContentID == content {
+			id = item.ID
+		}
+	}
+	if id == "" {
+		id = fmt.Sprintf("%s_I%d", project.ID, len(project.Items)+1)
+		project.Items = append(project.Items, ProjectItem{ID: id, ContentID: content, Values: map[string]string{}})
+	}
+	return map[string]interface{}{"addProjectV2ItemById": map[string]interface{}{"item": map[string]string{"id": id}}}, nil
+}
+
+func (s *Server) updateProjectItemField(variables map[string]interface{}) (interface{}, error) {
+	projectID, _ := variables["project"].(string)
+	itemID, _ := variables["item"].(string)
+	fieldID, _ := variables["field"].(string)
+	value, _ := variables["value"].(map[string]interface{})
+	project := s.projectByID(projectID)
+	item := project.item(itemID)
+	if item == nil {
+		return nil, fmt.Errorf("could not resolve to a node with the global id of '%s'", itemID)
+	}
+	field := project.field(fieldID)
+	if field == nil {
+		return nil, fmt.Errorf("could not resolve to a node with the global id of '%s'", fieldID)
+	}
+
+	switch {
+	case value["singleSelectOptionId"] != nil || value["iterationId"] != nil:
+		optionID, _ := value["singleSelectOptionId"].(string)
+		if optionID == "" {
+			optionID, _ = value["iterationId"].(string)
+		}
+		found := false
+		for i, option := range field.Options {
+			if field.optionID(i) == optionID {
+				item.Values[field.Name] = option
+				found = true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("the option %s does not belong to the field %s", optionID, field.Name)
+		}
+	case value["number"] != nil:
+		number, _ := value["number"].(float64)
+		item.Values[field.Name] = strconv.FormatFloat(number, 'f', -1, 64)
+	case value["date"] != nil:
+		item.Values[field.Name], _ = value["date"].(string)
+	default:
+		item.Values[field.Name], _ = value["text"].(string)
+	}
+	return map[string]interface{}{"updateProjectV2ItemFieldValue": map[string]interface{}{"projectV2Item": map[string]string{"id": item.ID}}}, nil
+}
+
+func (s *Server) githubIssueParent(variables map[string]interface{}) (interface{}, error) {
+	owner, _ := variables["owner"].(string)
+	name, _ := variables["name"].(string)
+	number, _ := variables["number"].(float64)
+	id, _ := variables["issue"].(string)
+	fullName := owner + "/" + name
+	parent := s.repository(fullName).issue(int64(number))
+	if parent == nil || parent.PullRequest != nil {
+		return nil, fmt.Errorf("could not resolve to an Issue with the number of %d.", int(number))
+	}
+
+	var node map[string]interface{}
+	if issue := s.issueByNodeID(id); issue != nil {
+		node = map[string]interface{}{"parent": nil}
+		if issue.ParentID != "" {
+			node["parent"] = map[string]string{"id": issue.ParentID}
+		}
+	}
+	return map[string]interface{}{
+		"repository": map[string]interface{}{"issue": map[string]string{"id": IssueNodeID(fullName, parent.Number)}},
+		"node":       node,
+	}, nil
+}
+
+func (s *Server) addSubIssue(variables map[string]interface{}) (interface{}, error) {
+	parentID, _ := variables["parent"].(string)
+	id, _ := variables["issue"].(string)
+	if s.issueByNodeID(parentID) == nil {
+		return nil, fmt.Errorf("could not resolve to a node with the global id of '%s'", parentID)
+	}
+	issue := s.issueByNodeID(id)
+	if issue == nil || id == parentID {
+		return nil, fmt.Errorf("could not resolve to a node with the global id of '%s'", id)
+	}
+	// replaceParent is always set by the operator, so an existing parent is replaced
+	issue.ParentID = parentID
+	return map[string]interface{}{"addSubIssue": map[string]interface{}{"issue": map[string]string{"id": parentID}}}, nil
+}
+
+// issueByNodeID returns the issue with the given node ID in any repository
+func (s *Server) issueByNodeID(id string) *Issue {
+	for _, repo := range s.repos {
+		for _, issue := range repo.issues {
+			if issue.PullRequest == nil && IssueNodeID(repo.fullName, issue.Number) == id {
+				return issue
+			}
+		}
+	}
+	return nil
+}
+
+// githubIssues answers a batch read of issues. Issues that do not exist are null and reported as NOT_FOUND
+// errors next to the other issues, as on GitHub.
+func (s *Server) githubIssues(w http.ResponseWriter, request graphQLRequest) {
+	owner, _ := request.Variables["owner"].(string)
+	name, _ := request.Variables["name"].(string)
+	fullName := owner + "/" + name
+	repo := s.repository(fullName)
+
+	issues := map[string]interface{}{}
+	errors := []map[string]interface{}{}
+	for _, match := range issueAlias.FindAllStringSubmatch(request.Query, -1) {
+		alias := match[1]
+		number, _ := strconv.ParseInt(match[2], 10, 64)
+		issue := repo.issue(number)
+		if issue == nil || issue.PullRequest != nil {
+			issues[alias] = nil
+			errors = append(errors, map[string]interface{}{
+				"type":    "NOT_FOUND",
+				"path":    []string{"repository", alias},
+				"message": fmt.Sprintf("Could not resolve to an Issue with the number of %d.", number),
+			})
+			continue
+		}
+		labels := []map[string]string{}
+		for _, label := range issue.Labels {
+			labels = append(labels, map[string]string{"name": label})
+		}
+		issues[alias] = map[string]interface{}{
+			"id":        IssueNodeID(fullName, issue.Number),
+			"number":    issue.Number,
+			"title":     issue.Title,
+			"body":      issue.Body,
+			"state":     strings.ToUpper(issue.State),
+			"updatedAt": issue.UpdatedAt.Format(time.RFC3339),
+			"labels":    map[string]interface{}{"nodes": labels},
+		}
+	}
+
+	response := map[string]interface{}{
+		"data": map[string]interface{}{
+			"rateLimit": map[string]interface{}{
+				"cost":      1,
+				"limit":     s.rateLimit,
+				"remaining": s.rateLimit - s.rateUsed,
+				"resetAt":   s.rateReset.UTC().Format(time.RFC3339),
+			},
+			"repository": issues,
+		},
+	}
+	if len(errors) > 0 {
+		response["errors"] = errors
+	}
+	writeJSON(w, http.StatusOK, response)
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package githubfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// PullRequest holds the pull request fields of an Issue
+type PullRequest struct {
+	// Head is the branch holding the changes, as given when the pull request was opened
+	Head string
+	Base string
+	// HeadSHA is the commit the check runs are reported on
+	HeadSHA string
+	Draft   bool
+	Merged  bool
+	// Mergeable is nil while GitHub computes it
+	Mergeable      *bool
+	MergeableState string
+	// RequestedReviewers are the logins whose review is pending
+	RequestedReviewers []string
+	Reviews            []Review
+	CheckRuns          []CheckRun
+}
+
+// Review is a review of a pull request
+type Review struct {
+	Author string
+	// State is APPROVED, CHANGES_REQUESTED or COMMENTED
+	State string
+}
+
+// CheckRun is a check run reported on the head commit of a pull request
+type CheckRun struct {
+	Name string
+	// Status is queued, in_progress or completed
+	Status     string
+	Conclusion string
+}
+
+// CreatePullRequest stores a pull request in the repository "owner/name" and returns it with its number set
+func (s *Server) CreatePullRequest(fullName string, issue Issue, pr PullRequest) Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.createPullRequest(s.repository(fullName), issue, pr)
+}
+
+func (s *Server) createPullRequest(repo *repository, issue Issue, pr PullRequest) *Issue {
+	if pr.HeadSHA == "" {
+		pr.HeadSHA = fmt.Sprintf("%040x", len(repo.issues)+1)
+	}
+	if pr.Mergeable == nil && pr.MergeableState == "" {
+		mergeable := true
+		pr.Mergeable = &mergeable
+		pr.MergeableState = "clean"
+	}
+	issue.PullRequest = &pr
+	return s.createIssue(repo, issue)
+}
+
+// headMatches compares two heads of pull requests, given as "owner:branch" or as a branch of owner
+func headMatches(owner string, head string, other string) bool {
+	qualify := func(head string) string {
+		if !strings.Contains(head, ":") {
+			return owner + ":" + head
+		}
+		return head
+	}
+	return strings.EqualFold(qualify(head), qualify(other))
+}
+
+func (s *Server) listPullRequests(w http.ResponseWriter, req *http.Request, fullName string, repo *repository) {
+	query := req.URL.Query()
+	state := query.Get("state")
+	if state == "" {
+		state = "open"
+	}
+	owner, _, _ := strings.Cut(fullName, "/")
+
+	var items []map[string]interface{}
+	for i := len(repo.issues) - 1; i >= 0; i-- {
+		issue := repo.issues[i]
+		pr := issue.PullRequest
+		if pr == nil || (state != "all" && issue.State != state) {
+			continue
+		}
+		if head := query.Get("head"); head != "" && !headMatches(owner, head, pr.Head) {
+			continue
+		}
+		if base := query.Get("base"); base != "" && base != pr.Base {
+			continue
+		}
+		items = append(items, s.pullRequestJSON(fullName, issue))
+	}
+	writeJSON(w, http.StatusOK, s.paginate(w, req, items))
+}
+
+func (s *Server) postPullRequest(w http.ResponseWriter, body []byte, fullName string, repo *repository) {
+	var payload struct {
+		Title string `json:"title"`
+		Body  string `json:"body"`
+		Head  string `json:"head"`
+		Base  string `json:"base"`
+		Draft bool   `json:"draft"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Title == "" || payload.Head == "" || payload.Base == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+	owner, _, _ := strings.Cut(fullName, "/")
+	for _, issue := range repo.issues {
+		if pr := issue.PullRequest; pr != nil && issue.State == "open" && pr.Base == payload.Base && headMatches(owner, payload.Head, pr.Head) {
+			writeError(w, http.StatusUnprocessableEntity, "A pull request already exists for "+payload.Head)
+			return
+		}
+	}
+	issue := s.createPullRequest(repo, Issue{Title: payload.Title, Body: payload.Body},
+		PullRequest{Head: payload.Head, Base: payload.Base, Draft: payload.Draft})
+	writeJSON(w, http.StatusCreated, s.pullRequestJSON(fullName, issue))
+}
+
+func (s *Server) servePullRequest(w http.ResponseWriter, req *http.Request, body []byte, fullName string, issue *Issue, rest []string) {
+	pr := issue.PullRequest
+	switch {
+	case len(rest) == 0 && req.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.pullRequestJSON(fullName, issue))
+	case len(rest) == 0 && req.Method == http.MethodPatch:
+		var payload struct {
+			Title *string `json:"title"`
+			Body  *string `json:"body"`
+			State *string `json:"state"`
+			Base  *string `json:"base"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+			return
+		}
+		if payload.State != nil && (pr.Merged || (*payload.State != "open" && *payload.State != "closed")) {
+			writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+			return
+		}
+		now := time.Now().UTC()
+		if payload.Title != nil {
+			issue.Title = *payload.Title
+		}
+		if payload.Body != nil {
+			issue.Body = *payload.Body
+		}
+		if payload.Base != nil {
+			pr.Base = *payload.Base
+		}
+		if payload.State != nil && *payload.State != issue.State {
+			issue.State = *payload.State
+			issue.ClosedAt = nil
+			if issue.State == "closed" {
+				issue.ClosedAt = &now
+			}
+		}
+		issue.UpdatedAt = now
+		writeJSON(w, http.StatusOK, s.pullRequestJSON(fullName, issue))
+	case len(rest) == 1 && rest[0] == "requested_reviewers" && req.Method == http.MethodPost:
+		var payload struct {
+			Reviewers []string `json:"reviewers"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+			return
+		}
+		for _, reviewer := range payload.Reviewers {
+			if !containsFold(pr.RequestedReviewers, reviewer) {
+				pr.RequestedReviewers = append(pr.RequestedReviewers, reviewer)
+			}
+		}
+		issue.UpdatedAt = time.Now().UTC()
+		writeJSON(w, http.StatusCreated, s.pullRequestJSON(fullName, issue))
+	case len(rest) == 1 && rest[0] == "reviews" && req.Method == http.MethodGet:
+		items := make([]map[string]interface{}, 0, len(pr.Reviews))
+		for i, review := range pr.Reviews {
+			items = append(items, map[string]interface{}{
+				"id":    i + 1,
+				"user":  map[string]string{"login": review.Author},
+				"state": review.State,
+			})
+		}
+		writeJSON(w, http.StatusOK, s.paginate(w, req, items))
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) listCheckRuns(w http.ResponseWriter, repo *repository, sha string) {
+	runs := []map[string]interface{}{}
+	for _, issue := range repo.issues {
+		if issue.PullRequest == nil || issue.PullRequest.HeadSHA != sha {
+			continue
+		}
+		for i, run := range issue.PullRequest.CheckRuns {
+			result := map[string]interface{}{"id": i + 1, "name": run.Name, "status": run.Status, "conclusion": nil}
+			if run.Conclusion != "" {
+				result["conclusion"] = run.Conclusion
+			}
+			runs = append(runs, result)
+		}
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{"total_count": len(runs), "check_runs": runs})
+}
+
+func (s *Server) pullRequestJSON(fullName string, issue *Issue) map[string]interface{} {
+	pr := issue.PullRequest
+	reviewers := make([]map[string]string, 0, len(pr.RequestedReviewers))
+	for _, login := range pr.RequestedReviewers {
+		reviewers = append(reviewers, map[string]string{"login": login})
+	}
+	result := map[string]interface{}{
+		"id":                  issue.Number,
+		"number":              issue.Number,
+		"title":               issue.Title,
+		"body":                issue.Body,
+		"state":               issue.State,
+		"url":                 fmt.Sprintf("%s/pulls/%d", s.RepositoryURL(fullName), issue.Number),
+		"html_url":            fmt.Sprintf("%s/%s/pull/%d", s.URL, fullName, issue.Number),
+		"draft":               pr.Draft,
+		"merged":              pr.Merged,
+		"mergeable":           nil,
+		"mergeable_state":     pr.MergeableState,
+		"head":                map[string]string{"ref": pr.Head, "sha": pr.HeadSHA},
+		"base":                map[string]string{"ref": pr.Base},
+		"labels":              labelsJSON(issue.Labels),
+		"requested_reviewers": reviewers,
+		"created_at":          issue.CreatedAt.Format(time.RFC3339),
+		"updated_at":          issue.UpdatedAt.Format(time.RFC3339),
+	}
+	if pr.Mergeable != nil {
+		result["mergeable"] = *pr.Mergeable
+	}
+	return result
+}
@@ -0,0 +1,826 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package githubfake is an in-process fake of the GitHub REST API endpoints used by the operator, and of the
+// GraphQL operations it sends. It keeps issues, pull requests, comments, labels and projects in memory and can
+// be used from unit, envtest and e2e suites, or by the manager to run offline with --fake-github.
+package githubfake
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultRateLimit is the number of requests allowed per rate limit window, as for an authenticated GitHub user
+	DefaultRateLimit = 5000
+
+	rateLimitWindow = time.Hour
+	defaultPerPage  = 30
+	maxPerPage      = 100
+)
+
+// Issue is an issue stored by the fake server
+type Issue struct {
+	Number    int64
+	Title     string
+	Body      string
+	State     string
+	Labels    []string
+	Assignees []string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	ClosedAt  *time.Time
+	// PullRequest is set when the issue is a pull request, which shares the numbers of the issues as on GitHub
+	PullRequest *PullRequest
+	// ParentID is the node ID of the parent issue of a sub-issue, see IssueNodeID
+	ParentID string
+}
+
+// Comment is an issue comment stored by the fake server
+type Comment struct {
+	ID          int64
+	IssueNumber int64
+	Author      string
+	Body        string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// Request is a request received by the fake server
+type Request struct {
+	Method string
+	// Path is the URL path without the query
+	Path  string
+	Query string
+	Body  string
+}
+
+// Fault makes the fake server answer the matching requests with an error
+type Fault struct {
+	// Method of the requests to fail, any method when empty.
+	Method string
+	// Path is a path.Match pattern of the URL paths to fail, any path when empty.
+	Path string
+	// Status is the HTTP status code returned.
+	Status int
+	// Message is the error message returned, the status text when empty.
+	Message string
+	// Times is the number of requests failed before the fault is removed, unlimited when 0.
+	Times int
+}
+
+type repository struct {
+	// fullName keeps the case of the first use, lookups are case insensitive as on GitHub
+	fullName string
+	issues   []*Issue
+	comments []*Comment
+}
+
+// Server is a fake GitHub REST API server listening on a local port
+type Server struct {
+	*httptest.Server
+
+	mu            sync.Mutex
+	token         string
+	repos         map[string]*repository
+	projects      []*Project
+	nextCommentID int64
+	requests      []Request
+	faults        []*Fault
+	rateLimit     int
+	rateUsed      int
+	rateReset     time.Time
+}
+
+// NewServer starts a fake GitHub server. Repositories are created on first use and any token is accepted
+// until SetToken is called. Close the server when done.
+func NewServer() *Server {
+	s := &Server{
+		repos:     map[string]*repository{},
+		rateLimit: DefaultRateLimit,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// RepositoryURL returns the API URL of the repository "owner/name", as used in spec.repo
+func (s *Server) RepositoryURL(fullName string) string {
+	return s.URL + "/repos/" + fullName
+}
+
+// SetToken makes the server reject requests that are not authenticated with token
+func (s *Server) SetToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+// SetRateLimit sets the number of requests allowed per window and starts a new window
+func (s *Server) SetRateLimit(limit int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.rateLimit = limit
+	s.rateUsed = 0
+	s.rateReset = time.Time{}
+}
+
+// InjectFault fails the requests matching the fault until it is used up or ClearFaults is called
+func (s *Server) InjectFault(fault Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &fault)
+}
+
+// ClearFaults removes all injected faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// Requests returns the requests received so far, oldest first
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Reset drops all repositories, requests, faults and rate limit usage
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.repos = map[string]*repository{}
+	s.projects = nil
+	s.nextCommentID = 0
+	s.requests = nil
+	s.faults = nil
+	s.rateUsed = 0
+	s.rateReset = time.Time{}
+}
+
+// CreateIssue stores an issue in the repository "owner/name" and returns it with its number set
+func (s *Server) CreateIssue(fullName string, issue Issue) Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.createIssue(s.repository(fullName), issue)
+}
+
+// Issue returns the issue of the repository "owner/name" with the given number
+func (s *Server) Issue(fullName string, number int64) (Issue, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue := s.repository(fullName).issue(number)
+	if issue == nil {
+		return Issue{}, false
+	}
+	return *issue, true
+}
+
+// Issues returns the issues of the repository "owner/name" ordered by number
+func (s *Server) Issues(fullName string) []Issue {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issues := make([]Issue, 0, len(s.repository(fullName).issues))
+	for _, issue := range s.repository(fullName).issues {
+		issues = append(issues, *issue)
+	}
+	return issues
+}
+
+// UpdateIssue changes an issue of the repository "owner/name" as if it was edited on GitHub
+func (s *Server) UpdateIssue(fullName string, number int64, update func(*Issue)) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	issue := s.repository(fullName).issue(number)
+	if issue == nil {
+		return false
+	}
+	update(issue)
+	issue.UpdatedAt = time.Now().UTC()
+	return true
+}
+
+// AddComment comments an issue of the repository "owner/name" as author
+func (s *Server) AddComment(fullName string, number int64, author string, body string) Comment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.addComment(s.repository(fullName), number, author, body)
+}
+
+// Comments returns the comments of an issue of the repository "owner/name", oldest first
+func (s *Server) Comments(fullName string, number int64) []Comment {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var comments []Comment
+	for _, comment := range s.repository(fullName).comments {
+		if comment.IssueNumber == number {
+			comments = append(comments, *comment)
+		}
+	}
+	return comments
+}
+
+func (s *Server) repository(fullName string) *repository {
+	repo, ok := s.repos[strings.ToLower(fullName)]
+	if !ok {
+		repo = &repository{fullName: fullName}
+		s.repos[strings.ToLower(fullName)] = repo
+	}
+	return repo
+}
+
+func (repo *repository) issue(number int64) *Issue {
+	if number < 1 || number > int64(len(repo.issues)) {
+		return nil
+	}
+	return repo.issues[number-1]
+}
+
+func (repo *repository) comment(id int64) *Comment {
+	for _, comment := range repo.comments {
+		if comment.ID == id {
+			return comment
+		}
+	}
+	return nil
+}
+
+func (s *Server) createIssue(repo *repository, issue Issue) *Issue {
+	now := time.Now().UTC()
+	issue.Number = int64(len(repo.issues)) + 1
+	if issue.State == "" {
+		issue.State = "open"
+	}
+	if issue.CreatedAt.IsZero() {
+		issue.CreatedAt = now
+	}
+	issue.UpdatedAt = now
+	repo.issues = append(repo.issues, &issue)
+	return &issue
+}
+
+func (s *Server) addComment(repo *repository, number int64, author string, body string) *Comment {
+	now := time.Now().UTC()
+	s.nextCommentID++
+	comment := &Comment{ID: s.nextCommentID, IssueNumber: number, Author: author, Body: body, CreatedAt: now, UpdatedAt: now}
+	repo.comments = append(repo.comments, comment)
+	return comment
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	body, _ := io.ReadAll(req.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = append(s.requests, Request{Method: req.Method, Path: req.URL.Path, Query: req.URL.RawQuery, Body: string(body)})
+
+	if s.token != "" && !authorized(req.Header.Get("Authorization"), s.token) {
+		writeError(w, http.StatusUnauthorized, "Bad credentials")
+		return
+	}
+	if !s.countRateLimit(w, req.URL.Path) {
+		writeError(w, http.StatusForbidden, "API rate limit exceeded")
+		return
+	}
+	if fault := s.matchFault(req); fault != nil {
+		message := fault.Message
+		if message == "" {
+			message = http.StatusText(fault.Status)
+		}
+		writeError(w, fault.Status, message)
+		return
+	}
+
+	s.route(w, req, body)
+}
+
+func authorized(header string, token string) bool {
+	return header == "token "+token || header == "Bearer "+token
+}
+
+// countRateLimit sets the rate limit headers and returns false when the limit of the window is used up.
+// /rate_limit itself does not count against the limit, as on GitHub.
+func (s *Server) countRateLimit(w http.ResponseWriter, urlPath string) bool {
+	now := time.Now()
+	if s.rateReset.IsZero() || !now.Before(s.rateReset) {
+		s.rateUsed = 0
+		s.rateReset = now.Add(rateLimitWindow)
+	}
+	allowed := s.rateUsed < s.rateLimit
+	if allowed && urlPath != "/rate_limit" {
+		s.rateUsed++
+	}
+
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(s.rateLimit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(s.rateLimit-s.rateUsed))
+	w.Header().Set("X-RateLimit-Used", strconv.Itoa(s.rateUsed))
+	w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(s.rateReset.Unix(), 10))
+	w.Header().Set("X-RateLimit-Resource", "core")
+	return allowed
+}
+
+func (s *Server) matchFault(req *http.Request) *Fault {
+	for i, fault := range s.faults {
+		if fault.Method != "" && !strings.EqualFold(fault.Method, req.Method) {
+			continue
+		}
+		if fault.Path != "" {
+			if matched, _ := path.Match(fault.Path, req.URL.Path); !matched {
+				continue
+			}
+		}
+		if fault.Times > 0 {
+			fault.Times--
+			if fault.Times == 0 {
+				s.faults = append(s.faults[:i], s.faults[i+1:]...)
+			}
+		}
+		return fault
+	}
+	return nil
+}
+
+// route dispatches the request on its path segments, the issue and comment routes overlap for ServeMux patterns
+func (s *Server) route(w http.ResponseWriter, req *http.Request, body []byte) {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	switch {
+	case len(segments) == 1 && segments[0] == "rate_limit" && req.Method == http.MethodGet:
+		s.getRateLimit(w)
+		return
+	case len(segments) == 2 && segments[0] == "search" && segments[1] == "issues" && req.Method == http.MethodGet:
+		s.searchIssues(w, req)
+		return
+	case len(segments) == 1 && segments[0] == "graphql" && req.Method == http.MethodPost:
+		s.serveGraphQL(w, body)
+		return
+	case len(segments) < 3 || segments[0] != "repos":
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	fullName := segments[1] + "/" + segments[2]
+	repo := s.repository(fullName)
+	rest := segments[3:]
+	switch {
+	case len(rest) == 0 && req.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"name":      segments[2],
+			"full_name": fullName,
+			"url":       s.RepositoryURL(fullName),
+			"owner":     map[string]string{"login": segments[1]},
+		})
+	case len(rest) == 1 && rest[0] == "labels" && req.Method == http.MethodGet:
+		s.listRepositoryLabels(w, repo)
+	case len(rest) == 1 && rest[0] == "issues" && req.Method == http.MethodGet:
+		s.listIssues(w, req, fullName, repo)
+	case len(rest) == 1 && rest[0] == "issues" && req.Method == http.MethodPost:
+		s.postIssue(w, body, fullName, repo)
+	case len(rest) == 3 && rest[0] == "issues" && rest[1] == "comments":
+		s.serveComment(w, req, body, fullName, repo, rest[2])
+	case len(rest) == 1 && rest[0] == "pulls" && req.Method == http.MethodGet:
+		s.listPullRequests(w, req, fullName, repo)
+	case len(rest) == 1 && rest[0] == "pulls" && req.Method == http.MethodPost:
+		s.postPullRequest(w, body, fullName, repo)
+	case len(rest) >= 2 && rest[0] == "pulls":
+		number, err := strconv.ParseInt(rest[1], 10, 64)
+		issue := repo.issue(number)
+		if err != nil || issue == nil || issue.PullRequest == nil {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		s.servePullRequest(w, req, body, fullName, issue, rest[2:])
+	case len(rest) == 3 && rest[0] == "commits" && rest[2] == "check-runs" && req.Method == http.MethodGet:
+		s.listCheckRuns(w, repo, rest[1])
+	case len(rest) >= 2 && rest[0] == "issues":
+		number, err := strconv.ParseInt(rest[1], 10, 64)
+		issue := repo.issue(number)
+		if err != nil || issue == nil {
+			writeError(w, http.StatusNotFound, "Not Found")
+			return
+		}
+		s.serveIssue(w, req, body, fullName, repo, issue, rest[2:])
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) serveIssue(w http.ResponseWriter, req *http.Request, body []byte, fullName string, repo *repository, issue *Issue, rest []string) {
+	switch {
+	case len(rest) == 0 && req.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, s.issueJSON(fullName, repo, issue))
+	case len(rest) == 0 && req.Method == http.MethodPatch:
+		s.patchIssue(w, body, fullName, repo, issue)
+	case len(rest) == 1 && rest[0] == "comments" && req.Method == http.MethodGet:
+		s.listComments(w, req, fullName, repo, issue)
+	case len(rest) == 1 && rest[0] == "comments" && req.Method == http.MethodPost:
+		var payload struct {
+			Body string `json:"body"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil || payload.Body == "" {
+			writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+			return
+		}
+		comment := s.addComment(repo, issue.Number, "fake-github", payload.Body)
+		writeJSON(w, http.StatusCreated, s.commentJSON(fullName, comment))
+	case len(rest) == 1 && rest[0] == "labels" && req.Method == http.MethodGet:
+		writeJSON(w, http.StatusOK, labelsJSON(issue.Labels))
+	case len(rest) == 1 && rest[0] == "labels" && req.Method == http.MethodPost:
+		var payload struct {
+			Labels []string `json:"labels"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+			return
+		}
+		for _, label := range payload.Labels {
+			if !containsFold(issue.Labels, label) {
+				issue.Labels = append(issue.Labels, label)
+			}
+		}
+		issue.UpdatedAt = time.Now().UTC()
+		writeJSON(w, http.StatusOK, labelsJSON(issue.Labels))
+	case len(rest) == 2 && rest[0] == "labels" && req.Method == http.MethodDelete:
+		labels := issue.Labels[:0]
+		found := false
+		for _, label := range issue.Labels {
+			if strings.EqualFold(label, rest[1]) {
+				found = true
+				continue
+			}
+			labels = append(labels, label)
+		}
+		if !found {
+			writeError(w, http.StatusNotFound, "Label does not exist")
+			return
+		}
+		issue.Labels = labels
+		issue.UpdatedAt = time.Now().UTC()
+		writeJSON(w, http.StatusOK, labelsJSON(issue.Labels))
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) postIssue(w http.ResponseWriter, body []byte, fullName string, repo *repository) {
+	var payload struct {
+		Title     string   `json:"title"`
+		Body      string   `json:"body"`
+		Labels    []string `json:"labels"`
+		Assignees []string `json:"assignees"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Title == "" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+	issue := s.createIssue(repo, Issue{
+		Title:     payload.Title,
+		Body:      payload.Body,
+		Labels:    payload.Labels,
+		Assignees: payload.Assignees,
+	})
+	writeJSON(w, http.StatusCreated, s.issueJSON(fullName, repo, issue))
+}
+
+func (s *Server) patchIssue(w http.ResponseWriter, body []byte, fullName string, repo *repository, issue *Issue) {
+	var payload struct {
+		Title     *string   `json:"title"`
+		Body      *string   `json:"body"`
+		State     *string   `json:"state"`
+		Labels    *[]string `json:"labels"`
+		Assignees *[]string `json:"assignees"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		writeError(w, http.StatusBadRequest, "Problems parsing JSON")
+		return
+	}
+	if payload.State != nil && *payload.State != "open" && *payload.State != "closed" {
+		writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+		return
+	}
+
+	now := time.Now().UTC()
+	if payload.Title != nil {
+		issue.Title = *payload.Title
+	}
+	if payload.Body != nil {
+		issue.Body = *payload.Body
+	}
+	if payload.State != nil && *payload.State != issue.State {
+		issue.State = *payload.State
+		issue.ClosedAt = nil
+		if issue.State == "closed" {
+			issue.ClosedAt = &now
+		}
+	}
+	if payload.Labels != nil {
+		issue.Labels = *payload.Labels
+	}
+	if payload.Assignees != nil {
+		issue.Assignees = *payload.Assignees
+	}
+	issue.UpdatedAt = now
+	writeJSON(w, http.StatusOK, s.issueJSON(fullName, repo, issue))
+}
+
+func (s *Server) listIssues(w http.ResponseWriter, req *http.Request, fullName string, repo *repository) {
+	state := req.URL.Query().Get("state")
+	if state == "" {
+		state = "open"
+	}
+	var labels []string
+	if value := req.URL.Query().Get("labels"); value != "" {
+		labels = strings.Split(value, ",")
+	}
+
+	var items []map[string]interface{}
+	for i := len(repo.issues) - 1; i >= 0; i-- {
+		issue := repo.issues[i]
+		if state != "all" && issue.State != state {
+			continue
+		}
+		if !hasLabels(issue, labels) {
+			continue
+		}
+		items = append(items, s.issueJSON(fullName, repo, issue))
+	}
+	writeJSON(w, http.StatusOK, s.paginate(w, req, items))
+}
+
+// searchIssues supports the repo:, state:, is:, type: and label: qualifiers, other terms must be in the title
+func (s *Server) searchIssues(w http.ResponseWriter, req *http.Request) {
+	var fullName, state string
+	var labels, terms []string
+	for _, term := range strings.Fields(req.URL.Query().Get("q")) {
+		qualifier, value, found := strings.Cut(term, ":")
+		switch {
+		case !found:
+			terms = append(terms, strings.ToLower(term))
+		case qualifier == "repo":
+			fullName = value
+		case qualifier == "state" || (qualifier == "is" && (value == "open" || value == "closed")):
+			state = value
+		case qualifier == "label":
+			labels = append(labels, strings.Trim(value, `"`))
+		}
+	}
+
+	items := []map[string]interface{}{}
+	var names []string
+	for name := range s.repos {
+		if fullName == "" || strings.EqualFold(name, fullName) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		repo := s.repos[name]
+		for _, issue := range repo.issues {
+			if state != "" && issue.State != state {
+				continue
+			}
+			if !hasLabels(issue, labels) || !containsTerms(issue.Title, terms) {
+				continue
+			}
+			items = append(items, s.issueJSON(repo.fullName, repo, issue))
+		}
+	}
+
+	total := len(items)
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"total_count":        total,
+		"incomplete_results": false,
+		"items":              s.paginate(w, req, items),
+	})
+}
+
+func (s *Server) listComments(w http.ResponseWriter, req *http.Request, fullName string, repo *repository, issue *Issue) {
+	var since time.Time
+	if value := req.URL.Query().Get("since"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+			return
+		}
+		since = parsed
+	}
+
+	var items []map[string]interface{}
+	for _, comment := range repo.comments {
+		if comment.IssueNumber != issue.Number || comment.UpdatedAt.Before(since) {
+			continue
+		}
+		items = append(items, s.commentJSON(fullName, comment))
+	}
+	writeJSON(w, http.StatusOK, s.paginate(w, req, items))
+}
+
+func (s *Server) serveComment(w http.ResponseWriter, req *http.Request, body []byte, fullName string, repo *repository, id string) {
+	commentID, err := strconv.ParseInt(id, 10, 64)
+	comment := repo.comment(commentID)
+	if err != nil || comment == nil {
+		writeError(w, http.StatusNotFound, "Not Found")
+		return
+	}
+
+	switch req.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, s.commentJSON(fullName, comment))
+	case http.MethodPatch:
+		var payload struct {
+			Body string `json:"body"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil || payload.Body == "" {
+			writeError(w, http.StatusUnprocessableEntity, "Validation Failed")
+			return
+		}
+		comment.Body = payload.Body
+		comment.UpdatedAt = time.Now().UTC()
+		writeJSON(w, http.StatusOK, s.commentJSON(fullName, comment))
+	case http.MethodDelete:
+		for i := range repo.comments {
+			if repo.comments[i] == comment {
+				repo.comments = append(repo.comments[:i], repo.comments[i+1:]...)
+				break
+			}
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeError(w, http.StatusNotFound, "Not Found")
+	}
+}
+
+func (s *Server) listRepositoryLabels(w http.ResponseWriter, repo *repository) {
+	var labels []string
+	for _, issue := range repo.issues {
+		for _, label := range issue.Labels {
+			if !containsFold(labels, label) {
+				labels = append(labels, label)
+			}
+		}
+	}
+	sort.Strings(labels)
+	writeJSON(w, http.StatusOK, labelsJSON(labels))
+}
+
+func (s *Server) getRateLimit(w http.ResponseWriter) {
+	core := map[string]interface{}{
+		"limit":     s.rateLimit,
+		"remaining": s.rateLimit - s.rateUsed,
+		"used":      s.rateUsed,
+		"reset":     s.rateReset.Unix(),
+	}
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"resources": map[string]interface{}{"core": core},
+		"rate":      core,
+	})
+}
+
+func (s *Server) issueJSON(fullName string, repo *repository, issue *Issue) map[string]interface{} {
+	url := fmt.Sprintf("%s/issues/%d", s.RepositoryURL(fullName), issue.Number)
+	comments := 0
+	for _, comment := range repo.comments {
+		if comment.IssueNumber == issue.Number {
+			comments++
+		}
+	}
+	assignees := make([]map[string]string, 0, len(issue.Assignees))
+	for _, login := range issue.Assignees {
+		assignees = append(assignees, map[string]string{"login": login})
+	}
+
+	result := map[string]interface{}{
+		"id":             issue.Number,
+		"node_id":        IssueNodeID(fullName, issue.Number),
+		"number":         issue.Number,
+		"title":          issue.Title,
+		"body":           issue.Body,
+		"state":          issue.State,
+		"url":            url,
+		"html_url":       fmt.Sprintf("%s/%s/issues/%d", s.URL, fullName, issue.Number),
+		"comments_url":   url + "/comments",
+		"repository_url": s.RepositoryURL(fullName),
+		"labels":         labelsJSON(issue.Labels),
+		"assignees":      assignees,
+		"comments":       comments,
+		"created_at":     issue.CreatedAt.Format(time.RFC3339),
+		"updated_at":     issue.UpdatedAt.Format(time.RFC3339),
+		"closed_at":      nil,
+	}
+	if issue.ClosedAt != nil {
+		result["closed_at"] = issue.ClosedAt.Format(time.RFC3339)
+	}
+	if issue.PullRequest != nil {
+		result["pull_request"] = map[string]interface{}{"url": fmt.Sprintf("%s/pulls/%d", s.RepositoryURL(fullName), issue.Number)}
+	}
+	return result
+}
+
+func (s *Server) commentJSON(fullName string, comment *Comment) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         comment.ID,
+		"body":       comment.Body,
+		"url":        fmt.Sprintf("%s/issues/comments/%d", s.RepositoryURL(fullName), comment.ID),
+		"issue_url":  fmt.Sprintf("%s/issues/%d", s.RepositoryURL(fullName), comment.IssueNumber),
+		"user":       map[string]string{"login": comment.Author},
+		"created_at": comment.CreatedAt.Format(time.RFC3339),
+		"updated_at": comment.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+func labelsJSON(labels []string) []map[string]string {
+	result := make([]map[string]string, 0, len(labels))
+	for _, label := range labels {
+		result = append(result, map[string]string{"name": label})
+	}
+	return result
+}
+
+// paginate returns the page selected by the page and per_page parameters and sets the Link header
+func (s *Server) paginate(w http.ResponseWriter, req *http.Request, items []map[string]interface{}) []map[string]interface{} {
+	perPage, err := strconv.Atoi(req.URL.Query().Get("per_page"))
+	if err != nil || perPage < 1 {
+		perPage = defaultPerPage
+	}
+	perPage = min(perPage, maxPerPage)
+	page, err := strconv.Atoi(req.URL.Query().Get("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	start := min((page-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+	if end < len(items) {
+		query := req.URL.Query()
+		query.Set("page", strconv.Itoa(page+1))
+		w.Header().Set("Link", fmt.Sprintf(`<%s%s?%s>; rel="next"`, s.URL, req.URL.Path, query.Encode()))
+	}
+	if items[start:end] == nil {
+		return []map[string]interface{}{}
+	}
+	return items[start:end]
+}
+
+func hasLabels(issue *Issue, labels []string) bool {
+	for _, label := range labels {
+		if !containsFold(issue.Labels, label) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsTerms(title string, terms []string) bool {
+	title = strings.ToLower(title)
+	for _, term := range terms {
+		if !strings.Contains(title, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, map[string]string{
+		"message":           message,
+		"documentation_url": "https://docs.github.com/rest",
+	})
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package githubfake
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fake GitHub server", func() {
+	var server *Server
+
+	BeforeEach(func() {
+		server = NewServer()
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	send := func(method string, url string, payload interface{}) (*http.Response, map[string]interface{}) {
+		var body io.Reader
+		if payload != nil {
+			data, err := json.Marshal(payload)
+			Expect(err).NotTo(HaveOccurred())
+			body = bytes.NewReader(data)
+		}
+		req, err := http.NewRequest(method, url, body)
+		Expect(err).NotTo(HaveOccurred())
+		req.Header.Set("Authorization", "token secret")
+		resp, err := http.DefaultClient.Do(req)
+		Expect(err).NotTo(HaveOccurred())
+		defer resp.Body.Close()
+
+		var result map[string]interface{}
+		data, err := io.ReadAll(resp.Body)
+		Expect(err).NotTo(HaveOccurred())
+		_ = json.Unmarshal(data, &result)
+		return resp, result
+	}
+
+	It("should create, read, update and search issues", func() {
+		repoURL := server.RepositoryURL("owner/repo")
+
+		resp, issue := send("POST", repoURL+"/issues", map[string]interface{}{"title": "title", "body": "body", "labels": []string{"bug"}})
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		Expect(issue["number"]).To(BeEquivalentTo(1))
+		Expect(issue["url"]).To(Equal(repoURL + "/issues/1"))
+
+		resp, issue = send("PATCH", repoURL+"/issues/1", map[string]string{"state": "closed"})
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(issue["state"]).To(Equal("closed"))
+		Expect(issue["title"]).To(Equal("title"))
+		Expect(issue["closed_at"]).NotTo(BeNil())
+
+		server.CreateIssue("owner/repo", Issue{Title: "second"})
+		resp, result := send("GET", server.URL+"/search/issues?q=repo:owner/repo+type:issue+state:open", nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(result["total_count"]).To(BeEquivalentTo(1))
+
+		stored, found := server.Issue("owner/repo", 1)
+		Expect(found).To(BeTrue())
+		Expect(stored.Labels).To(ConsistOf("bug"))
+		Expect(stored.State).To(Equal("closed"))
+	})
+
+	It("should manage comments", func() {
+		repoURL := server.RepositoryURL("owner/repo")
+		server.CreateIssue("owner/repo", Issue{Title: "title"})
+
+		resp, comment := send("POST", repoURL+"/issues/1/comments", map[string]string{"body": "first"})
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		commentURL := comment["url"].(string)
+
+		resp, _ = send("PATCH", commentURL, map[string]string{"body": "edited"})
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(server.Comments("owner/repo", 1)).To(ConsistOf(HaveField("Body", "edited")))
+
+		resp, _ = send("DELETE", commentURL, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusNoContent))
+		resp, _ = send("GET", commentURL, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+	})
+
+	It("should open, review and close pull requests", func() {
+		repoURL := server.RepositoryURL("owner/repo")
+
+		resp, pr := send("POST", repoURL+"/pulls", map[string]interface{}{"title": "title", "head": "feature", "base": "main"})
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		Expect(pr["number"]).To(BeEquivalentTo(1))
+		Expect(pr["mergeable"]).To(BeTrue())
+		resp, _ = send("POST", repoURL+"/pulls", map[string]interface{}{"title": "title", "head": "owner:feature", "base": "main"})
+		Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+		resp, _ = send("POST", repoURL+"/pulls/1/requested_reviewers", map[string][]string{"reviewers": {"alice"}})
+		Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+		Expect(server.UpdateIssue("owner/repo", 1, func(issue *Issue) {
+			issue.PullRequest.CheckRuns = []CheckRun{{Name: "test", Status: "completed", Conclusion: "success"}}
+		})).To(BeTrue())
+		sha := pr["head"].(map[string]interface{})["sha"].(string)
+		resp, checks := send("GET", repoURL+"/commits/"+sha+"/check-runs", nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(checks["total_count"]).To(BeEquivalentTo(1))
+
+		resp, pr = send("PATCH", repoURL+"/pulls/1", map[string]string{"state": "closed"})
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(pr["state"]).To(Equal("closed"))
+		Expect(pr["requested_reviewers"]).To(HaveLen(1))
+	})
+
+	It("should reject bad credentials, enforce the rate limit and inject faults", func() {
+		repoURL := server.RepositoryURL("owner/repo")
+
+		server.SetToken("other")
+		resp, _ := send("GET", repoURL, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusUnauthorized))
+		server.SetToken("secret")
+
+		server.InjectFault(Fault{Method: "GET", Path: "/repos/*/*", Status: http.StatusBadGateway, Times: 1})
+		resp, _ = send("GET", repoURL, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusBadGateway))
+		resp, _ = send("GET", repoURL, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		server.SetRateLimit(1)
+		resp, _ = send("GET", repoURL, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+		Expect(resp.Header.Get("X-RateLimit-Remaining")).To(Equal("0"))
+		resp, _ = send("GET", repoURL, nil)
+		Expect(resp.StatusCode).To(Equal(http.StatusForbidden))
+
+		Expect(server.Requests()).To(HaveLen(5))
+	})
+})
@@ -0,0 +1,306 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package policy evaluates GithubIssuePolicies, which restrict the repositories
+// GithubIssues and GithubPullRequests in a namespace may file into and the namespaces
+// GithubIssueRules may target Nodes from, and the kinds of objects issue templates may read.
+// It is shared by the reconcilers and admission webhooks.
+package policy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+// +kubebuilder:rbac:groups=training.redhat.com,resources=githubissuepolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=core,resources=namespaces,verbs=get;list;watch
+
+const (
+	// publicGitHubAPIHost is the host of repositories named by patterns without a host
+	publicGitHubAPIHost = "api.github.com"
+
+	defaultGitHubAPIBaseURL = "https://" + publicGitHubAPIHost
+
+	// defaultProvider is the provider of repositories and GithubIssues without spec.provider
+	defaultProvider = "GitHub"
+
+	// jiraProjectPath separates the site URL from the project key in the API URL of a Jira project
+	jiraProjectPath = "/rest/api/2/project/"
+)
+
+// DefaultAPIBaseURLs are the API base URLs of repositories without spec.apiBaseURL by provider. Gitea and Jira
+// have no public instance every repository lives on, so their repositories must set spec.apiBaseURL.
+var DefaultAPIBaseURLs = map[string]string{
+	"GitHub": defaultGitHubAPIBaseURL,
+	"GitLab": "https://gitlab.com/api/v4",
+}
+
+// ErrInvalidRepository is wrapped by the errors returned for repositories whose API URL cannot be derived
+var ErrInvalidRepository = errors.New("invalid repository")
+
+// TemplateObjectKinds are the kinds spec.template.objectRef of a GithubIssue may reference. Secrets and any kind not
+// listed are refused, the operator reads them with its own permissions and the rendered issue is public.
+var TemplateObjectKinds = []schema.GroupKind{
+	{Kind: "ConfigMap"},
+	{Kind: "Event"},
+	{Kind: "Node"},
+	{Kind: "Pod"},
+	{Group: "apps", Kind: "Deployment"},
+	{Group: "batch", Kind: "Job"},
+}
+
+// TemplateObjectAllowed reports whether an object of apiVersion and kind may be read by an issue template
+func TemplateObjectAllowed(apiVersion string, kind string) bool {
+	gk := schema.FromAPIVersionAndKind(apiVersion, kind).GroupKind()
+	for _, allowed := range TemplateObjectKinds {
+		if gk == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// RepositoryFullName returns the "owner/name" of a repository API URL such as https://api.github.com/repos/owner/name
+func RepositoryFullName(url string) (string, error) {
+	index := strings.Index(url, "repos/")
+	if index == -1 {
+		return "", fmt.Errorf("repository URL %q does not contain repos/", url)
+	}
+
+	parts := strings.Split(strings.Trim(url[index+len("repos/"):], "/"), "/")
+	if len(parts) < 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("repository URL %q does not name an owner and a repository", url)
+	}
+	return parts[0] + "/" + parts[1], nil
+}
+
+// RepositoryName returns the name policies match a repository API URL by, its host followed by the
+// "owner/name" of the repository, e.g. api.github.com/owner/name. The host is part of the name, as the
+// token of the repository is sent to it. GitLab project URLs are named by the unescaped path of the
+// project, e.g. gitlab.example.com/group/project for https://gitlab.example.com/api/v4/projects/group%2Fproject,
+// and Jira project URLs by the project key, e.g. jira.example.com/OPS for https://jira.example.com/rest/api/2/project/OPS.
+func RepositoryName(repoURL string) (string, error) {
+	parsed, err := url.Parse(repoURL)
+	if err != nil || parsed.Host == "" {
+		return "", fmt.Errorf("repository URL %q has no host", repoURL)
+	}
+	if _, key, ok := strings.Cut(parsed.Path, jiraProjectPath); ok {
+		key = strings.Trim(key, "/")
+		if key == "" || strings.Contains(key, "/") {
+			return "", fmt.Errorf("repository URL %q does not name a Jira project", repoURL)
+		}
+		return strings.ToLower(parsed.Host) + "/" + key, nil
+	}
+	if _, project, ok := strings.Cut(parsed.Path, "/projects/"); ok && !strings.Contains(parsed.Path, "/repos/") {
+		project = strings.Trim(project, "/")
+		if project == "" {
+			return "", fmt.Errorf("repository URL %q does not name a project", repoURL)
+		}
+		return strings.ToLower(parsed.Host) + "/" + project, nil
+	}
+	fullName, err := RepositoryFullName(parsed.Path)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(parsed.Host) + "/" + fullName, nil
+}
+
+// ResolveRepositoryName returns the name policies match the repository of a GithubIssue or GithubPullRequest by,
+// see RepositoryName. The referenced GithubRepository or ClusterGithubRepository is read when ref is set,
+// otherwise repo is the API URL of the repository. A repo that cannot be parsed is returned as is, so it is only
+// allowed in unrestricted namespaces. provider is spec.provider of the GithubIssue, which is used when the
+// referenced repository does not set its own.
+func ResolveRepositoryName(ctx context.Context, c client.Reader, namespace string, repo string,
+	ref *trainingv1alpha1.RepositoryReference, provider string) (string, error) {
+	if ref == nil {
+		if name, err := RepositoryName(repo); err == nil {
+			return name, nil
+		}
+		return repo, nil
+	}
+
+	var spec *trainingv1alpha1.GithubRepositorySpec
+	if ref.Kind == "ClusterGithubRepository" {
+		repo := &trainingv1alpha1.ClusterGithubRepository{}
+		if err := c.Get(ctx, types.NamespacedName{Name: ref.Name}, repo); err != nil {
+			return "", fmt.Errorf("failed to get ClusterGithubRepository %s: %w", ref.Name, err)
+		}
+		spec = &repo.Spec
+	} else {
+		repo := &trainingv1alpha1.GithubRepository{}
+		if err := c.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, repo); err != nil {
+			return "", fmt.Errorf("failed to get GithubRepository %s: %w", ref.Name, err)
+		}
+		spec = &repo.Spec
+	}
+
+	if spec.Provider != "" {
+		provider = spec.Provider
+	}
+	name, err := RepositorySpecName(spec, provider)
+	if err != nil {
+		return "", fmt.Errorf("repository %s: %w", ref.Name, err)
+	}
+	return name, nil
+}
+
+// RepositoryAPIBaseURL returns spec.apiBaseURL of a repository on provider, or the default of provider when it is
+// not set, see DefaultAPIBaseURLs. An empty provider is GitHub.
+func RepositoryAPIBaseURL(spec *trainingv1alpha1.GithubRepositorySpec, provider string) (string, error) {
+	if spec.APIBaseURL != "" {
+		return spec.APIBaseURL, nil
+	}
+	if provider == "" {
+		provider = defaultProvider
+	}
+	if baseURL, ok := DefaultAPIBaseURLs[provider]; ok {
+		return baseURL, nil
+	}
+	return "", fmt.Errorf("%w: apiBaseURL must be set for %s repositories", ErrInvalidRepository, provider)
+}
+
+// RepositorySpecName returns the name policies match a GithubRepository or ClusterGithubRepository on provider by,
+// see RepositoryName
+func RepositorySpecName(spec *trainingv1alpha1.GithubRepositorySpec, provider string) (string, error) {
+	baseURL, err := RepositoryAPIBaseURL(spec, provider)
+	if err != nil {
+		return "", err
+	}
+	parsed, err := url.Parse(baseURL)
+	if err != nil || parsed.Host == "" {
+		return "", fmt.Errorf("%w: apiBaseURL %q has no host", ErrInvalidRepository, baseURL)
+	}
+	// Jira projects have no owner and are matched by their key
+	fullName := spec.Name
+	if spec.Owner != "" {
+		fullName = spec.Owner + "/" + spec.Name
+	}
+	return strings.ToLower(parsed.Host) + "/" + fullName, nil
+}
+
+// GithubIssueRepositoryName returns the name policies match the repository a GithubIssue files into by
+func GithubIssueRepositoryName(ctx context.Context, c client.Reader, ghi *trainingv1alpha1.GithubIssue) (string, error) {
+	return ResolveRepositoryName(ctx, c, ghi.Namespace, ghi.Spec.Repo, ghi.Spec.RepositoryRef, ghi.Spec.Provider)
+}
+
+// GithubPullRequestRepositoryName returns the name policies match the repository a GithubPullRequest is opened in by
+func GithubPullRequestRepositoryName(ctx context.Context, c client.Reader, pr *trainingv1alpha1.GithubPullRequest) (string, error) {
+	return ResolveRepositoryName(ctx, c, pr.Namespace, pr.Spec.Repo, pr.Spec.RepositoryRef, "")
+}
+
+// CheckRepository returns whether GithubIssues and GithubPullRequests in namespace may use the repository named name, see
+// RepositoryName. When it is not allowed the returned message explains why.
+func CheckRepository(ctx context.Context, c client.Reader, namespace string, name string) (bool, string, error) {
+	policies := &trainingv1alpha1.GithubIssuePolicyList{}
+	if err := c.List(ctx, policies); err != nil {
+		return false, "", fmt.Errorf("failed to list GithubIssuePolicies: %w", err)
+	}
+	if len(policies.Items) == 0 {
+		return true, "", nil
+	}
+
+	ns := &corev1.Namespace{}
+	if err := c.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		return false, "", fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+
+	var applied []string
+	for _, policy := range policies.Items {
+		selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.NamespaceSelector)
+		if err != nil {
+			return false, "", fmt.Errorf("invalid namespaceSelector in GithubIssuePolicy %s: %w", policy.Name, err)
+		}
+		if !selector.Matches(labels.Set(ns.Labels)) {
+			continue
+		}
+		applied = append(applied, policy.Name)
+		if RepositoryAllowed(policy.Spec.AllowedRepositories, name) {
+			return true, "", nil
+		}
+	}
+
+	if len(applied) == 0 {
+		return true, "", nil
+	}
+	return false, fmt.Sprintf("repository %s is not allowed in namespace %s by GithubIssuePolicy %s",
+		name, namespace, strings.Join(applied, ", ")), nil
+}
+
+// NodeRulesAllowed returns whether GithubIssueRules in namespace may target Nodes, which requires a
+// GithubIssuePolicy selecting the namespace with spec.allowNodeRules set
+func NodeRulesAllowed(ctx context.Context, c client.Reader, namespace string) (bool, error) {
+	policies := &trainingv1alpha1.GithubIssuePolicyList{}
+	if err := c.List(ctx, policies); err != nil {
+		return false, fmt.Errorf("failed to list GithubIssuePolicies: %w", err)
+	}
+
+	var ns *corev1.Namespace
+	for _, policy := range policies.Items {
+		if !policy.Spec.AllowNodeRules {
+			continue
+		}
+		if ns == nil {
+			ns = &corev1.Namespace{}
+			if err := c.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+				return false, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+			}
+		}
+		selector, err := metav1.LabelSelectorAsSelector(&policy.Spec.NamespaceSelector)
+		if err != nil {
+			return false, fmt.Errorf("invalid namespaceSelector in GithubIssuePolicy %s: %w", policy.Name, err)
+		}
+		if selector.Matches(labels.Set(ns.Labels)) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// RepositoryAllowed reports whether the repository name, see RepositoryName, matches one of the allowed
+// repository patterns. Patterns without a host name repositories of api.github.com. GitHub names are case
+// insensitive, so the comparison is too.
+func RepositoryAllowed(allowed []string, name string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range allowed {
+		if ok, err := path.Match(strings.ToLower(qualifiedPattern(pattern)), name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// qualifiedPattern prefixes a pattern without a host with api.github.com. The first segment of a pattern is
+// a host when it contains a dot or a port, as owners cannot.
+func qualifiedPattern(pattern string) string {
+	first, _, _ := strings.Cut(pattern, "/")
+	if strings.ContainsAny(first, ".:") {
+		return pattern
+	}
+	return publicGitHubAPIHost + "/" + pattern
+}
@@ -0,0 +1,180 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package policy
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+var _ = Describe("GithubIssuePolicy", func() {
+	ctx := context.Background()
+
+	newClient := func(objs ...client.Object) client.Client {
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(trainingv1alpha1.AddToScheme(scheme)).To(Succeed())
+		return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()
+	}
+
+	namespace := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+
+	githubIssuePolicy := func(name string, matchLabels map[string]string, allowed ...string) *trainingv1alpha1.GithubIssuePolicy {
+		return &trainingv1alpha1.GithubIssuePolicy{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Spec: trainingv1alpha1.GithubIssuePolicySpec{
+				NamespaceSelector:   metav1.LabelSelector{MatchLabels: matchLabels},
+				AllowedRepositories: allowed,
+			},
+		}
+	}
+
+	It("should only allow templates to read the listed kinds", func() {
+		Expect(TemplateObjectAllowed("v1", "Pod")).To(BeTrue())
+		Expect(TemplateObjectAllowed("apps/v1", "Deployment")).To(BeTrue())
+		Expect(TemplateObjectAllowed("v1", "Secret")).To(BeFalse())
+		Expect(TemplateObjectAllowed("example.com/v1", "Pod")).To(BeFalse())
+		Expect(TemplateObjectAllowed("v1", "Deployment")).To(BeFalse())
+	})
+
+	It("should parse the repository full name from an API URL", func() {
+		fullName, err := RepositoryFullName("https://api.github.com/repos/owner/name")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fullName).To(Equal("owner/name"))
+
+		fullName, err = RepositoryFullName("https://github.example.com/api/v3/repos/owner/name/")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fullName).To(Equal("owner/name"))
+
+		_, err = RepositoryFullName("https://api.github.com/owner/name")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should name a repository by the host and the full name of its API URL", func() {
+		name, err := RepositoryName("https://API.github.com/repos/owner/name")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("api.github.com/owner/name"))
+
+		name, err = RepositoryName("https://github.example.com/api/v3/repos/owner/name")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("github.example.com/owner/name"))
+
+		name, err = RepositoryName("https://gitlab.example.com/api/v4/projects/group%2Fsub%2Fproject")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("gitlab.example.com/group/sub/project"))
+
+		name, err = RepositoryName("https://jira.example.com/rest/api/2/project/OPS")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("jira.example.com/OPS"))
+
+		_, err = RepositoryName("repos/owner/name")
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("should match repository patterns case insensitively", func() {
+		Expect(RepositoryAllowed([]string{"Owner/*"}, "api.github.com/owner/name")).To(BeTrue())
+		Expect(RepositoryAllowed([]string{"owner/name"}, "api.github.com/Owner/Name")).To(BeTrue())
+		Expect(RepositoryAllowed([]string{"owner/other"}, "api.github.com/owner/name")).To(BeFalse())
+		Expect(RepositoryAllowed(nil, "api.github.com/owner/name")).To(BeFalse())
+	})
+
+	It("should only match patterns without a host against api.github.com", func() {
+		Expect(RepositoryAllowed([]string{"owner/*"}, "attacker.example/owner/name")).To(BeFalse())
+		Expect(RepositoryAllowed([]string{"github.example.com/owner/*"}, "github.example.com/owner/name")).To(BeTrue())
+		Expect(RepositoryAllowed([]string{"github.example.com/owner/*"}, "api.github.com/owner/name")).To(BeFalse())
+		Expect(RepositoryAllowed([]string{"localhost:8080/owner/name"}, "localhost:8080/owner/name")).To(BeTrue())
+	})
+
+	It("should allow every repository when no policy selects the namespace", func() {
+		c := newClient(namespace("team-b", map[string]string{"team": "b"}),
+			githubIssuePolicy("team-a", map[string]string{"team": "a"}, "owner/a"))
+
+		allowed, _, err := CheckRepository(ctx, c, "team-b", "api.github.com/owner/anything")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+	})
+
+	It("should allow a repository allowed by any selecting policy", func() {
+		c := newClient(namespace("team-a", map[string]string{"team": "a"}),
+			githubIssuePolicy("team-a", map[string]string{"team": "a"}, "owner/a"),
+			githubIssuePolicy("shared", nil, "owner/shared-*"))
+
+		allowed, _, err := CheckRepository(ctx, c, "team-a", "api.github.com/owner/shared-docs")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allowed).To(BeTrue())
+
+		allowed, message, err := CheckRepository(ctx, c, "team-a", "api.github.com/owner/b")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(allowed).To(BeFalse())
+		Expect(message).To(ContainSubstring("team-a"))
+		Expect(message).To(ContainSubstring("shared"))
+	})
+
+	It("should resolve the repository of a repositoryRef", func() {
+		c := newClient(&trainingv1alpha1.ClusterGithubRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+			Spec:       trainingv1alpha1.GithubRepositorySpec{Owner: "owner", Name: "shared"},
+		})
+		ghi := &trainingv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"},
+			Spec: trainingv1alpha1.GithubIssueSpec{
+				RepositoryRef: &trainingv1alpha1.RepositoryReference{Kind: "ClusterGithubRepository", Name: "shared"},
+			},
+		}
+
+		name, err := GithubIssueRepositoryName(ctx, c, ghi)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("api.github.com/owner/shared"))
+	})
+
+	It("should name repositories without apiBaseURL by the host of their provider", func() {
+		c := newClient(&trainingv1alpha1.ClusterGithubRepository{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared"},
+			Spec:       trainingv1alpha1.GithubRepositorySpec{Owner: "group", Name: "shared"},
+		})
+		ghi := &trainingv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "default"},
+			Spec: trainingv1alpha1.GithubIssueSpec{
+				Provider:      "GitLab",
+				RepositoryRef: &trainingv1alpha1.RepositoryReference{Kind: "ClusterGithubRepository", Name: "shared"},
+			},
+		}
+
+		name, err := GithubIssueRepositoryName(ctx, c, ghi)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("gitlab.com/group/shared"))
+
+		_, err = RepositorySpecName(&trainingv1alpha1.GithubRepositorySpec{Name: "OPS"}, "Jira")
+		Expect(err).To(MatchError(ErrInvalidRepository))
+		name, err = RepositorySpecName(&trainingv1alpha1.GithubRepositorySpec{
+			Name: "OPS", APIBaseURL: "https://example.atlassian.net"}, "Jira")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(name).To(Equal("example.atlassian.net/OPS"))
+	})
+})
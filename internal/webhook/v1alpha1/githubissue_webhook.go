@@ -0,0 +1,259 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	"Shai1-Levi/githubissues-operator.git/internal/policy"
+)
+
+// log is for logging in this package.
+var githubissuelog = logf.Log.WithName("githubissue-resource")
+
+// SetupGithubIssueWebhookWithManager registers the webhook for GithubIssue in the manager.
+func SetupGithubIssueWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&trainingv1alpha1.GithubIssue{}).
+		WithValidator(&GithubIssueCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-training-redhat-com-v1alpha1-githubissue,mutating=false,failurePolicy=fail,sideEffects=None,groups=training.redhat.com,resources=githubissues,verbs=create;update,versions=v1alpha1,name=vgithubissue-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// GithubIssueCustomValidator rejects GithubIssues filing into a repository forbidden by a GithubIssuePolicy,
+// GithubIssues whose template references an object of a kind templates may not read, GithubIssues using fields
+// their provider does not support, and GithubIssues whose spec.parent would make them their own ancestor.
+type GithubIssueCustomValidator struct {
+	Client client.Reader
+}
+
+var _ webhook.CustomValidator = &GithubIssueCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type GithubIssue.
+func (v *GithubIssueCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	githubissue, ok := obj.(*trainingv1alpha1.GithubIssue)
+	if !ok {
+		return nil, fmt.Errorf("expected a GithubIssue object but got %T", obj)
+	}
+	githubissuelog.Info("Validation for GithubIssue upon creation", "name", githubissue.GetName())
+
+	if err := v.validateRepository(ctx, githubissue); err != nil {
+		return nil, err
+	}
+	if err := validateTemplate(githubissue); err != nil {
+		return nil, err
+	}
+	if err := v.validateProvider(ctx, githubissue); err != nil {
+		return nil, err
+	}
+	return nil, v.validateParent(ctx, githubissue)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type GithubIssue.
+func (v *GithubIssueCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	githubissue, ok := newObj.(*trainingv1alpha1.GithubIssue)
+	if !ok {
+		return nil, fmt.Errorf("expected a GithubIssue object for the newObj but got %T", newObj)
+	}
+	githubissuelog.Info("Validation for GithubIssue upon update", "name", githubissue.GetName())
+
+	// Objects being deleted must stay updatable so the finalizer can be removed
+	if !githubissue.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	if err := v.validateRepository(ctx, githubissue); err != nil {
+		return nil, err
+	}
+	if err := validateTemplate(githubissue); err != nil {
+		return nil, err
+	}
+	if err := v.validateProvider(ctx, githubissue); err != nil {
+		return nil, err
+	}
+	return nil, v.validateParent(ctx, githubissue)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type GithubIssue.
+func (v *GithubIssueCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateRepository returns a Forbidden error when the GithubIssuePolicies selecting the namespace do not allow
+// the repository of the GithubIssue
+func (v *GithubIssueCustomValidator) validateRepository(ctx context.Context, githubissue *trainingv1alpha1.GithubIssue) error {
+	return validateRepositoryPolicy(ctx, v.Client, "githubissues", githubissue, githubissue.Spec.Repo, githubissue.Spec.RepositoryRef,
+		githubissue.Spec.Provider)
+}
+
+// validateTemplate returns an Invalid error when spec.template.objectRef references a kind templates may not read,
+// such as Secrets
+func validateTemplate(githubissue *trainingv1alpha1.GithubIssue) error {
+	if githubissue.Spec.Template == nil || githubissue.Spec.Template.ObjectRef == nil {
+		return nil
+	}
+	ref := githubissue.Spec.Template.ObjectRef
+	if policy.TemplateObjectAllowed(ref.APIVersion, ref.Kind) {
+		return nil
+	}
+
+	supported := make([]string, 0, len(policy.TemplateObjectKinds))
+	for _, gk := range policy.TemplateObjectKinds {
+		supported = append(supported, gk.String())
+	}
+	return apierrors.NewInvalid(trainingv1alpha1.GroupVersion.WithKind("GithubIssue").GroupKind(), githubissue.Name,
+		field.ErrorList{field.NotSupported(field.NewPath("spec", "template", "objectRef", "kind"), ref.Kind, supported)})
+}
+
+// validateProvider returns an Invalid error when a GithubIssue filed outside of GitHub sets a field only GitHub
+// issues support. The provider of a referenced repository takes precedence over spec.provider, a repository that
+// does not exist yet is checked by its spec.provider only.
+func (v *GithubIssueCustomValidator) validateProvider(ctx context.Context, githubissue *trainingv1alpha1.GithubIssue) error {
+	provider := githubissue.Spec.Provider
+	if ref := githubissue.Spec.RepositoryRef; ref != nil {
+		var repo client.Object = &trainingv1alpha1.GithubRepository{}
+		key := types.NamespacedName{Name: ref.Name, Namespace: githubissue.Namespace}
+		if ref.Kind == "ClusterGithubRepository" {
+			repo, key.Namespace = &trainingv1alpha1.ClusterGithubRepository{}, ""
+		}
+		if err := v.Client.Get(ctx, key, repo); client.IgnoreNotFound(err) != nil {
+			return apierrors.NewInternalError(err)
+		}
+		switch repo := repo.(type) {
+		case *trainingv1alpha1.GithubRepository:
+			if repo.Spec.Provider != "" {
+				provider = repo.Spec.Provider
+			}
+		case *trainingv1alpha1.ClusterGithubRepository:
+			if repo.Spec.Provider != "" {
+				provider = repo.Spec.Provider
+			}
+		}
+	}
+	if provider == "" || provider == "GitHub" {
+		return nil
+	}
+
+	spec := field.NewPath("spec")
+	detail := fmt.Sprintf("only supported for GitHub, the issue is filed in %s", provider)
+	var errs field.ErrorList
+	if len(githubissue.Spec.Comments) > 0 {
+		errs = append(errs, field.Forbidden(spec.Child("comments"), detail))
+	}
+	if githubissue.Spec.CommentMirror != nil {
+		errs = append(errs, field.Forbidden(spec.Child("commentMirror"), detail))
+	}
+	if target := githubissue.Spec.TargetRef; target != nil && target.AutoClose != "" && target.AutoClose != "Never" {
+		errs = append(errs, field.Forbidden(spec.Child("targetRef", "autoClose"), detail))
+	}
+	if githubissue.Spec.Deduplication != nil {
+		errs = append(errs, field.Forbidden(spec.Child("deduplication"), detail))
+	}
+	if githubissue.Spec.Project != nil {
+		errs = append(errs, field.Forbidden(spec.Child("project"), detail))
+	}
+	if githubissue.Spec.Parent != nil {
+		errs = append(errs, field.Forbidden(spec.Child("parent"), detail))
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return apierrors.NewInvalid(trainingv1alpha1.GroupVersion.WithKind("GithubIssue").GroupKind(), githubissue.Name, errs)
+}
+
+// maxParentDepth bounds the spec.parent chain walked by validateParent, GitHub nests sub-issues at most eight
+// levels deep
+const maxParentDepth = 8
+
+// validateParent returns an Invalid error when spec.parent references the GithubIssue itself, directly or through
+// the parents of its parent, or starts a chain of more than maxParentDepth parents. A parent that does not exist
+// yet is reported by the reconciler.
+func (v *GithubIssueCustomValidator) validateParent(ctx context.Context, githubissue *trainingv1alpha1.GithubIssue) error {
+	parentRef := githubissue.Spec.Parent
+	if parentRef == nil {
+		return nil
+	}
+	fieldPath := field.NewPath("spec", "parent", "name")
+	invalid := func(detail string) error {
+		return apierrors.NewInvalid(trainingv1alpha1.GroupVersion.WithKind("GithubIssue").GroupKind(), githubissue.Name,
+			field.ErrorList{field.Invalid(fieldPath, parentRef.Name, detail)})
+	}
+
+	if parentRef.Name == githubissue.Name {
+		return invalid("a GithubIssue cannot be its own parent")
+	}
+	name := parentRef.Name
+	for depth := 1; depth <= maxParentDepth; depth++ {
+		parent := &trainingv1alpha1.GithubIssue{}
+		if err := v.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: githubissue.Namespace}, parent); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return apierrors.NewInternalError(err)
+		}
+		if parent.Spec.Parent == nil {
+			return nil
+		}
+		if parent.Spec.Parent.Name == githubissue.Name {
+			return invalid(fmt.Sprintf("GithubIssue %s is a descendant of %s already", parent.Name, githubissue.Name))
+		}
+		name = parent.Spec.Parent.Name
+	}
+	return invalid(fmt.Sprintf("the parent chain is deeper than %d GithubIssues", maxParentDepth))
+}
+
+// validateRepositoryPolicy returns a Forbidden error when the GithubIssuePolicies selecting the namespace of obj do
+// not allow its repository, given by repo, ref and provider as in its spec. resource is the plural name of the kind
+// of obj.
+func validateRepositoryPolicy(ctx context.Context, c client.Reader, resource string, obj client.Object, repo string,
+	ref *trainingv1alpha1.RepositoryReference, provider string) error {
+	fieldPath := field.NewPath("spec", "repo")
+	if ref != nil {
+		fieldPath = field.NewPath("spec", "repositoryRef")
+	}
+
+	name, err := policy.ResolveRepositoryName(ctx, c, obj.GetNamespace(), repo, ref, provider)
+	if err != nil {
+		// A missing or invalid GithubRepository is reported by the reconciler, the policy is enforced again there
+		if apierrors.IsNotFound(err) || errors.Is(err, policy.ErrInvalidRepository) {
+			return nil
+		}
+		return apierrors.NewInternalError(err)
+	}
+
+	allowed, message, err := policy.CheckRepository(ctx, c, obj.GetNamespace(), name)
+	if err != nil {
+		return apierrors.NewInternalError(err)
+	}
+	if !allowed {
+		return apierrors.NewForbidden(trainingv1alpha1.GroupVersion.WithResource(resource).GroupResource(),
+			obj.GetName(), field.Forbidden(fieldPath, message))
+	}
+	return nil
+}
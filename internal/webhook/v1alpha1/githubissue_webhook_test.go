@@ -0,0 +1,304 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+	trainingv1beta1 "Shai1-Levi/githubissues-operator.git/api/v1beta1"
+)
+
+var _ = Describe("GithubIssue Webhook", func() {
+	var (
+		ctx       context.Context
+		validator GithubIssueCustomValidator
+		obj       *trainingv1alpha1.GithubIssue
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(trainingv1alpha1.AddToScheme(scheme)).To(Succeed())
+		validator = GithubIssueCustomValidator{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}}},
+				&trainingv1alpha1.GithubIssuePolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+					Spec: trainingv1alpha1.GithubIssuePolicySpec{
+						NamespaceSelector:   metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+						AllowedRepositories: []string{"owner/allowed", "gitlab.example.com/group/*", "jira.example.com/OPS"},
+					},
+				},
+			).Build(),
+		}
+
+		obj = &trainingv1alpha1.GithubIssue{
+			ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "team-a"},
+			Spec:       trainingv1alpha1.GithubIssueSpec{Repo: "https://api.github.com/repos/owner/allowed", Title: "title"},
+		}
+	})
+
+	Context("When creating or updating GithubIssue under Validating Webhook", func() {
+		It("Should admit creation if the repository is allowed", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny creation if the repository is not allowed", func() {
+			obj.Spec.Repo = "https://api.github.com/repos/owner/other"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		})
+
+		It("Should deny an allowed repository name on another host", func() {
+			obj.Spec.Repo = "https://attacker.example/repos/owner/allowed"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		})
+
+		It("Should match a GitLab project by its unescaped path", func() {
+			obj.Spec.Provider = "GitLab"
+			obj.Spec.Repo = "https://gitlab.example.com/api/v4/projects/group%2Fproject"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.Repo = "https://gitlab.example.com/api/v4/projects/other%2Fproject"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		})
+
+		It("Should match a Jira project by its key", func() {
+			obj.Spec.Provider = "Jira"
+			obj.Spec.Repo = "https://jira.example.com/rest/api/2/project/OPS"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+
+			obj.Spec.Repo = "https://jira.example.com/rest/api/2/project/DEV"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		})
+
+		It("Should deny moving a GithubIssue to a repository that is not allowed", func() {
+			oldObj := obj.DeepCopy()
+			obj.Spec.Repo = "https://api.github.com/repos/owner/other"
+			_, err := validator.ValidateUpdate(ctx, oldObj, obj)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		})
+
+		It("Should deny a template reading a Secret", func() {
+			obj.Spec.Template = &trainingv1alpha1.IssueTemplateSpec{
+				Body:      "{{ .Object.data }}",
+				ObjectRef: &trainingv1alpha1.ObjectReference{APIVersion: "v1", Kind: "Secret", Name: "token"},
+			}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.template.objectRef.kind"))
+
+			By("admitting a template reading a Pod")
+			obj.Spec.Template.ObjectRef.Kind = "Pod"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny fields only GitHub supports on other providers", func() {
+			obj.Spec.Provider = "GitLab"
+			obj.Spec.Repo = "https://gitlab.example.com/api/v4/projects/group%2Fproject"
+			obj.Spec.Comments = []trainingv1alpha1.GithubIssueComment{{Name: "note", Body: "body"}}
+			obj.Spec.Deduplication = &trainingv1alpha1.DeduplicationSpec{}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.comments"))
+			Expect(err.Error()).To(ContainSubstring("spec.deduplication"))
+
+			By("taking the provider of the referenced repository")
+			scheme := runtime.NewScheme()
+			Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+			Expect(trainingv1alpha1.AddToScheme(scheme)).To(Succeed())
+			validator.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: obj.Namespace}},
+				&trainingv1alpha1.ClusterGithubRepository{
+					ObjectMeta: metav1.ObjectMeta{Name: "ops"},
+					Spec: trainingv1alpha1.GithubRepositorySpec{
+						Provider: "Jira", Name: "OPS", APIBaseURL: "https://jira.example.com"},
+				},
+			).Build()
+			obj = &trainingv1alpha1.GithubIssue{
+				ObjectMeta: metav1.ObjectMeta{Name: "issue", Namespace: "team-a"},
+				Spec: trainingv1alpha1.GithubIssueSpec{
+					RepositoryRef: &trainingv1alpha1.RepositoryReference{Kind: "ClusterGithubRepository", Name: "ops"},
+					Title:         "title",
+					Parent:        &trainingv1alpha1.ParentReference{Name: "epic"},
+				},
+			}
+			_, err = validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("spec.parent"))
+
+			obj.Spec.Parent = nil
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny a GithubIssue that is its own parent", func() {
+			obj.Spec.Parent = &trainingv1alpha1.ParentReference{Name: obj.Name}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("its own parent"))
+		})
+
+		It("Should deny a GithubIssue whose parent is its child", func() {
+			scheme := runtime.NewScheme()
+			Expect(trainingv1alpha1.AddToScheme(scheme)).To(Succeed())
+			validator.Client = fake.NewClientBuilder().WithScheme(scheme).WithObjects(&trainingv1alpha1.GithubIssue{
+				ObjectMeta: metav1.ObjectMeta{Name: "child", Namespace: obj.Namespace},
+				Spec:       trainingv1alpha1.GithubIssueSpec{Title: "child", Parent: &trainingv1alpha1.ParentReference{Name: obj.Name}},
+			}).Build()
+
+			newObj := obj.DeepCopy()
+			newObj.Spec.Parent = &trainingv1alpha1.ParentReference{Name: "child"}
+			_, err := validator.ValidateUpdate(ctx, obj, newObj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+
+			By("admitting a parent that does not exist yet")
+			newObj.Spec.Parent.Name = "missing"
+			Expect(validator.ValidateUpdate(ctx, obj, newObj)).To(BeNil())
+		})
+
+		It("Should deny a GithubIssue whose parent descends from it", func() {
+			scheme := runtime.NewScheme()
+			Expect(trainingv1alpha1.AddToScheme(scheme)).To(Succeed())
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			// issue <- b <- c, so making c the parent of issue closes the cycle issue -> c -> b -> issue
+			for name, parent := range map[string]string{"b": obj.Name, "c": "b"} {
+				builder = builder.WithObjects(&trainingv1alpha1.GithubIssue{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: obj.Namespace},
+					Spec:       trainingv1alpha1.GithubIssueSpec{Title: name, Parent: &trainingv1alpha1.ParentReference{Name: parent}},
+				})
+			}
+			validator.Client = builder.Build()
+
+			newObj := obj.DeepCopy()
+			newObj.Spec.Parent = &trainingv1alpha1.ParentReference{Name: "c"}
+			_, err := validator.ValidateUpdate(ctx, obj, newObj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("descendant"))
+		})
+
+		It("Should deny a parent chain deeper than the bound", func() {
+			scheme := runtime.NewScheme()
+			Expect(trainingv1alpha1.AddToScheme(scheme)).To(Succeed())
+			builder := fake.NewClientBuilder().WithScheme(scheme)
+			// level-0 -> level-1 -> ... -> level-maxParentDepth, which has no parent
+			for i := 0; i <= maxParentDepth; i++ {
+				level := &trainingv1alpha1.GithubIssue{
+					ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("level-%d", i), Namespace: obj.Namespace},
+					Spec:       trainingv1alpha1.GithubIssueSpec{Title: "level"},
+				}
+				if i < maxParentDepth {
+					level.Spec.Parent = &trainingv1alpha1.ParentReference{Name: fmt.Sprintf("level-%d", i+1)}
+				}
+				builder = builder.WithObjects(level)
+			}
+			validator.Client = builder.Build()
+
+			obj.Spec.Parent = &trainingv1alpha1.ParentReference{Name: "level-0"}
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsInvalid(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("deeper than"))
+
+			By("admitting a chain within the bound")
+			obj.Spec.Parent.Name = "level-1"
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should admit updates of a GithubIssue being deleted", func() {
+			obj.Spec.Repo = "https://api.github.com/repos/owner/other"
+			now := metav1.Now()
+			obj.DeletionTimestamp = &now
+			Expect(validator.ValidateUpdate(ctx, obj.DeepCopy(), obj)).To(BeNil())
+		})
+	})
+
+	Context("When converting GithubIssue under Conversion Webhook", func() {
+		It("Should register v1alpha1 as the hub of v1beta1", func() {
+			scheme := runtime.NewScheme()
+			Expect(trainingv1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(trainingv1beta1.AddToScheme(scheme)).To(Succeed())
+			Expect(conversion.IsConvertible(scheme, obj)).To(BeTrue())
+		})
+
+		It("Should convert to v1beta1 and back without losing fields", func() {
+			obj.Spec = trainingv1alpha1.GithubIssueSpec{
+				Provider:      "GitLab",
+				RepositoryRef: &trainingv1alpha1.RepositoryReference{Kind: "GithubRepository", Name: "repo"},
+				Title:         "title",
+				Description:   "body",
+				Labels:        []string{"bug"},
+				State:         "Closed",
+				Comments:      []trainingv1alpha1.GithubIssueComment{{Name: "note", Body: "comment"}},
+				Deduplication: &trainingv1alpha1.DeduplicationSpec{Fingerprint: "fingerprint", Occurrences: "Count"},
+				Project: &trainingv1alpha1.ProjectSpec{
+					Owner: "org", Number: 1, Fields: []trainingv1alpha1.ProjectFieldValue{{Name: "Status", Value: "Todo"}},
+				},
+				Parent: &trainingv1alpha1.ParentReference{Name: "incident", Link: "TaskList"},
+				TargetRef: &trainingv1alpha1.TargetReference{
+					ObjectReference: trainingv1alpha1.ObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Name: "app"},
+					AutoClose:       "Healthy",
+				},
+				Mode:    "CreateOnly",
+				Suspend: true,
+			}
+			obj.Status = trainingv1alpha1.GithubIssueStatus{
+				IssueNumber:   12,
+				Occurrences:   2,
+				ProjectItemID: "item",
+				State:         "closed",
+				Children:      &trainingv1alpha1.ChildIssuesStatus{Total: 3, Closed: 1, Progress: "1/3"},
+				Conditions:    []metav1.Condition{{Type: "Synced", Status: metav1.ConditionTrue, Reason: "Synced"}},
+			}
+
+			spoke := &trainingv1beta1.GithubIssue{}
+			Expect(spoke.ConvertFrom(obj)).To(Succeed())
+			Expect(spoke.Spec.Tracker.Provider).To(Equal("GitLab"))
+			Expect(spoke.Spec.Tracker.RepositoryRef.Name).To(Equal("repo"))
+			Expect(spoke.Spec.Body).To(Equal("body"))
+			Expect(spoke.Spec.GitHub.Comments).To(HaveLen(1))
+			Expect(spoke.Spec.GitHub.Parent.Name).To(Equal("incident"))
+			Expect(spoke.Status.IssueID).To(Equal("12"))
+
+			hub := &trainingv1alpha1.GithubIssue{}
+			Expect(spoke.ConvertTo(hub)).To(Succeed())
+			Expect(hub.ObjectMeta).To(Equal(obj.ObjectMeta))
+			Expect(hub.Spec).To(Equal(obj.Spec))
+			Expect(hub.Status).To(Equal(obj.Status))
+		})
+
+		It("Should reject an issue ID the hub cannot store", func() {
+			spoke := &trainingv1beta1.GithubIssue{Status: trainingv1beta1.GithubIssueStatus{IssueID: "OPS-1"}}
+			Expect(spoke.ConvertTo(&trainingv1alpha1.GithubIssue{})).NotTo(Succeed())
+		})
+	})
+})
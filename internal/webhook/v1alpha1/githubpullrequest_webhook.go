@@ -0,0 +1,83 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+// log is for logging in this package.
+var githubpullrequestlog = logf.Log.WithName("githubpullrequest-resource")
+
+// SetupGithubPullRequestWebhookWithManager registers the webhook for GithubPullRequest in the manager.
+func SetupGithubPullRequestWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&trainingv1alpha1.GithubPullRequest{}).
+		WithValidator(&GithubPullRequestCustomValidator{Client: mgr.GetClient()}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-training-redhat-com-v1alpha1-githubpullrequest,mutating=false,failurePolicy=fail,sideEffects=None,groups=training.redhat.com,resources=githubpullrequests,verbs=create;update,versions=v1alpha1,name=vgithubpullrequest-v1alpha1.kb.io,admissionReviewVersions=v1
+
+// GithubPullRequestCustomValidator rejects GithubPullRequests opened in a repository forbidden by a GithubIssuePolicy.
+type GithubPullRequestCustomValidator struct {
+	Client client.Reader
+}
+
+var _ webhook.CustomValidator = &GithubPullRequestCustomValidator{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type GithubPullRequest.
+func (v *GithubPullRequestCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	githubpullrequest, ok := obj.(*trainingv1alpha1.GithubPullRequest)
+	if !ok {
+		return nil, fmt.Errorf("expected a GithubPullRequest object but got %T", obj)
+	}
+	githubpullrequestlog.Info("Validation for GithubPullRequest upon creation", "name", githubpullrequest.GetName())
+
+	return nil, validateRepositoryPolicy(ctx, v.Client, "githubpullrequests", githubpullrequest,
+		githubpullrequest.Spec.Repo, githubpullrequest.Spec.RepositoryRef, "")
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type GithubPullRequest.
+func (v *GithubPullRequestCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	githubpullrequest, ok := newObj.(*trainingv1alpha1.GithubPullRequest)
+	if !ok {
+		return nil, fmt.Errorf("expected a GithubPullRequest object for the newObj but got %T", newObj)
+	}
+	githubpullrequestlog.Info("Validation for GithubPullRequest upon update", "name", githubpullrequest.GetName())
+
+	// Objects being deleted must stay updatable so the finalizer can be removed
+	if !githubpullrequest.DeletionTimestamp.IsZero() {
+		return nil, nil
+	}
+	return nil, validateRepositoryPolicy(ctx, v.Client, "githubpullrequests", githubpullrequest,
+		githubpullrequest.Spec.Repo, githubpullrequest.Spec.RepositoryRef, "")
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type GithubPullRequest.
+func (v *GithubPullRequestCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
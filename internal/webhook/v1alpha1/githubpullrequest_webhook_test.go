@@ -0,0 +1,94 @@
+/*
+Copyright 2025.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	trainingv1alpha1 "Shai1-Levi/githubissues-operator.git/api/v1alpha1"
+)
+
+var _ = Describe("GithubPullRequest Webhook", func() {
+	var (
+		ctx       context.Context
+		validator GithubPullRequestCustomValidator
+		obj       *trainingv1alpha1.GithubPullRequest
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		scheme := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+		Expect(trainingv1alpha1.AddToScheme(scheme)).To(Succeed())
+		validator = GithubPullRequestCustomValidator{
+			Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "team-a", Labels: map[string]string{"team": "a"}}},
+				&trainingv1alpha1.GithubIssuePolicy{
+					ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+					Spec: trainingv1alpha1.GithubIssuePolicySpec{
+						NamespaceSelector:   metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+						AllowedRepositories: []string{"owner/allowed"},
+					},
+				},
+			).Build(),
+		}
+
+		obj = &trainingv1alpha1.GithubPullRequest{
+			ObjectMeta: metav1.ObjectMeta{Name: "pull-request", Namespace: "team-a"},
+			Spec: trainingv1alpha1.GithubPullRequestSpec{
+				Repo: "https://api.github.com/repos/owner/allowed", Head: "feature", Base: "main", Title: "title",
+			},
+		}
+	})
+
+	Context("When creating or updating GithubPullRequest under Validating Webhook", func() {
+		It("Should admit creation if the repository is allowed", func() {
+			Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
+		})
+
+		It("Should deny creation if the repository is not allowed", func() {
+			obj.Spec.Repo = "https://api.github.com/repos/owner/other"
+			_, err := validator.ValidateCreate(ctx, obj)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+			Expect(err.Error()).To(ContainSubstring("team-a"))
+		})
+
+		It("Should deny moving a GithubPullRequest to a repository that is not allowed", func() {
+			newObj := obj.DeepCopy()
+			newObj.Spec.Repo = "https://api.github.com/repos/owner/other"
+			_, err := validator.ValidateUpdate(ctx, obj, newObj)
+			Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		})
+
+		It("Should admit updates of a GithubPullRequest being deleted", func() {
+			obj.Spec.Repo = "https://api.github.com/repos/owner/other"
+			now := metav1.Now()
+			obj.DeletionTimestamp = &now
+			Expect(validator.ValidateUpdate(ctx, obj, obj)).To(BeNil())
+		})
+	})
+})